@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/DataInCube/hackathon-service/api/middlewares"
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AccessHandler struct {
+	Service    *services.AccessService
+	Governance *services.GovernanceService
+}
+
+func NewAccessHandler(service *services.AccessService, governance *services.GovernanceService) *AccessHandler {
+	return &AccessHandler{Service: service, Governance: governance}
+}
+
+const (
+	hackathonIDContextKey = "hackathon_id"
+	staffRolesContextKey  = "hackathon_staff_roles"
+)
+
+// RequireVisible answers 404 on hackathon-scoped routes when the entity of
+// the route does not exist or the caller may not see its hackathon, so
+// private hackathons do not leak their existence, and 400 when the id is
+// malformed. It then stores the hackathon and the caller's staff roles on it
+// for RequireStaff and isAdminOrStaff. Routes without a hackathon-scoped
+// parameter pass through.
+func (h *AccessHandler) RequireVisible(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		param, id := hackathonScopeParam(c)
+		if param == "" {
+			return next(c)
+		}
+		if _, err := uuid.Parse(id); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+		}
+		access, err := h.Service.ResolveAccess(c.Request().Context(), param, id, viewerFromContext(c))
+		if err != nil {
+			return handleServiceError(err)
+		}
+		if !access.Visible {
+			return echo.NewHTTPError(http.StatusNotFound, "hackathon not found")
+		}
+		c.Set(hackathonIDContextKey, access.HackathonID)
+		c.Set(staffRolesContextKey, access.StaffRoles)
+		return next(c)
+	}
+}
+
+// hackathonScopeParam returns the first hackathon-scoped route parameter
+// and its raw value.
+func hackathonScopeParam(c echo.Context) (string, string) {
+	for _, param := range services.HackathonScopeParams {
+		if raw := c.Param(param); raw != "" {
+			return param, raw
+		}
+	}
+	return "", ""
+}
+
+func viewerFromContext(c echo.Context) services.Viewer {
+	return services.Viewer{
+		UserID:       actorIDFromContext(c),
+		Unrestricted: hasAnyRole(middlewares.RolesFromContext(c), "hackathon_admin", "platform_admin", "evaluation_executor"),
+	}
+}
+
+func (h *AccessHandler) ListMembers(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	members, err := h.Service.ListMembers(c.Request().Context(), hackathonID, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, members)
+}
+
+func (h *AccessHandler) AddMembers(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	added, err := h.Service.AddMembers(c.Request().Context(), hackathonID, input.UserIDs, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.members.added", added)
+	return c.JSON(http.StatusCreated, map[string]any{"added": added})
+}
+
+func (h *AccessHandler) RemoveMember(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	userID := strings.TrimSpace(c.Param("userId"))
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "missing userId")
+	}
+	if err := h.Service.RemoveMember(c.Request().Context(), hackathonID, userID); err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.member.removed", map[string]string{"user_id": userID})
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *AccessHandler) CreateInvite(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.InviteInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	invite, err := h.Service.CreateInvite(c.Request().Context(), hackathonID, input, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.invite.created", map[string]any{
+		"invite_id":  invite.ID,
+		"max_uses":   invite.MaxUses,
+		"expires_at": invite.ExpiresAt,
+	})
+	return c.JSON(http.StatusCreated, invite)
+}
+
+func (h *AccessHandler) ListInvites(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	invites, err := h.Service.ListInvites(c.Request().Context(), hackathonID, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, invites)
+}
+
+func (h *AccessHandler) RevokeInvite(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	inviteID, err := parseUUIDParam(c, "inviteId")
+	if err != nil {
+		return err
+	}
+	invite, err := h.Service.RevokeInvite(c.Request().Context(), hackathonID, inviteID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.invite.revoked", map[string]string{"invite_id": invite.ID})
+	return c.JSON(http.StatusOK, invite)
+}
+
+func (h *AccessHandler) RedeemInvite(c echo.Context) error {
+	var input struct {
+		Code string `json:"code"`
+	}
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	member, err := h.Service.RedeemInvite(c.Request().Context(), input.Code, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, member.HackathonID, actorID, "hackathon.invite.redeemed", member)
+	return c.JSON(http.StatusOK, member)
+}
+
+func (h *AccessHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
+	if h.Governance == nil {
+		return
+	}
+	raw, _ := json.Marshal(payload)
+	_ = h.Governance.AppendAudit(c.Request().Context(), models.AuditLog{
+		HackathonID: hackathonID,
+		ActorID:     actorID,
+		Action:      action,
+		Payload:     raw,
+	})
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHackathonScopeParam(t *testing.T) {
+	hackathonID := "5f3de833-5f0b-4675-b0b6-7e9ef32ed500"
+	ruleVersionID := "0b6c7d6e-1f6a-4c43-9d0b-2a3b4c5d6e7f"
+
+	c := newHandlerContext(http.MethodPost, "/")
+	c.SetParamNames("ruleVersionId", "hackathonId")
+	c.SetParamValues(ruleVersionID, hackathonID)
+	if param, id := hackathonScopeParam(c); param != "hackathonId" || id != hackathonID {
+		t.Fatalf("expected the hackathon param, got %s=%s", param, id)
+	}
+
+	c = newHandlerContext(http.MethodGet, "/")
+	c.SetParamNames("ruleVersionId")
+	c.SetParamValues(ruleVersionID)
+	if param, id := hackathonScopeParam(c); param != "ruleVersionId" || id != ruleVersionID {
+		t.Fatalf("expected the rule version param, got %s=%s", param, id)
+	}
+
+	c = newHandlerContext(http.MethodGet, "/")
+	c.SetParamNames("hackathonId")
+	c.SetParamValues("not-a-uuid")
+	if param, id := hackathonScopeParam(c); param != "hackathonId" || id != "not-a-uuid" {
+		t.Fatalf("expected malformed ids to be returned for RequireVisible to reject, got %s=%s", param, id)
+	}
+
+	c = newHandlerContext(http.MethodGet, "/")
+	c.SetParamNames("metricId")
+	c.SetParamValues(hackathonID)
+	if param, _ := hackathonScopeParam(c); param != "" {
+		t.Fatalf("expected unscoped routes to pass, got %s", param)
+	}
+}
+
+func TestRequireVisible(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	h := &AccessHandler{Service: services.NewAccessService(db)}
+	reached := false
+	visible := h.RequireVisible(func(c echo.Context) error {
+		reached = true
+		return nil
+	})
+	hackathonID := "5f3de833-5f0b-4675-b0b6-7e9ef32ed500"
+	ruleID := "0b6c7d6e-1f6a-4c43-9d0b-2a3b4c5d6e7f"
+
+	scoped := func(param, id string) echo.Context {
+		c := newHandlerContext(http.MethodGet, "/")
+		c.SetParamNames(param)
+		c.SetParamValues(id)
+		c.Set("user_id", "user-1")
+		return c
+	}
+
+	if err := visible(scoped("ruleId", "not-a-uuid")); !isHTTPStatus(err, http.StatusBadRequest) {
+		t.Fatalf("expected 400 for a malformed id, got %v", err)
+	}
+
+	mock.ExpectQuery("FROM hackathons WHERE id = \\(SELECT hackathon_id FROM rules WHERE id = \\$6\\)").
+		WithArgs("user-1", "user-1", "user-1", "user-1", "user-1", ruleID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "visible", "roles"}))
+	if err := visible(scoped("ruleId", ruleID)); !isHTTPStatus(err, http.StatusNotFound) {
+		t.Fatalf("expected 404 for an unknown id, got %v", err)
+	}
+
+	mock.ExpectQuery("FROM hackathons").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "visible", "roles"}).AddRow(hackathonID, false, "{}"))
+	if err := visible(scoped("ruleId", ruleID)); !isHTTPStatus(err, http.StatusNotFound) {
+		t.Fatalf("expected 404 for a hidden hackathon, got %v", err)
+	}
+
+	mock.ExpectQuery("FROM hackathons").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "visible", "roles"}).AddRow(hackathonID, true, "{judge,organizer}"))
+	c := scoped("ruleId", ruleID)
+	if err := visible(c); err != nil {
+		t.Fatalf("RequireVisible: %v", err)
+	}
+	roles, _ := c.Get(staffRolesContextKey).([]string)
+	if !reached || c.Get(hackathonIDContextKey) != hackathonID || len(roles) != 2 || roles[1] != models.StaffRoleOrganizer {
+		t.Fatalf("expected the hackathon and staff roles on the context, got %v %v", c.Get(hackathonIDContextKey), roles)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRequireStaff(t *testing.T) {
+	h := &AccessHandler{}
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	organizers := h.RequireStaff(models.StaffRoleOrganizer)(ok)
+	hackathonID := "5f3de833-5f0b-4675-b0b6-7e9ef32ed500"
+
+	scoped := func(roles []string, staff []string) echo.Context {
+		c := newHandlerContext(http.MethodPut, "/")
+		c.SetParamNames("hackathonId")
+		c.SetParamValues(hackathonID)
+		c.Set("roles", roles)
+		c.Set(hackathonIDContextKey, hackathonID)
+		c.Set(staffRolesContextKey, staff)
+		return c
+	}
+
+	if err := organizers(scoped([]string{"hackathon_organizer"}, []string{})); !isHTTPStatus(err, http.StatusForbidden) {
+		t.Fatalf("expected 403 for an organizer of another hackathon, got %v", err)
+	}
+	if err := organizers(scoped(nil, []string{models.StaffRoleJudge})); !isHTTPStatus(err, http.StatusForbidden) {
+		t.Fatalf("expected 403 for a judge, got %v", err)
+	}
+	for _, c := range []echo.Context{
+		scoped(nil, []string{models.StaffRoleOrganizer}),
+		scoped(nil, []string{models.StaffRoleOwner}),
+		scoped([]string{"hackathon_admin"}, nil),
+	} {
+		if err := organizers(c); err != nil {
+			t.Fatalf("expected access, got %v", err)
+		}
+	}
+
+	// Unresolved ids never reach the handler; unscoped routes are refused.
+	reached := false
+	guarded := h.RequireStaff(models.StaffRoleOrganizer)(func(c echo.Context) error {
+		reached = true
+		return nil
+	})
+	unresolved := func(param, id string) echo.Context {
+		c := newHandlerContext(http.MethodPut, "/")
+		c.SetParamNames(param)
+		c.SetParamValues(id)
+		c.Set("user_id", "user-1")
+		return c
+	}
+	if err := guarded(unresolved("ruleId", "0b6c7d6e-1f6a-4c43-9d0b-2a3b4c5d6e7f")); !isHTTPStatus(err, http.StatusNotFound) || reached {
+		t.Fatalf("expected 404 for an unknown rule without reaching the handler, got %v", err)
+	}
+	if err := guarded(unresolved("hackathonId", "not-a-uuid")); !isHTTPStatus(err, http.StatusBadRequest) || reached {
+		t.Fatalf("expected 400 for a malformed id without reaching the handler, got %v", err)
+	}
+	if err := organizers(newHandlerContext(http.MethodPost, "/")); !isHTTPStatus(err, http.StatusForbidden) {
+		t.Fatalf("expected 403 on an unscoped route, got %v", err)
+	}
+}
+
+func TestRequireEvaluator(t *testing.T) {
+	h := &AccessHandler{}
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	callback := h.RequireEvaluator()(ok)
+	hackathonID := "5f3de833-5f0b-4675-b0b6-7e9ef32ed500"
+
+	scoped := func(roles []string, staff []string) echo.Context {
+		c := newHandlerContext(http.MethodPost, "/")
+		c.SetParamNames("submissionId")
+		c.SetParamValues("0d8f2a3e-5b7c-4e1a-9c2d-3f4a5b6c7d8e")
+		c.Set("roles", roles)
+		c.Set(hackathonIDContextKey, hackathonID)
+		c.Set(staffRolesContextKey, staff)
+		return c
+	}
+
+	if err := callback(scoped([]string{"hackathon_organizer"}, []string{})); !isHTTPStatus(err, http.StatusForbidden) {
+		t.Fatalf("expected 403 for an organizer of another hackathon, got %v", err)
+	}
+	if err := callback(scoped(nil, []string{models.StaffRoleJudge})); !isHTTPStatus(err, http.StatusForbidden) {
+		t.Fatalf("expected 403 for a judge, got %v", err)
+	}
+	for _, c := range []echo.Context{
+		scoped([]string{"evaluation_executor"}, nil),
+		scoped([]string{"platform_admin"}, nil),
+		scoped(nil, []string{models.StaffRoleEvaluator}),
+		scoped(nil, []string{models.StaffRoleOrganizer}),
+	} {
+		if err := callback(c); err != nil {
+			t.Fatalf("expected access, got %v", err)
+		}
+	}
+}
+
+func TestRequireStaffUnknownRule(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	h := &AccessHandler{Service: services.NewAccessService(db)}
+	reached := false
+	handler := h.RequireVisible(h.RequireStaff(models.StaffRoleOrganizer)(func(c echo.Context) error {
+		reached = true
+		return nil
+	}))
+
+	mock.ExpectQuery("FROM hackathons").WillReturnRows(sqlmock.NewRows([]string{"id", "visible", "roles"}))
+	c := newHandlerContext(http.MethodPut, "/")
+	c.SetParamNames("ruleId")
+	c.SetParamValues("0b6c7d6e-1f6a-4c43-9d0b-2a3b4c5d6e7f")
+	c.Set("user_id", "user-1")
+	if err := handler(c); !isHTTPStatus(err, http.StatusNotFound) || reached {
+		t.Fatalf("expected 404 for a non-staff caller on an unknown rule, got %v (reached=%v)", err, reached)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func isHTTPStatus(err error, code int) bool {
+	var httpErr *echo.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == code
+}
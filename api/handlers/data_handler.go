@@ -7,18 +7,16 @@ import (
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/labstack/echo/v4"
 )
 
 type DataHandler struct {
 	Service    *services.DatasetService
 	Governance *services.GovernanceService
-	Publisher  events.Publisher
 }
 
-func NewDataHandler(service *services.DatasetService, governance *services.GovernanceService, publisher events.Publisher) *DataHandler {
-	return &DataHandler{Service: service, Governance: governance, Publisher: publisher}
+func NewDataHandler(service *services.DatasetService, governance *services.GovernanceService) *DataHandler {
+	return &DataHandler{Service: service, Governance: governance}
 }
 
 func (h *DataHandler) Create(c echo.Context) error {
@@ -34,7 +32,6 @@ func (h *DataHandler) Create(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.created", map[string]any{"hackathon_id": hackathonID, "dataset_id": created.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.created", created)
 	return c.JSON(http.StatusCreated, created)
 }
@@ -67,7 +64,6 @@ func (h *DataHandler) Update(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.updated", map[string]any{"hackathon_id": hackathonID, "dataset_id": updated.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.updated", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -83,7 +79,6 @@ func (h *DataHandler) Delete(c echo.Context) error {
 		}
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.deleted", map[string]any{"hackathon_id": hackathonID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.deleted", map[string]string{"hackathon_id": hackathonID})
 	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
@@ -101,7 +96,6 @@ func (h *DataHandler) CreateFile(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.file.created", map[string]any{"hackathon_id": hackathonID, "file_id": created.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.created", created)
 	return c.JSON(http.StatusCreated, created)
 }
@@ -111,15 +105,15 @@ func (h *DataHandler) ListFiles(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.ListFiles(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.ListFiles(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
 }
 
 func (h *DataHandler) GetFile(c echo.Context) error {
@@ -158,7 +152,6 @@ func (h *DataHandler) UpdateFile(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.file.updated", map[string]any{"hackathon_id": hackathonID, "file_id": updated.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.updated", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -178,7 +171,6 @@ func (h *DataHandler) DeleteFile(c echo.Context) error {
 		}
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.file.deleted", map[string]any{"hackathon_id": hackathonID, "file_id": fileID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.deleted", map[string]string{"id": fileID})
 	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
@@ -196,7 +188,6 @@ func (h *DataHandler) CreateVariable(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.variable.created", map[string]any{"hackathon_id": hackathonID, "variable_id": created.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.variable.created", created)
 	return c.JSON(http.StatusCreated, created)
 }
@@ -206,15 +197,15 @@ func (h *DataHandler) ListVariables(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.ListVariables(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.ListVariables(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
 }
 
 func (h *DataHandler) GetVariable(c echo.Context) error {
@@ -253,7 +244,6 @@ func (h *DataHandler) UpdateVariable(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.variable.updated", map[string]any{"hackathon_id": hackathonID, "variable_id": updated.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.variable.updated", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -273,20 +263,10 @@ func (h *DataHandler) DeleteVariable(c echo.Context) error {
 		}
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.data.variable.deleted", map[string]any{"hackathon_id": hackathonID, "variable_id": variableID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.variable.deleted", map[string]string{"id": variableID})
 	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
 
-func (h *DataHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
-	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
-	}
-}
-
 func (h *DataHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
 	if h.Governance == nil {
 		return
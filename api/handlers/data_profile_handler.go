@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestProfile queues profiling of an uploaded CSV or Parquet file; poll
+// GetProfile for the result.
+func (h *DataHandler) RequestProfile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	fileID, err := parseUUIDParam(c, "fileId")
+	if err != nil {
+		return err
+	}
+	profile, err := h.Service.RequestProfile(c.Request().Context(), hackathonID, fileID, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusAccepted, profile)
+}
+
+func (h *DataHandler) GetProfile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	fileID, err := parseUUIDParam(c, "fileId")
+	if err != nil {
+		return err
+	}
+	profile, err := h.Service.GetProfile(c.Request().Context(), hackathonID, fileID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if profile == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "profile not found")
+	}
+	return c.JSON(http.StatusOK, profile)
+}
+
+// AcceptProfile creates variables from the latest completed profile.
+func (h *DataHandler) AcceptProfile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	fileID, err := parseUUIDParam(c, "fileId")
+	if err != nil {
+		return err
+	}
+	var input services.DatasetProfileAcceptInput
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&input); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+	result, err := h.Service.AcceptProfile(c.Request().Context(), hackathonID, fileID, input)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	actorID := actorIDFromContext(c)
+	for i := range result.Created {
+		created := &result.Created[i]
+		h.audit(c, hackathonID, actorID, "hackathon.data.variable.created", created)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.data.profile.accepted", map[string]any{
+		"profile_id": result.Profile.ID,
+		"created":    len(result.Created),
+		"skipped":    result.Skipped,
+	})
+	return c.JSON(http.StatusOK, result)
+}
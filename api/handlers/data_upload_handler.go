@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadTimeout replaces the server read and write timeouts for upload
+// requests, which are expected to stream large bodies.
+const uploadTimeout = time.Hour
+
+// UploadFile stores the multipart "file" field as a dataset file. The name
+// defaults to the uploaded file name.
+func (h *DataHandler) UploadFile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	extendUploadDeadline(c)
+	header, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+	f, err := header.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	defer f.Close()
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		name = header.Filename
+	}
+	created, err := h.Service.UploadFile(c.Request().Context(), hackathonID, services.DatasetFileUploadInput{
+		Name:        name,
+		FileType:    c.FormValue("file_type"),
+		Description: c.FormValue("description"),
+		ContentType: header.Header.Get("Content-Type"),
+	}, f, header.Size)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.created", created)
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *DataHandler) CreateUpload(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.DatasetUploadInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	upload, err := h.Service.CreateUpload(c.Request().Context(), hackathonID, input, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	setUploadHeaders(c, upload)
+	c.Response().Header().Set(echo.HeaderLocation, c.Request().URL.Path+"/"+upload.ID)
+	return c.JSON(http.StatusCreated, upload)
+}
+
+// GetUpload reports the upload progress; Upload-Offset is where the next
+// chunk must start.
+func (h *DataHandler) GetUpload(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	uploadID, err := parseUUIDParam(c, "uploadId")
+	if err != nil {
+		return err
+	}
+	upload, err := h.Service.GetUpload(c.Request().Context(), hackathonID, uploadID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if upload == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "upload not found")
+	}
+	setUploadHeaders(c, upload)
+	return c.JSON(http.StatusOK, upload)
+}
+
+// AppendUpload takes the raw chunk as the request body and its start
+// position in the Upload-Offset header.
+func (h *DataHandler) AppendUpload(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	uploadID, err := parseUUIDParam(c, "uploadId")
+	if err != nil {
+		return err
+	}
+	offset, err := strconv.ParseInt(c.Request().Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Upload-Offset header is required")
+	}
+	extendUploadDeadline(c)
+
+	upload, err := h.Service.AppendUpload(c.Request().Context(), hackathonID, uploadID, offset, c.Request().Body, c.Request().ContentLength)
+	if err != nil {
+		if errors.Is(err, services.ErrConflict) {
+			if current, _ := h.Service.GetUpload(c.Request().Context(), hackathonID, uploadID); current != nil {
+				setUploadHeaders(c, current)
+			}
+		}
+		return handleServiceError(err)
+	}
+	setUploadHeaders(c, upload)
+	if upload.File != nil {
+		h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.created", upload.File)
+	}
+	return c.JSON(http.StatusOK, upload)
+}
+
+// CompleteUpload assembles an upload whose bytes were all received. The last
+// chunk already does this; the endpoint retries an assembly that failed.
+func (h *DataHandler) CompleteUpload(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	uploadID, err := parseUUIDParam(c, "uploadId")
+	if err != nil {
+		return err
+	}
+	extendUploadDeadline(c)
+
+	upload, err := h.Service.CompleteUpload(c.Request().Context(), hackathonID, uploadID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	setUploadHeaders(c, upload)
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.data.file.created", upload.File)
+	return c.JSON(http.StatusOK, upload)
+}
+
+func (h *DataHandler) AbortUpload(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	uploadID, err := parseUUIDParam(c, "uploadId")
+	if err != nil {
+		return err
+	}
+	if err := h.Service.AbortUpload(c.Request().Context(), hackathonID, uploadID); err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "aborted"})
+}
+
+// DownloadFile returns a signed, expiring download link for a data file.
+func (h *DataHandler) DownloadFile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	fileID, err := parseUUIDParam(c, "fileId")
+	if err != nil {
+		return err
+	}
+	download, err := h.Service.DownloadFile(c.Request().Context(), hackathonID, fileID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, download)
+}
+
+func setUploadHeaders(c echo.Context, upload *models.DatasetUpload) {
+	c.Response().Header().Set("Upload-Offset", strconv.FormatInt(upload.OffsetBytes, 10))
+	c.Response().Header().Set("Upload-Length", strconv.FormatInt(upload.SizeBytes, 10))
+}
+
+func extendUploadDeadline(c echo.Context) {
+	// Not every ResponseWriter supports deadlines (e.g. test recorders).
+	rc := http.NewResponseController(c.Response())
+	deadline := time.Now().Add(uploadTimeout)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
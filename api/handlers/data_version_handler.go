@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateVersion snapshots the dataset outside of the automatic publish and
+// rule-lock triggers. It answers 200 with the latest version when nothing
+// changed since.
+func (h *DataHandler) CreateVersion(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	actorID := actorIDFromContext(c)
+	version, created, err := h.Service.CreateVersion(c.Request().Context(), hackathonID, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if !created {
+		return c.JSON(http.StatusOK, version)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.data.version.created", map[string]any{
+		"dataset_version_id": version.ID,
+		"version":            version.Version,
+		"checksum":           version.Checksum,
+	})
+	return c.JSON(http.StatusCreated, version)
+}
+
+func (h *DataHandler) ListVersions(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.Service.ListVersions(c.Request().Context(), hackathonID, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+func (h *DataHandler) GetVersion(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersionParam(c)
+	if err != nil {
+		return err
+	}
+	item, err := h.Service.GetVersion(c.Request().Context(), hackathonID, version)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if item == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "dataset version not found")
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *DataHandler) DiffVersions(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid from")
+	}
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid to")
+	}
+	diff, err := h.Service.DiffVersions(c.Request().Context(), hackathonID, from, to)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, diff)
+}
+
+func (h *DataHandler) DownloadVersionFile(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	version, err := parseVersionParam(c)
+	if err != nil {
+		return err
+	}
+	fileID, err := parseUUIDParam(c, "fileId")
+	if err != nil {
+		return err
+	}
+	download, err := h.Service.DownloadVersionFile(c.Request().Context(), hackathonID, version, fileID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, download)
+}
+
+func parseVersionParam(c echo.Context) (int, error) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid version")
+	}
+	return version, nil
+}
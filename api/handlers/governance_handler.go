@@ -49,13 +49,156 @@ func (h *GovernanceHandler) AuditHackathon(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.AuditLogs(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.AuditLogs(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
+}
+
+func (h *GovernanceHandler) ListReports(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	filter, page, err := parseGovernanceFilter(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.Service.ListReports(c.Request().Context(), hackathonID, filter, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+func (h *GovernanceHandler) GetReport(c echo.Context) error {
+	id, err := parseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+	report, err := h.Service.GetReport(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if report == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "report not found")
+	}
+	if !isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleModerator) && report.ReporterID != actorIDFromContext(c) {
+		return echo.NewHTTPError(http.StatusForbidden, "forbidden")
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *GovernanceHandler) AssignReport(c echo.Context) error {
+	id, err := parseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+	var input services.GovernanceAssignInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	report, err := h.Service.AssignReport(c.Request().Context(), id, input, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *GovernanceHandler) TransitionReport(c echo.Context) error {
+	id, err := parseUUIDParam(c, "reportId")
+	if err != nil {
+		return err
+	}
+	var input services.GovernanceTransitionInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	report, err := h.Service.TransitionReport(c.Request().Context(), id, input, actorIDFromContext(c), isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleModerator))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+func (h *GovernanceHandler) ListAppeals(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	filter, page, err := parseGovernanceFilter(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.Service.ListAppeals(c.Request().Context(), hackathonID, filter, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+func (h *GovernanceHandler) GetAppeal(c echo.Context) error {
+	id, err := parseUUIDParam(c, "appealId")
+	if err != nil {
+		return err
+	}
+	appeal, err := h.Service.GetAppeal(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if appeal == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "appeal not found")
+	}
+	if !isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleModerator) && appeal.AppellantID != actorIDFromContext(c) {
+		return echo.NewHTTPError(http.StatusForbidden, "forbidden")
+	}
+	return c.JSON(http.StatusOK, appeal)
+}
+
+func (h *GovernanceHandler) AssignAppeal(c echo.Context) error {
+	id, err := parseUUIDParam(c, "appealId")
+	if err != nil {
+		return err
+	}
+	var input services.GovernanceAssignInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	appeal, err := h.Service.AssignAppeal(c.Request().Context(), id, input, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, appeal)
+}
+
+func (h *GovernanceHandler) TransitionAppeal(c echo.Context) error {
+	id, err := parseUUIDParam(c, "appealId")
+	if err != nil {
+		return err
+	}
+	var input services.GovernanceTransitionInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	appeal, err := h.Service.TransitionAppeal(c.Request().Context(), id, input, actorIDFromContext(c), isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleModerator))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, appeal)
+}
+
+func parseGovernanceFilter(c echo.Context) (services.GovernanceFilter, services.PageRequest, error) {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return services.GovernanceFilter{}, services.PageRequest{}, err
+	}
+	return services.GovernanceFilter{
+		Status:     c.QueryParam("status"),
+		AssigneeID: c.QueryParam("assignee_id"),
+	}, page, nil
 }
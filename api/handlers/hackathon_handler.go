@@ -7,18 +7,16 @@ import (
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/labstack/echo/v4"
 )
 
 type HackathonHandler struct {
-	Service   *services.HackathonService
+	Service    *services.HackathonService
 	Governance *services.GovernanceService
-	Publisher events.Publisher
 }
 
-func NewHackathonHandler(service *services.HackathonService, governance *services.GovernanceService, publisher events.Publisher) *HackathonHandler {
-	return &HackathonHandler{Service: service, Governance: governance, Publisher: publisher}
+func NewHackathonHandler(service *services.HackathonService, governance *services.GovernanceService) *HackathonHandler {
+	return &HackathonHandler{Service: service, Governance: governance}
 }
 
 func (h *HackathonHandler) Create(c echo.Context) error {
@@ -32,25 +30,73 @@ func (h *HackathonHandler) Create(c echo.Context) error {
 		return handleServiceError(err)
 	}
 
-	h.emit(c, "hackathon.created", map[string]any{
-		"hackathon_id": created.ID,
-		"state":        created.State,
-	})
 	h.audit(c, created.ID, actorID, "hackathon.created", created)
 
 	return c.JSON(http.StatusCreated, created)
 }
 
 func (h *HackathonHandler) List(c echo.Context) error {
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	filter, err := parseHackathonFilter(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.Service.List(c.Request().Context(), filter, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+// Search is ranked by relevance, so it pages with limit/offset like the
+// leaderboard rather than with cursors.
+func (h *HackathonHandler) Search(c echo.Context) error {
 	limit, offset, err := parseLimitOffset(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.List(c.Request().Context(), limit, offset)
+	filter, err := parseHackathonFilter(c)
+	if err != nil {
+		return err
+	}
+	result, err := h.Service.Search(c.Request().Context(), services.HackathonSearchQuery{
+		Text:       c.QueryParam("q"),
+		Filter:     filter,
+		TeamPolicy: c.QueryParam("team_policy"),
+		MetricType: c.QueryParam("metric_type"),
+		Limit:      limit,
+		Offset:     offset,
+	})
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return c.JSON(http.StatusOK, result)
+}
+
+func parseHackathonFilter(c echo.Context) (services.HackathonFilter, error) {
+	viewer := viewerFromContext(c)
+	filter := services.HackathonFilter{
+		Viewer:     &viewer,
+		State:      c.QueryParam("state"),
+		Visibility: c.QueryParam("visibility"),
+	}
+	var err error
+	if filter.StartsAfter, err = parseQueryTime(c, "starts_after"); err != nil {
+		return services.HackathonFilter{}, err
+	}
+	if filter.StartsBefore, err = parseQueryTime(c, "starts_before"); err != nil {
+		return services.HackathonFilter{}, err
+	}
+	if filter.EndsAfter, err = parseQueryTime(c, "ends_after"); err != nil {
+		return services.HackathonFilter{}, err
+	}
+	if filter.EndsBefore, err = parseQueryTime(c, "ends_before"); err != nil {
+		return services.HackathonFilter{}, err
+	}
+	return filter, nil
 }
 
 func (h *HackathonHandler) GetByID(c echo.Context) error {
@@ -109,7 +155,6 @@ func (h *HackathonHandler) Publish(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.published", map[string]any{"hackathon_id": updated.ID, "state": updated.State})
 	h.audit(c, id, actorIDFromContext(c), "hackathon.published", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -129,18 +174,52 @@ func (h *HackathonHandler) Transition(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.phase.changed", map[string]any{"hackathon_id": updated.ID, "state": updated.State})
-	if updated.State == models.HackathonStateLive && updated.RequiresTeams {
-		h.emit(c, "hackathon.team.required", map[string]any{"hackathon_id": updated.ID})
+	h.audit(c, id, actorIDFromContext(c), "hackathon.phase.changed", updated)
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *HackathonHandler) GetSchedule(c echo.Context) error {
+	id, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	schedule, err := h.Service.GetSchedule(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if schedule == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "hackathon schedule not found")
 	}
-	if updated.State == models.HackathonStateSubmissionFrozen {
-		h.emit(c, "hackathon.team.locked", map[string]any{"hackathon_id": updated.ID})
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *HackathonHandler) PutSchedule(c echo.Context) error {
+	id, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
 	}
-	if updated.State == models.HackathonStateCompleted {
-		h.emit(c, "hackathon.completed", map[string]any{"hackathon_id": updated.ID})
+	var input models.HackathonSchedule
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	h.audit(c, id, actorIDFromContext(c), "hackathon.phase.changed", updated)
-	return c.JSON(http.StatusOK, updated)
+	schedule, err := h.Service.PutSchedule(c.Request().Context(), id, input)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, id, actorIDFromContext(c), "hackathon.schedule.updated", schedule)
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *HackathonHandler) DeleteSchedule(c echo.Context) error {
+	id, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	if err := h.Service.DeleteSchedule(c.Request().Context(), id); err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, id, actorIDFromContext(c), "hackathon.schedule.deleted", map[string]string{"hackathon_id": id})
+	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
 
 func (h *HackathonHandler) GetState(c echo.Context) error {
@@ -231,7 +310,6 @@ func (h *HackathonHandler) FreezeLeaderboard(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "leaderboard.freeze.requested", map[string]any{"hackathon_id": updated.ID})
 	h.audit(c, id, actorIDFromContext(c), "leaderboard.freeze.requested", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -245,7 +323,6 @@ func (h *HackathonHandler) PublishLeaderboard(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "leaderboard.publish.requested", map[string]any{"hackathon_id": updated.ID})
 	h.audit(c, id, actorIDFromContext(c), "leaderboard.publish.requested", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -259,20 +336,10 @@ func (h *HackathonHandler) UnfreezeLeaderboard(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "leaderboard.unfreeze.requested", map[string]any{"hackathon_id": updated.ID})
 	h.audit(c, id, actorIDFromContext(c), "leaderboard.unfreeze.requested", updated)
 	return c.JSON(http.StatusOK, updated)
 }
 
-func (h *HackathonHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
-	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
-	}
-}
-
 func (h *HackathonHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
 	if h.Governance == nil {
 		return
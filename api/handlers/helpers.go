@@ -4,9 +4,12 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DataInCube/hackathon-service/api/middlewares"
 	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -27,6 +30,8 @@ func parseUUIDParam(c echo.Context, name string) (string, error) {
 	return raw, nil
 }
 
+// parseLimitOffset reads offset pagination, used as is by the ranked
+// leaderboard and search. Other list endpoints page with parsePageRequest.
 func parseLimitOffset(c echo.Context) (int, int, error) {
 	limit := defaultLimit
 	offset := 0
@@ -53,6 +58,67 @@ func parseLimitOffset(c echo.Context) (int, int, error) {
 	return limit, offset, nil
 }
 
+// parsePageRequest reads limit and the opaque cursor of a keyset-paginated
+// list. offset is still accepted for clients that have not moved to cursors
+// yet, but not together with a cursor.
+func parsePageRequest(c echo.Context) (services.PageRequest, error) {
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		return services.PageRequest{}, err
+	}
+	page := services.PageRequest{Limit: limit, Cursor: c.QueryParam("cursor"), Offset: offset}
+	if page.Cursor != "" && c.QueryParam("offset") != "" {
+		return services.PageRequest{}, echo.NewHTTPError(http.StatusBadRequest, "use either cursor or offset")
+	}
+	return page, nil
+}
+
+const (
+	// legacyListMediaType asks a list endpoint for the bare JSON array it
+	// returned before pages were introduced.
+	legacyListMediaType = "application/vnd.hackathon.v1+json"
+	// legacyListSunset is when the bare array shape is removed.
+	legacyListSunset = "Fri, 30 Apr 2027 00:00:00 GMT"
+)
+
+// respondPage writes a page of a list. Clients that send
+// Accept: application/vnd.hackathon.v1+json still get the items as a bare
+// array until legacyListSunset, with the total and next cursor in headers.
+func respondPage[T any](c echo.Context, page *models.Page[T]) error {
+	if !strings.Contains(c.Request().Header.Get(echo.HeaderAccept), legacyListMediaType) {
+		return c.JSON(http.StatusOK, page)
+	}
+	h := c.Response().Header()
+	h.Set("Deprecation", "true")
+	h.Set("Sunset", legacyListSunset)
+	h.Set("X-Total-Count", strconv.Itoa(page.Total))
+	if page.NextCursor != nil {
+		h.Set("X-Next-Cursor", *page.NextCursor)
+	}
+	return c.JSON(http.StatusOK, page.Items)
+}
+
+// parseQueryTime reads an optional RFC 3339 timestamp query parameter.
+func parseQueryTime(c echo.Context, name string) (*time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid "+name)
+	}
+	return &t, nil
+}
+
+// parseOptionalQueryUUID reads a UUID query parameter that may be absent.
+func parseOptionalQueryUUID(c echo.Context, name string) (string, error) {
+	if c.QueryParam(name) == "" {
+		return "", nil
+	}
+	return parseQueryUUID(c, name)
+}
+
 func parseQueryUUID(c echo.Context, name string) (string, error) {
 	raw := c.QueryParam(name)
 	if raw == "" {
@@ -65,6 +131,13 @@ func parseQueryUUID(c echo.Context, name string) (string, error) {
 }
 
 func handleServiceError(err error) *echo.HTTPError {
+	var fieldErrs *services.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, map[string]any{
+			"message": fieldErrs.Message,
+			"errors":  fieldErrs.Errors,
+		})
+	}
 	switch {
 	case errors.Is(err, services.ErrNotFound):
 		return echo.NewHTTPError(http.StatusNotFound, err.Error())
@@ -74,6 +147,8 @@ func handleServiceError(err error) *echo.HTTPError {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	case errors.Is(err, services.ErrForbidden):
 		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrQuotaExceeded):
+		return echo.NewHTTPError(http.StatusTooManyRequests, err.Error())
 	default:
 		return echo.NewHTTPError(http.StatusInternalServerError, "internal error")
 	}
@@ -95,7 +170,22 @@ func hasAnyRole(roles []string, targets ...string) bool {
 	return false
 }
 
+// isAdminOrOrganizer reports whether the caller manages the hackathon of the
+// current route: a platform admin, or its owner or an organizer.
 func isAdminOrOrganizer(c echo.Context) bool {
-	roles := middlewares.RolesFromContext(c)
-	return hasAnyRole(roles, "hackathon_admin", "hackathon_organizer", "platform_admin")
+	return isAdminOrStaff(c, models.StaffRoleOrganizer)
+}
+
+// isAdminOrStaff reports whether the caller is a platform admin or holds one
+// of roles (or owner) on the hackathon of the current route.
+func isAdminOrStaff(c echo.Context, roles ...string) bool {
+	if hasAnyRole(middlewares.RolesFromContext(c), "hackathon_admin", "platform_admin") {
+		return true
+	}
+	return hasAnyRole(staffRolesFromContext(c), append([]string{models.StaffRoleOwner}, roles...)...)
+}
+
+func staffRolesFromContext(c echo.Context) []string {
+	roles, _ := c.Get(staffRolesContextKey).([]string)
+	return roles
 }
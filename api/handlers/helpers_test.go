@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
 	"github.com/labstack/echo/v4"
 )
 
@@ -108,6 +110,77 @@ func TestParseLimitOffset_Invalid(t *testing.T) {
 	}
 }
 
+func TestParsePageRequest(t *testing.T) {
+	c := newHandlerContext(http.MethodGet, "/")
+	page, err := parsePageRequest(c)
+	if err != nil || page.Limit != defaultLimit || page.Cursor != "" {
+		t.Fatalf("unexpected defaults: %+v (%v)", page, err)
+	}
+
+	c = newHandlerContext(http.MethodGet, "/?limit=999&cursor=abc")
+	page, err = parsePageRequest(c)
+	if err != nil || page.Limit != maxLimit || page.Cursor != "abc" {
+		t.Fatalf("unexpected values: %+v (%v)", page, err)
+	}
+
+	c = newHandlerContext(http.MethodGet, "/?limit=20&offset=40")
+	page, err = parsePageRequest(c)
+	if err != nil || page.Limit != 20 || page.Offset != 40 || page.Cursor != "" {
+		t.Fatalf("unexpected offset page: %+v (%v)", page, err)
+	}
+
+	for _, target := range []string{"/?limit=0", "/?offset=-1", "/?cursor=abc&offset=10"} {
+		if _, err := parsePageRequest(newHandlerContext(http.MethodGet, target)); err == nil {
+			t.Fatalf("expected error for %s", target)
+		}
+	}
+}
+
+func TestRespondPage(t *testing.T) {
+	next := "c2"
+	page := &models.Page[string]{Items: []string{"a", "b"}, NextCursor: &next, Total: 5}
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if err := respondPage(c, page); err != nil {
+		t.Fatalf("respondPage: %v", err)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"items":["a","b"],"next_cursor":"c2","total":5}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+	if rec.Header().Get("Deprecation") != "" {
+		t.Fatal("expected no Deprecation header on the paged shape")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAccept, legacyListMediaType)
+	rec = httptest.NewRecorder()
+	c = echo.New().NewContext(req, rec)
+	if err := respondPage(c, page); err != nil {
+		t.Fatalf("respondPage: %v", err)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `["a","b"]` {
+		t.Fatalf("unexpected legacy body: %s", got)
+	}
+	if rec.Header().Get("Sunset") != legacyListSunset || rec.Header().Get("X-Total-Count") != "5" || rec.Header().Get("X-Next-Cursor") != "c2" {
+		t.Fatalf("unexpected legacy headers: %v", rec.Header())
+	}
+}
+
+func TestParseQueryTime(t *testing.T) {
+	c := newHandlerContext(http.MethodGet, "/?from=2026-03-01T10:00:00Z")
+	got, err := parseQueryTime(c, "from")
+	if err != nil || got == nil || got.Day() != 1 || got.Hour() != 10 {
+		t.Fatalf("unexpected time: %v (%v)", got, err)
+	}
+	if got, err := parseQueryTime(c, "to"); err != nil || got != nil {
+		t.Fatalf("expected nil for missing param, got %v (%v)", got, err)
+	}
+	if _, err := parseQueryTime(newHandlerContext(http.MethodGet, "/?from=yesterday"), "from"); err == nil {
+		t.Fatal("expected error for invalid time")
+	}
+}
+
 func TestHandleServiceError(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -118,6 +191,8 @@ func TestHandleServiceError(t *testing.T) {
 		{"conflict", fmt.Errorf("wrap: %w", services.ErrConflict), http.StatusConflict},
 		{"invalid", fmt.Errorf("wrap: %w", services.ErrInvalid), http.StatusBadRequest},
 		{"forbidden", fmt.Errorf("wrap: %w", services.ErrForbidden), http.StatusForbidden},
+		{"quota exceeded", fmt.Errorf("wrap: %w", services.ErrQuotaExceeded), http.StatusTooManyRequests},
+		{"field errors", &services.FieldErrors{Message: "invalid metadata"}, http.StatusUnprocessableEntity},
 		{"internal", errors.New("boom"), http.StatusInternalServerError},
 	}
 
@@ -148,7 +223,33 @@ func TestIsAdminOrOrganizer(t *testing.T) {
 	}
 
 	c.Set("roles", []string{"hackathon_organizer"})
+	if isAdminOrOrganizer(c) {
+		t.Fatal("the global organizer role should not grant access to every hackathon")
+	}
+
+	c.Set(staffRolesContextKey, []string{models.StaffRoleOrganizer})
 	if !isAdminOrOrganizer(c) {
-		t.Fatal("organizer role should be accepted")
+		t.Fatal("hackathon organizers should be accepted")
+	}
+
+	c = newHandlerContext(http.MethodGet, "/")
+	c.Set("roles", []string{"hackathon_admin"})
+	if !isAdminOrOrganizer(c) {
+		t.Fatal("hackathon_admin should be accepted everywhere")
+	}
+}
+
+func TestIsAdminOrStaff(t *testing.T) {
+	c := newHandlerContext(http.MethodGet, "/")
+	c.Set(staffRolesContextKey, []string{models.StaffRoleJudge})
+	if !isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleJudge) {
+		t.Fatal("judges should pass a judge check")
+	}
+	if isAdminOrStaff(c, models.StaffRoleModerator) {
+		t.Fatal("judges should not pass a moderator check")
+	}
+	c.Set(staffRolesContextKey, []string{models.StaffRoleOwner})
+	if !isAdminOrStaff(c, models.StaffRoleModerator) {
+		t.Fatal("owners should pass every staff check")
 	}
 }
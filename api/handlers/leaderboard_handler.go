@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+type LeaderboardHandler struct {
+	Service    *services.LeaderboardService
+	Governance *services.GovernanceService
+}
+
+func NewLeaderboardHandler(service *services.LeaderboardService, governance *services.GovernanceService) *LeaderboardHandler {
+	return &LeaderboardHandler{Service: service, Governance: governance}
+}
+
+func (h *LeaderboardHandler) Get(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	limit, offset, err := parseLimitOffset(c)
+	if err != nil {
+		return err
+	}
+	live := false
+	if raw := c.QueryParam("live"); raw != "" {
+		if live, err = strconv.ParseBool(raw); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid live")
+		}
+	}
+	leaderboard, err := h.Service.Get(c.Request().Context(), hackathonID, services.LeaderboardQuery{
+		Board:      c.QueryParam("board"),
+		Live:       live,
+		Privileged: isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleJudge),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func (h *LeaderboardHandler) Rebuild(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	if err := h.Service.Rebuild(c.Request().Context(), hackathonID); err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "leaderboard.rebuilt", map[string]string{"hackathon_id": hackathonID})
+	leaderboard, err := h.Service.Get(c.Request().Context(), hackathonID, services.LeaderboardQuery{
+		Live:       true,
+		Privileged: true,
+		Limit:      defaultLimit,
+	})
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, leaderboard)
+}
+
+func (h *LeaderboardHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
+	if h.Governance == nil {
+		return
+	}
+	raw, _ := json.Marshal(payload)
+	_ = h.Governance.AppendAudit(c.Request().Context(), models.AuditLog{
+		HackathonID: hackathonID,
+		ActorID:     actorID,
+		Action:      action,
+		Payload:     raw,
+	})
+}
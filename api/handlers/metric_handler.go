@@ -3,22 +3,23 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type MetricHandler struct {
 	Service    *services.MetricService
 	Governance *services.GovernanceService
-	Publisher  events.Publisher
 }
 
-func NewMetricHandler(service *services.MetricService, governance *services.GovernanceService, publisher events.Publisher) *MetricHandler {
-	return &MetricHandler{Service: service, Governance: governance, Publisher: publisher}
+func NewMetricHandler(service *services.MetricService, governance *services.GovernanceService) *MetricHandler {
+	return &MetricHandler{Service: service, Governance: governance}
 }
 
 func (h *MetricHandler) Create(c echo.Context) error {
@@ -34,7 +35,6 @@ func (h *MetricHandler) Create(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.metric.created", map[string]any{"hackathon_id": hackathonID, "metric_id": created.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.metric.created", created)
 	return c.JSON(http.StatusCreated, created)
 }
@@ -44,15 +44,15 @@ func (h *MetricHandler) List(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.List(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.List(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
 }
 
 func (h *MetricHandler) GetByID(c echo.Context) error {
@@ -74,6 +74,11 @@ func (h *MetricHandler) GetByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, item)
 }
 
+// Types lists the supported metric types and the params each one accepts.
+func (h *MetricHandler) Types(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.Service.MetricTypes())
+}
+
 func (h *MetricHandler) Update(c echo.Context) error {
 	hackathonID, err := parseUUIDParam(c, "hackathonId")
 	if err != nil {
@@ -91,7 +96,6 @@ func (h *MetricHandler) Update(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.metric.updated", map[string]any{"hackathon_id": hackathonID, "metric_id": updated.ID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.metric.updated", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -111,18 +115,55 @@ func (h *MetricHandler) Delete(c echo.Context) error {
 		}
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.metric.deleted", map[string]any{"hackathon_id": hackathonID, "metric_id": metricID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.metric.deleted", map[string]string{"id": metricID})
 	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
 
-func (h *MetricHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
+// Verify recomputes a metric from uploaded ground_truth and predictions CSV
+// files, optionally comparing it with a submission's reported score.
+func (h *MetricHandler) Verify(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	metricID, err := parseUUIDParam(c, "metricId")
+	if err != nil {
+		return err
 	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
+	submissionID := strings.TrimSpace(c.FormValue("submission_id"))
+	if submissionID != "" {
+		if _, err := uuid.Parse(submissionID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid submission_id")
+		}
+	}
+	files := make(map[string]io.ReadCloser, 2)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, field := range []string{"ground_truth", "predictions"} {
+		header, err := c.FormFile(field)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, field+" file is required")
+		}
+		f, err := header.Open()
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		files[field] = f
+	}
+
+	result, err := h.Service.VerifyScore(c.Request().Context(), hackathonID, metricID, services.ScoreVerificationInput{
+		GroundTruth:  files["ground_truth"],
+		Predictions:  files["predictions"],
+		SubmissionID: submissionID,
+	})
+	if err != nil {
+		return handleServiceError(err)
 	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.metric.verified", result)
+	return c.JSON(http.StatusOK, result)
 }
 
 func (h *MetricHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+type ParticipantHandler struct {
+	Service    *services.ParticipantService
+	Governance *services.GovernanceService
+}
+
+func NewParticipantHandler(service *services.ParticipantService, governance *services.GovernanceService) *ParticipantHandler {
+	return &ParticipantHandler{Service: service, Governance: governance}
+}
+
+func (h *ParticipantHandler) Register(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.RegistrationInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	participant, err := h.Service.Register(c.Request().Context(), hackathonID, actorID, input)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.participant.registered", participant)
+	return c.JSON(http.StatusCreated, participant)
+}
+
+func (h *ParticipantHandler) Withdraw(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	actorID := actorIDFromContext(c)
+	participant, err := h.Service.Withdraw(c.Request().Context(), hackathonID, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.participant.withdrawn", participant)
+	return c.JSON(http.StatusOK, participant)
+}
+
+func (h *ParticipantHandler) Me(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	participant, err := h.Service.Get(c.Request().Context(), hackathonID, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if participant == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "participant not found")
+	}
+	return c.JSON(http.StatusOK, participant)
+}
+
+func (h *ParticipantHandler) List(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	status := c.QueryParam("status")
+	if status != "" && status != models.ParticipantStatusRegistered && status != models.ParticipantStatusWithdrawn {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid status")
+	}
+	items, err := h.Service.List(c.Request().Context(), hackathonID, status, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+func (h *ParticipantHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
+	if h.Governance == nil {
+		return
+	}
+	raw, _ := json.Marshal(payload)
+	_ = h.Governance.AppendAudit(c.Request().Context(), models.AuditLog{
+		HackathonID: hackathonID,
+		ActorID:     actorID,
+		Action:      action,
+		Payload:     raw,
+	})
+}
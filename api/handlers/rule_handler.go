@@ -4,22 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/labstack/echo/v4"
 )
 
 type RuleHandler struct {
-	Service          *services.RuleService
-	Hackathons       *services.HackathonService
-	Governance       *services.GovernanceService
-	Publisher        events.Publisher
+	Service    *services.RuleService
+	Governance *services.GovernanceService
 }
 
-func NewRuleHandler(service *services.RuleService, hackathons *services.HackathonService, governance *services.GovernanceService, publisher events.Publisher) *RuleHandler {
-	return &RuleHandler{Service: service, Hackathons: hackathons, Governance: governance, Publisher: publisher}
+func NewRuleHandler(service *services.RuleService, governance *services.GovernanceService) *RuleHandler {
+	return &RuleHandler{Service: service, Governance: governance}
 }
 
 func (h *RuleHandler) Create(c echo.Context) error {
@@ -48,11 +46,6 @@ func (h *RuleHandler) Create(c echo.Context) error {
 		return handleServiceError(err)
 	}
 
-	h.emit(c, "hackathon.rule.created", map[string]any{
-		"hackathon_id": hackathonID,
-		"rule_id":      rule.ID,
-		"version_id":   version.ID,
-	})
 	h.audit(c, hackathonID, actorID, "hackathon.rule.created", rule)
 
 	return c.JSON(http.StatusCreated, map[string]any{
@@ -66,15 +59,15 @@ func (h *RuleHandler) ListByHackathon(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.ListByHackathon(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.ListByHackathon(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
 }
 
 func (h *RuleHandler) GetByID(c echo.Context) error {
@@ -120,15 +113,35 @@ func (h *RuleHandler) History(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.Service.History(c.Request().Context(), ruleID, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, items)
+}
+
+func (h *RuleHandler) Diff(c echo.Context) error {
+	ruleID, err := parseUUIDParam(c, "ruleId")
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.History(c.Request().Context(), ruleID, limit, offset)
+	from, err := strconv.Atoi(c.QueryParam("from"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid from")
+	}
+	to, err := strconv.Atoi(c.QueryParam("to"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid to")
+	}
+	diff, err := h.Service.Diff(c.Request().Context(), ruleID, from, to)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return c.JSON(http.StatusOK, diff)
 }
 
 func (h *RuleHandler) Update(c echo.Context) error {
@@ -196,14 +209,12 @@ func (h *RuleHandler) Activate(c echo.Context) error {
 	if !ok {
 		return echo.NewHTTPError(http.StatusBadRequest, "rule version does not belong to hackathon")
 	}
-	if err := h.Hackathons.SetActiveRuleVersion(c.Request().Context(), hackathonID, ruleVersionID); err != nil {
+	if err := h.Service.ActivateVersion(c.Request().Context(), hackathonID, ruleVersionID); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "hackathon not found")
+		}
 		return handleServiceError(err)
 	}
-
-	h.emit(c, "hackathon.rule.activated", map[string]any{
-		"hackathon_id":     hackathonID,
-		"rule_version_id":  ruleVersionID,
-	})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.rule.activated", map[string]any{
 		"rule_version_id": ruleVersionID,
 	})
@@ -211,6 +222,42 @@ func (h *RuleHandler) Activate(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"message": "rule activated"})
 }
 
+func (h *RuleHandler) Accept(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.RuleAcceptanceInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	acceptance, err := h.Service.AcceptRules(c.Request().Context(), hackathonID, actorID, input)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.rule.accepted", acceptance)
+	return c.JSON(http.StatusOK, acceptance)
+}
+
+func (h *RuleHandler) AcceptanceReport(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	ruleVersionID := ""
+	if c.QueryParam("rule_version_id") != "" {
+		if ruleVersionID, err = parseQueryUUID(c, "rule_version_id"); err != nil {
+			return err
+		}
+	}
+	reports, err := h.Service.AcceptanceReport(c.Request().Context(), hackathonID, ruleVersionID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, reports)
+}
+
 func (h *RuleHandler) LockVersion(c echo.Context) error {
 	ruleVersionID, err := parseUUIDParam(c, "ruleVersionId")
 	if err != nil {
@@ -226,24 +273,11 @@ func (h *RuleHandler) LockVersion(c echo.Context) error {
 		hackathonID = rule.HackathonID
 	}
 
-	h.emit(c, "hackathon.rule.version.locked", map[string]any{
-		"rule_id":        version.RuleID,
-		"rule_version_id": version.ID,
-	})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.rule.version.locked", version)
 
 	return c.JSON(http.StatusOK, version)
 }
 
-func (h *RuleHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
-	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
-	}
-}
-
 func (h *RuleHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
 	if h.Governance == nil {
 		return
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/DataInCube/hackathon-service/api/middlewares"
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireStaff allows callers holding one of roles, or owner, on the
+// hackathon of the route; hackathon_admin passes everywhere. It relies on
+// RequireVisible having resolved the hackathon, and fails closed when it has
+// not: 400 for a malformed id, 404 for an unknown one.
+func (h *AccessHandler) RequireStaff(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if hasAnyRole(middlewares.RolesFromContext(c), "hackathon_admin") {
+				return next(c)
+			}
+			if _, resolved := c.Get(hackathonIDContextKey).(string); !resolved {
+				param, id := hackathonScopeParam(c)
+				if param == "" {
+					// Not a hackathon-scoped route: nothing to be staff of.
+					return echo.NewHTTPError(http.StatusForbidden, "Forbidden")
+				}
+				if _, err := uuid.Parse(id); err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "invalid "+param)
+				}
+				return echo.NewHTTPError(http.StatusNotFound, "hackathon not found")
+			}
+			if !isAdminOrStaff(c, roles...) {
+				return echo.NewHTTPError(http.StatusForbidden, "Forbidden")
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireEvaluator guards evaluation callbacks: the evaluation_executor and
+// platform_admin service identities pass, anyone else must be an organizer or
+// evaluator of the hackathon of the route.
+func (h *AccessHandler) RequireEvaluator() echo.MiddlewareFunc {
+	staff := h.RequireStaff(models.StaffRoleOrganizer, models.StaffRoleEvaluator)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		guarded := staff(next)
+		return func(c echo.Context) error {
+			if hasAnyRole(middlewares.RolesFromContext(c), "platform_admin", "evaluation_executor") {
+				return next(c)
+			}
+			return guarded(c)
+		}
+	}
+}
+
+func (h *AccessHandler) ListStaff(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	staff, err := h.Service.ListStaff(c.Request().Context(), hackathonID, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, staff)
+}
+
+func (h *AccessHandler) AssignStaff(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.StaffInput
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	actorID := actorIDFromContext(c)
+	staff, err := h.Service.AssignStaff(c.Request().Context(), hackathonID, input, actorID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorID, "hackathon.staff.assigned", staff)
+	return c.JSON(http.StatusCreated, staff)
+}
+
+func (h *AccessHandler) RemoveStaff(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	staffID, err := parseUUIDParam(c, "staffId")
+	if err != nil {
+		return err
+	}
+	staff, err := h.Service.RemoveStaff(c.Request().Context(), hackathonID, staffID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.staff.removed", staff)
+	return c.NoContent(http.StatusNoContent)
+}
@@ -1,26 +1,26 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/labstack/echo/v4"
 )
 
 type SubmissionHandler struct {
 	Service    *services.SubmissionService
 	Governance *services.GovernanceService
-	Publisher  events.Publisher
 }
 
-func NewSubmissionHandler(service *services.SubmissionService, governance *services.GovernanceService, publisher events.Publisher) *SubmissionHandler {
-	return &SubmissionHandler{Service: service, Governance: governance, Publisher: publisher}
+func NewSubmissionHandler(service *services.SubmissionService, governance *services.GovernanceService) *SubmissionHandler {
+	return &SubmissionHandler{Service: service, Governance: governance}
 }
 
 func (h *SubmissionHandler) Create(c echo.Context) error {
@@ -32,15 +32,11 @@ func (h *SubmissionHandler) Create(c echo.Context) error {
 	if err := c.Bind(&input); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	sub, err := h.Service.Create(c.Request().Context(), hackathonID, input, actorIDFromContext(c))
+	sub, quota, err := h.Service.Create(c.Request().Context(), hackathonID, input, actorIDFromContext(c))
+	setQuotaHeaders(c, quota)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "submission.created", map[string]any{
-		"submission_id": sub.ID,
-		"hackathon_id":  sub.HackathonID,
-		"status":        sub.Status,
-	})
 	h.audit(c, sub.HackathonID, actorIDFromContext(c), "submission.created", sub)
 	return c.JSON(http.StatusCreated, sub)
 }
@@ -70,6 +66,9 @@ func (h *SubmissionHandler) Update(c echo.Context) error {
 		return handleServiceError(err)
 	}
 	h.audit(c, updated.HackathonID, actorIDFromContext(c), "submission.updated", updated)
+	if err := h.hidePrivateScores(c, updated.HackathonID, updated); err != nil {
+		return handleServiceError(err)
+	}
 	return c.JSON(http.StatusOK, updated)
 }
 
@@ -85,6 +84,9 @@ func (h *SubmissionHandler) GetByID(c echo.Context) error {
 	if sub == nil {
 		return echo.NewHTTPError(http.StatusNotFound, "submission not found")
 	}
+	if err := h.hidePrivateScores(c, sub.HackathonID, sub); err != nil {
+		return handleServiceError(err)
+	}
 	return c.JSON(http.StatusOK, sub)
 }
 
@@ -93,15 +95,118 @@ func (h *SubmissionHandler) ListByHackathon(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
+	if err != nil {
+		return err
+	}
+	filter := services.SubmissionFilter{
+		Status:      c.QueryParam("status"),
+		SubmittedBy: strings.TrimSpace(c.QueryParam("submitted_by")),
+		TeamID:      strings.TrimSpace(c.QueryParam("team_id")),
+	}
+	if filter.TrackID, err = parseOptionalQueryUUID(c, "track_id"); err != nil {
+		return err
+	}
+	if filter.CreatedAfter, err = parseQueryTime(c, "created_after"); err != nil {
+		return err
+	}
+	if filter.CreatedBefore, err = parseQueryTime(c, "created_before"); err != nil {
+		return err
+	}
+	subs, err := h.Service.ListByHackathon(c.Request().Context(), hackathonID, filter, page)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	refs := make([]*models.Submission, len(subs.Items))
+	for i := range subs.Items {
+		refs[i] = &subs.Items[i]
+	}
+	if err := h.hidePrivateScores(c, hackathonID, refs...); err != nil {
+		return handleServiceError(err)
+	}
+	return respondPage(c, subs)
+}
+
+func (h *SubmissionHandler) Quota(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var teamID *string
+	if raw := strings.TrimSpace(c.QueryParam("team_id")); raw != "" {
+		teamID = &raw
+	}
+	quota, err := h.Service.Quota(c.Request().Context(), hackathonID, actorIDFromContext(c), teamID)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	setQuotaHeaders(c, quota)
+	return c.JSON(http.StatusOK, quota)
+}
+
+func (h *SubmissionHandler) SelectFinal(c echo.Context) error {
+	return h.setFinalSelection(c, true)
+}
+
+func (h *SubmissionHandler) UnselectFinal(c echo.Context) error {
+	return h.setFinalSelection(c, false)
+}
+
+func (h *SubmissionHandler) setFinalSelection(c echo.Context, selected bool) error {
+	id, err := parseUUIDParam(c, "submissionId")
+	if err != nil {
+		return err
+	}
+	existing, err := h.Service.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	if existing == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "submission not found")
+	}
+	if !isAdminOrOrganizer(c) && existing.SubmittedBy != actorIDFromContext(c) {
+		return echo.NewHTTPError(http.StatusForbidden, "forbidden")
+	}
+
+	var updated *models.Submission
+	action := "submission.final.selected"
+	if selected {
+		updated, err = h.Service.SelectFinal(c.Request().Context(), id)
+	} else {
+		updated, err = h.Service.UnselectFinal(c.Request().Context(), id)
+		action = "submission.final.unselected"
+	}
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, updated.HackathonID, actorIDFromContext(c), action, map[string]string{"id": id})
+	if err := h.hidePrivateScores(c, updated.HackathonID, updated); err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *SubmissionHandler) FinalSelections(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
 	if err != nil {
 		return err
 	}
-	subs, err := h.Service.ListByHackathon(c.Request().Context(), hackathonID, limit, offset)
+	var teamID *string
+	if raw := strings.TrimSpace(c.QueryParam("team_id")); raw != "" {
+		teamID = &raw
+	}
+	selections, err := h.Service.FinalSelections(c.Request().Context(), hackathonID, actorIDFromContext(c), teamID)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, subs)
+	refs := make([]*models.Submission, len(selections.Submissions))
+	for i := range selections.Submissions {
+		refs[i] = &selections.Submissions[i]
+	}
+	if err := h.hidePrivateScores(c, hackathonID, refs...); err != nil {
+		return handleServiceError(err)
+	}
+	return c.JSON(http.StatusOK, selections)
 }
 
 func (h *SubmissionHandler) Delete(c echo.Context) error {
@@ -135,11 +240,6 @@ func (h *SubmissionHandler) Lock(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "submission.locked", map[string]any{
-		"submission_id": sub.ID,
-		"hackathon_id":  sub.HackathonID,
-		"status":        sub.Status,
-	})
 	h.audit(c, sub.HackathonID, actorIDFromContext(c), "submission.locked", sub)
 	return c.JSON(http.StatusOK, sub)
 }
@@ -152,8 +252,23 @@ func (h *SubmissionHandler) MarkEvaluationFailed(c echo.Context) error {
 	return h.updateEvaluationStatus(c, models.SubmissionStatusEvaluationFailed)
 }
 
+// MarkScored accepts a typed models.EvaluationResult validated against the
+// hackathon metrics.
 func (h *SubmissionHandler) MarkScored(c echo.Context) error {
-	return h.updateEvaluationStatus(c, models.SubmissionStatusScored)
+	id, err := parseUUIDParam(c, "submissionId")
+	if err != nil {
+		return err
+	}
+	var result models.EvaluationResult
+	if err := c.Bind(&result); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	updated, err := h.Service.RecordEvaluationResult(c.Request().Context(), id, result)
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, updated.HackathonID, actorIDFromContext(c), "submission.evaluation."+models.SubmissionStatusScored, updated)
+	return c.JSON(http.StatusOK, updated)
 }
 
 func (h *SubmissionHandler) Invalidate(c echo.Context) error {
@@ -165,247 +280,200 @@ func (h *SubmissionHandler) Invalidate(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "submission.invalidated", map[string]any{
-		"submission_id": sub.ID,
-		"hackathon_id":  sub.HackathonID,
-		"status":        sub.Status,
-	})
 	h.audit(c, sub.HackathonID, actorIDFromContext(c), "submission.invalidated", sub)
 	return c.JSON(http.StatusOK, sub)
 }
 
-func (h *SubmissionHandler) updateEvaluationStatus(c echo.Context, target string) error {
+func (h *SubmissionHandler) Evaluations(c echo.Context) error {
 	id, err := parseUUIDParam(c, "submissionId")
 	if err != nil {
 		return err
 	}
-	var payload struct {
-		Metadata json.RawMessage `json:"metadata,omitempty"`
+	sub, err := h.Service.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err)
 	}
-	if err := c.Bind(&payload); err != nil && err != io.EOF {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	if sub == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "submission not found")
 	}
-
-	var metadataPatch *json.RawMessage
-	if len(payload.Metadata) > 0 {
-		metadataPatch = &payload.Metadata
+	reviewer := isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleJudge, models.StaffRoleEvaluator)
+	if !reviewer && sub.SubmittedBy != actorIDFromContext(c) {
+		return echo.NewHTTPError(http.StatusForbidden, "forbidden")
 	}
-	updated, err := h.Service.UpdateEvaluationStatus(c.Request().Context(), id, target, metadataPatch)
+	items, err := h.Service.ListEvaluations(c.Request().Context(), id)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	if target == models.SubmissionStatusScored {
-		metadataMap := metadataToMap(updated.Metadata)
-		secondary := extractSecondaryMetricsFromMetadata(updated.Metadata)
-		updatesLeaderboard := extractBoolFromMetadata(updated.Metadata, true, "updates_leaderboard", "updatesLeaderboard")
-		payload := map[string]any{
-			"submission_id":       updated.ID,
-			"hackathon_id":        updated.HackathonID,
-			"user_id":             updated.SubmittedBy,
-			"source":              "git",
-			"is_official":         true,
-			"is_practice":         false,
-			"updates_leaderboard": updatesLeaderboard,
-			"evaluated_at":        time.Now().UTC().Format(time.RFC3339),
-			"metadata":            metadataMap,
-		}
-		if updated.TeamID != nil && *updated.TeamID != "" {
-			payload["team_id"] = *updated.TeamID
+	if !reviewer {
+		visible, err := h.Service.PrivateScoresVisible(c.Request().Context(), sub.HackathonID)
+		if err != nil {
+			return handleServiceError(err)
 		}
-		if score, ok := extractScoreFromMetadata(updated.Metadata); ok {
-			payload["score"] = score
-			payload["scores"] = map[string]any{
-				"primary":   score,
-				"secondary": secondary,
+		if !visible {
+			for i := range items {
+				items[i].PrivateScore = nil
+				items[i].Metadata = redactPrivateMetadata(items[i].Metadata)
 			}
 		}
-		if metric := extractStringFromMetadata(updated.Metadata, "primary_metric", "metric"); metric != "" {
-			payload["primary_metric"] = metric
-		}
-		if commit := extractStringFromMetadata(updated.Metadata, "commit_sha", "commit", "git_commit"); commit != "" {
-			payload["commit_sha"] = commit
-		}
-		if boardType := extractStringFromMetadata(updated.Metadata, "board_type", "boardType"); boardType != "" {
-			payload["board_type"] = boardType
-		}
-		if submittedAt := extractStringFromMetadata(updated.Metadata, "submitted_at", "submission_time", "created_at"); submittedAt != "" {
-			payload["submission_time"] = submittedAt
-		}
-		if evaluationJobID := extractStringFromMetadata(updated.Metadata, "evaluation_job_id", "job_id"); evaluationJobID != "" {
-			payload["evaluation_job_id"] = evaluationJobID
-		}
-		if practiceJobID := extractStringFromMetadata(updated.Metadata, "practice_job_id", "last_practice_job_id"); practiceJobID != "" {
-			payload["practice_job_id"] = practiceJobID
-		}
-		h.emit(c, "evaluation.completed", payload)
 	}
-	h.audit(c, updated.HackathonID, actorIDFromContext(c), "submission.evaluation."+target, updated)
-	return c.JSON(http.StatusOK, updated)
+	return c.JSON(http.StatusOK, items)
 }
 
-func (h *SubmissionHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
+func (h *SubmissionHandler) Rescore(c echo.Context) error {
+	hackathonID, err := parseUUIDParam(c, "hackathonId")
+	if err != nil {
+		return err
+	}
+	var input services.RescoreInput
+	if err := c.Bind(&input); err != nil && err != io.EOF {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
+	result, err := h.Service.Rescore(c.Request().Context(), hackathonID, input, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
 	}
+	h.audit(c, hackathonID, actorIDFromContext(c), "submission.rescore.requested", result)
+	return c.JSON(http.StatusOK, result)
 }
 
-func (h *SubmissionHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
-	if h.Governance == nil {
-		return
+func (h *SubmissionHandler) Requeue(c echo.Context) error {
+	id, err := parseUUIDParam(c, "submissionId")
+	if err != nil {
+		return err
 	}
-	raw, _ := json.Marshal(payload)
-	_ = h.Governance.AppendAudit(c.Request().Context(), models.AuditLog{
-		HackathonID: hackathonID,
-		ActorID:     actorID,
-		Action:      action,
-		Payload:     raw,
-	})
+	var payload struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	if err := c.Bind(&payload); err != nil && err != io.EOF {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	sub, err := h.Service.Requeue(c.Request().Context(), id, payload.Reason, actorIDFromContext(c))
+	if err != nil {
+		return handleServiceError(err)
+	}
+	h.audit(c, sub.HackathonID, actorIDFromContext(c), "submission.rescore.requested", sub)
+	return c.JSON(http.StatusOK, sub)
 }
 
-func extractScoreFromMetadata(raw json.RawMessage) (float64, bool) {
-	if len(raw) == 0 {
-		return 0, false
+func (h *SubmissionHandler) updateEvaluationStatus(c echo.Context, target string) error {
+	id, err := parseUUIDParam(c, "submissionId")
+	if err != nil {
+		return err
 	}
-	var payload map[string]any
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return 0, false
+	var payload struct {
+		Metadata json.RawMessage `json:"metadata,omitempty"`
+		JobID    string          `json:"job_id,omitempty"`
+		Executor string          `json:"executor,omitempty"`
+		Error    string          `json:"error,omitempty"`
 	}
-	if score, ok := numericFrom(payload["score"]); ok {
-		return score, true
+	if err := c.Bind(&payload); err != nil && err != io.EOF {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	if metrics, ok := payload["metrics"].(map[string]any); ok {
-		if score, ok := numericFrom(metrics["score"]); ok {
-			return score, true
-		}
-		if score, ok := numericFrom(metrics["primary"]); ok {
-			return score, true
-		}
-		if score, ok := numericFrom(metrics["value"]); ok {
-			return score, true
-		}
+
+	update := services.EvaluationUpdate{
+		JobID:    payload.JobID,
+		Executor: payload.Executor,
+		Error:    payload.Error,
 	}
-	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
-		if score, ok := numericFrom(evaluation["score"]); ok {
-			return score, true
-		}
+	if len(payload.Metadata) > 0 {
+		update.Metadata = &payload.Metadata
+	}
+	updated, err := h.Service.UpdateEvaluationStatus(c.Request().Context(), id, target, update)
+	if err != nil {
+		return handleServiceError(err)
 	}
-	return 0, false
+	h.audit(c, updated.HackathonID, actorIDFromContext(c), "submission.evaluation."+target, updated)
+	return c.JSON(http.StatusOK, updated)
 }
 
-func extractStringFromMetadata(raw json.RawMessage, keys ...string) string {
-	if len(raw) == 0 {
-		return ""
-	}
-	var payload map[string]any
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return ""
-	}
-	for _, key := range keys {
-		if v, ok := payload[key]; ok {
-			if s, ok := v.(string); ok {
-				return strings.TrimSpace(s)
-			}
-		}
+// hidePrivateScores clears private scores, including the ones reported in
+// metadata, for everyone but organizers, judges and evaluators until the
+// leaderboard is published.
+func (h *SubmissionHandler) hidePrivateScores(c echo.Context, hackathonID string, subs ...*models.Submission) error {
+	if isAdminOrStaff(c, models.StaffRoleOrganizer, models.StaffRoleJudge, models.StaffRoleEvaluator) {
+		return nil
+	}
+	redacted := make([]json.RawMessage, len(subs))
+	hasPrivate := false
+	for i, sub := range subs {
+		redacted[i] = redactPrivateMetadata(sub.Metadata)
+		hasPrivate = hasPrivate || sub.PrivateScore != nil || !bytes.Equal(redacted[i], sub.Metadata)
+	}
+	if !hasPrivate {
+		return nil
+	}
+	visible, err := h.Service.PrivateScoresVisible(c.Request().Context(), hackathonID)
+	if err != nil || visible {
+		return err
 	}
-	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
-		for _, key := range keys {
-			if v, ok := evaluation[key]; ok {
-				if s, ok := v.(string); ok {
-					return strings.TrimSpace(s)
-				}
-			}
-		}
+	for i, sub := range subs {
+		sub.PrivateScore = nil
+		sub.Metadata = redacted[i]
 	}
-	return ""
+	return nil
 }
 
-func extractSecondaryMetricsFromMetadata(raw json.RawMessage) map[string]any {
-	if len(raw) == 0 {
-		return map[string]any{}
-	}
-	var payload map[string]any
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return map[string]any{}
-	}
-	if scores, ok := payload["scores"].(map[string]any); ok {
-		if secondary, ok := scores["secondary"].(map[string]any); ok {
-			return secondary
+// redactPrivateMetadata drops the private scores an evaluator may report in
+// metadata: private_score, scores.private and private_metrics.
+func redactPrivateMetadata(raw json.RawMessage) json.RawMessage {
+	var payload map[string]json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &payload) != nil {
+		return raw
+	}
+	redacted := false
+	for _, key := range []string{"private_score", "private_metrics"} {
+		if _, ok := payload[key]; ok {
+			delete(payload, key)
+			redacted = true
 		}
 	}
-	for _, key := range []string{"efficiency_metrics", "secondary_metrics", "metrics_secondary"} {
-		if secondary, ok := payload[key].(map[string]any); ok {
-			return secondary
+	var scores map[string]json.RawMessage
+	if json.Unmarshal(payload["scores"], &scores) == nil {
+		if _, ok := scores["private"]; ok {
+			delete(scores, "private")
+			payload["scores"], _ = json.Marshal(scores)
+			redacted = true
 		}
 	}
-	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
-		if secondary, ok := evaluation["secondary"].(map[string]any); ok {
-			return secondary
-		}
-		if secondary, ok := evaluation["efficiency_metrics"].(map[string]any); ok {
-			return secondary
-		}
+	if !redacted {
+		return raw
+	}
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return raw
 	}
-	return map[string]any{}
+	return out
 }
 
-func extractBoolFromMetadata(raw json.RawMessage, fallback bool, keys ...string) bool {
-	if len(raw) == 0 {
-		return fallback
-	}
-	var payload map[string]any
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return fallback
-	}
-	for _, key := range keys {
-		value, ok := payload[key]
-		if !ok {
-			continue
-		}
-		switch typed := value.(type) {
-		case bool:
-			return typed
-		case string:
-			lower := strings.ToLower(strings.TrimSpace(typed))
-			if lower == "true" || lower == "1" || lower == "yes" {
-				return true
-			}
-			if lower == "false" || lower == "0" || lower == "no" {
-				return false
-			}
-		}
+func (h *SubmissionHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
+	if h.Governance == nil {
+		return
 	}
-	return fallback
+	raw, _ := json.Marshal(payload)
+	_ = h.Governance.AppendAudit(c.Request().Context(), models.AuditLog{
+		HackathonID: hackathonID,
+		ActorID:     actorID,
+		Action:      action,
+		Payload:     raw,
+	})
 }
 
-func metadataToMap(raw json.RawMessage) map[string]any {
-	if len(raw) == 0 {
-		return map[string]any{}
+func setQuotaHeaders(c echo.Context, quota *models.SubmissionQuota) {
+	if quota == nil {
+		return
 	}
-	var payload map[string]any
-	if err := json.Unmarshal(raw, &payload); err != nil {
-		return map[string]any{}
+	header := c.Response().Header()
+	if quota.RemainingToday != nil {
+		header.Set("X-Submission-Limit-Day", strconv.Itoa(quota.PerDay))
+		header.Set("X-Submission-Remaining-Day", strconv.Itoa(*quota.RemainingToday))
 	}
-	return payload
-}
-
-func numericFrom(v any) (float64, bool) {
-	switch n := v.(type) {
-	case float64:
-		return n, true
-	case float32:
-		return float64(n), true
-	case int:
-		return float64(n), true
-	case int64:
-		return float64(n), true
-	case json.Number:
-		f, err := n.Float64()
-		if err == nil {
-			return f, true
-		}
+	if quota.RemainingTotal != nil {
+		header.Set("X-Submission-Limit-Total", strconv.Itoa(quota.Total))
+		header.Set("X-Submission-Remaining-Total", strconv.Itoa(*quota.RemainingTotal))
+	}
+	if quota.TeamID != nil && quota.RemainingTeam != nil {
+		header.Set("X-Submission-Limit-Team", strconv.Itoa(quota.PerTeam))
+		header.Set("X-Submission-Remaining-Team", strconv.Itoa(*quota.RemainingTeam))
 	}
-	return 0, false
+	header.Set("X-Submission-Quota-Reset", quota.ResetsAt.Format(time.RFC3339))
 }
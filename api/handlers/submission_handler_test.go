@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	testSubmissionID = "0d8f2a3e-5b7c-4e1a-9c2d-3f4a5b6c7d8e"
+	testHackathonID  = "5f3de833-5f0b-4675-b0b6-7e9ef32ed500"
+	testParticipant  = "participant-1"
+	privateMetadata  = `{"metrics": {"f1": 0.8}, "private_metrics": {"f1": 0.75}, "private_score": 0.75, "scores": {"public": 0.8, "private": 0.75}}`
+)
+
+func submissionRows(metadata string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{
+		"id", "hackathon_id", "track_id", "rule_version_id", "dataset_version_id", "submitted_by",
+		"team_id", "status", "phase", "metadata", "public_score", "private_score", "composite_score",
+		"final_selection", "final_selected_at", "created_at", "updated_at", "locked_at", "invalidated_at",
+	}).AddRow(
+		testSubmissionID, testHackathonID, nil, "0b6c7d6e-1f6a-4c43-9d0b-2a3b4c5d6e7f", nil, testParticipant,
+		nil, models.SubmissionStatusScored, "live", []byte(metadata), 0.8, 0.75, nil,
+		"", nil, now, now, nil, nil,
+	)
+}
+
+func participantContext(t *testing.T, method string) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("submissionId")
+	c.SetParamValues(testSubmissionID)
+	c.Set("user_id", testParticipant)
+	return c, rec
+}
+
+func TestEvaluations_RedactsPrivateMetricsForParticipants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("FROM submissions WHERE id").WillReturnRows(submissionRows(`{}`))
+	mock.ExpectQuery("FROM submission_evaluations").WillReturnRows(sqlmock.NewRows([]string{
+		"id", "submission_id", "hackathon_id", "attempt", "job_id", "executor",
+		"status", "error", "public_score", "private_score", "composite_score", "secondary_scores", "metadata",
+		"started_at", "finished_at", "superseded_at", "rescore_reason", "requested_by", "created_at", "updated_at",
+	}).AddRow(
+		"7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d", testSubmissionID, testHackathonID, 1, "", "",
+		models.SubmissionStatusScored, "", 0.8, 0.75, nil, []byte(`{}`), []byte(privateMetadata),
+		now, now, nil, "", "", now, now,
+	))
+	mock.ExpectQuery("SELECT leaderboard_published FROM hackathons").
+		WillReturnRows(sqlmock.NewRows([]string{"leaderboard_published"}).AddRow(false))
+
+	h := NewSubmissionHandler(services.NewSubmissionService(db, nil, nil), nil)
+	c, rec := participantContext(t, http.MethodGet)
+	if err := h.Evaluations(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := rec.Body.String()
+	for _, leaked := range []string{"private_metrics", "private_score", `"private"`, "0.75"} {
+		if strings.Contains(body, leaked) {
+			t.Fatalf("response leaks %s: %s", leaked, body)
+		}
+	}
+	if !strings.Contains(body, `"metrics"`) {
+		t.Fatalf("public metrics should be kept: %s", body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetByID_RedactsPrivateMetricsForParticipants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("FROM submissions WHERE id").WillReturnRows(submissionRows(privateMetadata))
+	mock.ExpectQuery("SELECT leaderboard_published FROM hackathons").
+		WillReturnRows(sqlmock.NewRows([]string{"leaderboard_published"}).AddRow(false))
+
+	h := NewSubmissionHandler(services.NewSubmissionService(db, nil, nil), nil)
+	c, rec := participantContext(t, http.MethodGet)
+	if err := h.GetByID(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := rec.Body.String()
+	for _, leaked := range []string{"private_metrics", "private_score", `"private"`, "0.75"} {
+		if strings.Contains(body, leaked) {
+			t.Fatalf("response leaks %s: %s", leaked, body)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRedactPrivateMetadata(t *testing.T) {
+	raw := []byte(`{"score": 1}`)
+	if got := redactPrivateMetadata(raw); string(got) != string(raw) {
+		t.Fatalf("metadata without private keys should be returned as is, got %s", got)
+	}
+	got := string(redactPrivateMetadata([]byte(privateMetadata)))
+	if strings.Contains(got, "private") || !strings.Contains(got, `"public":0.8`) {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+}
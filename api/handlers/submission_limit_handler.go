@@ -7,18 +7,16 @@ import (
 
 	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/DataInCube/hackathon-service/internal/models"
-	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/labstack/echo/v4"
 )
 
 type SubmissionLimitHandler struct {
 	Service    *services.SubmissionLimitService
 	Governance *services.GovernanceService
-	Publisher  events.Publisher
 }
 
-func NewSubmissionLimitHandler(service *services.SubmissionLimitService, governance *services.GovernanceService, publisher events.Publisher) *SubmissionLimitHandler {
-	return &SubmissionLimitHandler{Service: service, Governance: governance, Publisher: publisher}
+func NewSubmissionLimitHandler(service *services.SubmissionLimitService, governance *services.GovernanceService) *SubmissionLimitHandler {
+	return &SubmissionLimitHandler{Service: service, Governance: governance}
 }
 
 func (h *SubmissionLimitHandler) Create(c echo.Context) error {
@@ -34,7 +32,6 @@ func (h *SubmissionLimitHandler) Create(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.submission_limits.created", map[string]any{"hackathon_id": hackathonID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.submission_limits.created", created)
 	return c.JSON(http.StatusCreated, created)
 }
@@ -67,7 +64,6 @@ func (h *SubmissionLimitHandler) Update(c echo.Context) error {
 	if err != nil {
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.submission_limits.updated", map[string]any{"hackathon_id": hackathonID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.submission_limits.updated", updated)
 	return c.JSON(http.StatusOK, updated)
 }
@@ -83,20 +79,10 @@ func (h *SubmissionLimitHandler) Delete(c echo.Context) error {
 		}
 		return handleServiceError(err)
 	}
-	h.emit(c, "hackathon.submission_limits.deleted", map[string]any{"hackathon_id": hackathonID})
 	h.audit(c, hackathonID, actorIDFromContext(c), "hackathon.submission_limits.deleted", map[string]string{"hackathon_id": hackathonID})
 	return c.JSON(http.StatusOK, map[string]string{"message": "deleted"})
 }
 
-func (h *SubmissionLimitHandler) emit(c echo.Context, subject string, payload any) {
-	if h.Publisher == nil {
-		return
-	}
-	if err := h.Publisher.Publish(c.Request().Context(), subject, payload); err != nil {
-		c.Logger().Error(err)
-	}
-}
-
 func (h *SubmissionLimitHandler) audit(c echo.Context, hackathonID, actorID, action string, payload any) {
 	if h.Governance == nil {
 		return
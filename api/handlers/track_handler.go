@@ -60,15 +60,15 @@ func (h *TrackHandler) List(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	limit, offset, err := parseLimitOffset(c)
+	page, err := parsePageRequest(c)
 	if err != nil {
 		return err
 	}
-	items, err := h.Service.List(c.Request().Context(), hackathonID, limit, offset)
+	items, err := h.Service.List(c.Request().Context(), hackathonID, page)
 	if err != nil {
 		return handleServiceError(err)
 	}
-	return c.JSON(http.StatusOK, items)
+	return respondPage(c, items)
 }
 
 func (h *TrackHandler) Update(c echo.Context) error {
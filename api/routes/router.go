@@ -2,10 +2,12 @@ package routes
 
 import (
 	"database/sql"
+	"net/http"
 
 	"github.com/DataInCube/hackathon-service/api/handlers"
 	"github.com/DataInCube/hackathon-service/api/middlewares"
 	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/models"
 	"github.com/DataInCube/hackathon-service/pkg/events"
 
 	"github.com/labstack/echo/v4"
@@ -13,30 +15,36 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func RegisterRoutes(e *echo.Echo, db *sql.DB, logger *logrus.Logger, authMiddleware echo.MiddlewareFunc, serviceName, serviceVersion string, publisher events.Publisher) {
+func RegisterRoutes(e *echo.Echo, db *sql.DB, logger *logrus.Logger, authMiddleware echo.MiddlewareFunc, serviceName, serviceVersion string, publisher events.Publisher, storage services.DatasetStorage) {
 	// Middleware global (logger, recover, CORS, etc. à ajouter ici si besoin)
 
 	// Injecter les services
-	hackathonService := services.NewHackathonService(db)
+	hackathonService := services.NewHackathonService(db, publisher)
 	trackService := services.NewTrackService(db)
-	ruleService := services.NewRuleService(db)
-	submissionService := services.NewSubmissionService(db, trackService)
+	ruleService := services.NewRuleService(db, publisher)
+	submissionService := services.NewSubmissionService(db, trackService, publisher)
 	resourceService := services.NewResourceService(db)
-	datasetService := services.NewDatasetService(db)
-	metricService := services.NewMetricService(db)
-	submissionLimitService := services.NewSubmissionLimitService(db)
-	governanceService := services.NewGovernanceService(db)
+	datasetService := services.NewDatasetService(db, storage, publisher)
+	metricService := services.NewMetricService(db, publisher)
+	submissionLimitService := services.NewSubmissionLimitService(db, publisher)
+	governanceService := services.NewGovernanceService(db, publisher)
+	participantService := services.NewParticipantService(db, publisher)
+	leaderboardService := services.NewLeaderboardService(db, publisher)
+	accessService := services.NewAccessService(db)
 
 	// Injecter les handlers
-	hackathonHandler := handlers.NewHackathonHandler(hackathonService, governanceService, publisher)
+	hackathonHandler := handlers.NewHackathonHandler(hackathonService, governanceService)
 	trackHandler := handlers.NewTrackHandler(trackService, governanceService)
-	ruleHandler := handlers.NewRuleHandler(ruleService, hackathonService, governanceService, publisher)
-	submissionHandler := handlers.NewSubmissionHandler(submissionService, governanceService, publisher)
+	ruleHandler := handlers.NewRuleHandler(ruleService, governanceService)
+	submissionHandler := handlers.NewSubmissionHandler(submissionService, governanceService)
 	resourceHandler := handlers.NewResourceHandler(resourceService, governanceService)
-	dataHandler := handlers.NewDataHandler(datasetService, governanceService, publisher)
-	metricHandler := handlers.NewMetricHandler(metricService, governanceService, publisher)
-	submissionLimitHandler := handlers.NewSubmissionLimitHandler(submissionLimitService, governanceService, publisher)
+	dataHandler := handlers.NewDataHandler(datasetService, governanceService)
+	metricHandler := handlers.NewMetricHandler(metricService, governanceService)
+	submissionLimitHandler := handlers.NewSubmissionLimitHandler(submissionLimitService, governanceService)
 	governanceHandler := handlers.NewGovernanceHandler(governanceService)
+	participantHandler := handlers.NewParticipantHandler(participantService, governanceService)
+	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, governanceService)
+	accessHandler := handlers.NewAccessHandler(accessService, governanceService)
 
 	// Routes protégées par authentification
 	api := e.Group("/api/v1")
@@ -46,96 +54,171 @@ func RegisterRoutes(e *echo.Echo, db *sql.DB, logger *logrus.Logger, authMiddlew
 
 	adminOrOrganizer := middlewares.RequireAnyRole("hackathon_admin", "hackathon_organizer")
 
+	// Hackathons the caller may not see answer 404 on every route scoped to
+	// them; registered before the routes so it wraps all of them.
+	api.Use(accessHandler.RequireVisible)
+
+	// Per-hackathon staff roles; owners pass all of them and hackathon_admin
+	// overrides them platform-wide.
+	owners := accessHandler.RequireStaff()
+	organizers := accessHandler.RequireStaff(models.StaffRoleOrganizer)
+	reviewers := accessHandler.RequireStaff(models.StaffRoleOrganizer, models.StaffRoleJudge)
+	evaluators := accessHandler.RequireStaff(models.StaffRoleOrganizer, models.StaffRoleEvaluator)
+	moderators := accessHandler.RequireStaff(models.StaffRoleOrganizer, models.StaffRoleModerator)
+
 	// Hackathon routes
 	api.POST("/hackathons", hackathonHandler.Create, adminOrOrganizer)
 	api.GET("/hackathons", hackathonHandler.List)
+	api.GET("/hackathons/search", hackathonHandler.Search)
 	api.GET("/hackathons/:hackathonId", hackathonHandler.GetByID)
-	api.PUT("/hackathons/:hackathonId", hackathonHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId", hackathonHandler.Delete, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/publish", hackathonHandler.Publish, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/transition", hackathonHandler.Transition, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId", hackathonHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId", hackathonHandler.Delete, organizers)
+	api.POST("/hackathons/:hackathonId/publish", hackathonHandler.Publish, organizers)
+	api.POST("/hackathons/:hackathonId/transition", hackathonHandler.Transition, organizers)
 	api.GET("/hackathons/:hackathonId/state", hackathonHandler.GetState)
+	api.GET("/hackathons/:hackathonId/schedule", hackathonHandler.GetSchedule)
+	api.PUT("/hackathons/:hackathonId/schedule", hackathonHandler.PutSchedule, organizers)
+	api.DELETE("/hackathons/:hackathonId/schedule", hackathonHandler.DeleteSchedule, organizers)
+
+	// Staff of a hackathon
+	api.GET("/hackathons/:hackathonId/staff", accessHandler.ListStaff, organizers)
+	api.POST("/hackathons/:hackathonId/staff", accessHandler.AssignStaff, owners)
+	api.DELETE("/hackathons/:hackathonId/staff/:staffId", accessHandler.RemoveStaff, owners)
+
+	// Members & invites of private and invite-only hackathons
+	api.GET("/hackathons/:hackathonId/members", accessHandler.ListMembers, organizers)
+	api.POST("/hackathons/:hackathonId/members", accessHandler.AddMembers, organizers)
+	api.DELETE("/hackathons/:hackathonId/members/:userId", accessHandler.RemoveMember, organizers)
+	api.POST("/hackathons/:hackathonId/invites", accessHandler.CreateInvite, organizers)
+	api.GET("/hackathons/:hackathonId/invites", accessHandler.ListInvites, organizers)
+	api.DELETE("/hackathons/:hackathonId/invites/:inviteId", accessHandler.RevokeInvite, organizers)
+	api.POST("/invites/redeem", accessHandler.RedeemInvite)
 
 	// Tracks & rules
-	api.POST("/hackathons/:hackathonId/tracks", trackHandler.Create, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/tracks", trackHandler.Create, organizers)
 	api.GET("/hackathons/:hackathonId/tracks", trackHandler.List)
 	api.GET("/hackathons/:hackathonId/tracks/:trackId", trackHandler.GetByID)
-	api.PUT("/hackathons/:hackathonId/tracks/:trackId", trackHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/tracks/:trackId", trackHandler.Delete, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/tracks/:trackId", trackHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId/tracks/:trackId", trackHandler.Delete, organizers)
 	api.GET("/hackathons/:hackathonId/rules", ruleHandler.ListByHackathon)
-	api.POST("/hackathons/:hackathonId/rules", ruleHandler.Create, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/rules", ruleHandler.Create, organizers)
 	api.GET("/rules/:ruleId", ruleHandler.GetByID)
-	api.PUT("/rules/:ruleId", ruleHandler.Update, adminOrOrganizer)
-	api.DELETE("/rules/:ruleId", ruleHandler.Delete, adminOrOrganizer)
-	api.POST("/rules/:ruleId/version", ruleHandler.CreateVersion, adminOrOrganizer)
-	api.POST("/rules/:ruleId/versions", ruleHandler.CreateVersion, adminOrOrganizer)
-	api.POST("/rules/versions/:ruleVersionId/lock", ruleHandler.LockVersion, adminOrOrganizer)
+	api.PUT("/rules/:ruleId", ruleHandler.Update, organizers)
+	api.DELETE("/rules/:ruleId", ruleHandler.Delete, organizers)
+	api.POST("/rules/:ruleId/version", ruleHandler.CreateVersion, organizers)
+	api.POST("/rules/:ruleId/versions", ruleHandler.CreateVersion, organizers)
+	api.POST("/rules/versions/:ruleVersionId/lock", ruleHandler.LockVersion, organizers)
 	api.GET("/rules/:ruleId/history", ruleHandler.History)
-	api.POST("/hackathons/:hackathonId/rules/:ruleVersionId/activate", ruleHandler.Activate, adminOrOrganizer)
+	api.GET("/rules/:ruleId/diff", ruleHandler.Diff)
+	api.POST("/hackathons/:hackathonId/rules/:ruleVersionId/activate", ruleHandler.Activate, organizers)
+	api.POST("/hackathons/:hackathonId/rules/accept", ruleHandler.Accept)
+	api.GET("/hackathons/:hackathonId/rules/acceptances", ruleHandler.AcceptanceReport, organizers)
 
 	// Team policy
 	api.GET("/hackathons/:hackathonId/team-policy", hackathonHandler.TeamPolicy)
 	api.POST("/hackathons/:hackathonId/teams/validate", hackathonHandler.ValidateTeam)
 
+	// Participants
+	api.POST("/hackathons/:hackathonId/participants/register", participantHandler.Register)
+	api.POST("/hackathons/:hackathonId/participants/withdraw", participantHandler.Withdraw)
+	api.GET("/hackathons/:hackathonId/participants/me", participantHandler.Me)
+	api.GET("/hackathons/:hackathonId/participants", participantHandler.List, reviewers)
+
 	// Submissions
 	api.POST("/hackathons/:hackathonId/submissions", submissionHandler.Create)
 	api.GET("/hackathons/:hackathonId/submissions", submissionHandler.ListByHackathon)
+	api.GET("/hackathons/:hackathonId/submissions/quota", submissionHandler.Quota)
+	api.GET("/hackathons/:hackathonId/submissions/final", submissionHandler.FinalSelections)
+	api.POST("/hackathons/:hackathonId/submissions/rescore", submissionHandler.Rescore, evaluators)
 	api.GET("/submissions/:submissionId", submissionHandler.GetByID)
+	api.GET("/submissions/:submissionId/evaluations", submissionHandler.Evaluations)
 	api.PUT("/submissions/:submissionId", submissionHandler.Update)
 	api.DELETE("/submissions/:submissionId", submissionHandler.Delete)
-	api.POST("/submissions/:submissionId/lock", submissionHandler.Lock, adminOrOrganizer)
-	evaluationRole := middlewares.RequireAnyRole("hackathon_admin", "hackathon_organizer", "platform_admin", "evaluation_executor")
+	api.POST("/submissions/:submissionId/final", submissionHandler.SelectFinal)
+	api.DELETE("/submissions/:submissionId/final", submissionHandler.UnselectFinal)
+	api.POST("/submissions/:submissionId/lock", submissionHandler.Lock, moderators)
+	// Evaluation callbacks come from the evaluation service, or from the
+	// hackathon's own organizers and evaluators.
+	evaluationRole := accessHandler.RequireEvaluator()
 	api.POST("/submissions/:submissionId/evaluation/start", submissionHandler.MarkEvaluationRunning, evaluationRole)
 	api.POST("/submissions/:submissionId/evaluation/fail", submissionHandler.MarkEvaluationFailed, evaluationRole)
 	api.POST("/submissions/:submissionId/evaluation/score", submissionHandler.MarkScored, evaluationRole)
-	api.POST("/submissions/:submissionId/invalidate", submissionHandler.Invalidate, adminOrOrganizer)
+	api.POST("/submissions/:submissionId/invalidate", submissionHandler.Invalidate, moderators)
+	api.POST("/submissions/:submissionId/requeue", submissionHandler.Requeue, evaluators)
 
-	// Leaderboard policy
+	// Leaderboard
+	api.GET("/hackathons/:hackathonId/leaderboard", leaderboardHandler.Get)
+	api.POST("/hackathons/:hackathonId/leaderboard/rebuild", leaderboardHandler.Rebuild, organizers)
 	api.GET("/hackathons/:hackathonId/leaderboard-policy", hackathonHandler.LeaderboardPolicy)
-	api.POST("/hackathons/:hackathonId/leaderboard/freeze", hackathonHandler.FreezeLeaderboard, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/leaderboard/unfreeze", hackathonHandler.UnfreezeLeaderboard, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/leaderboard/publish", hackathonHandler.PublishLeaderboard, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/leaderboard/freeze", hackathonHandler.FreezeLeaderboard, organizers)
+	api.POST("/hackathons/:hackathonId/leaderboard/unfreeze", hackathonHandler.UnfreezeLeaderboard, organizers)
+	api.POST("/hackathons/:hackathonId/leaderboard/publish", hackathonHandler.PublishLeaderboard, organizers)
 
 	// Resources
 	api.GET("/hackathons/:hackathonId/resources", resourceHandler.List)
-	api.POST("/hackathons/:hackathonId/resources", resourceHandler.Create, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/resources", resourceHandler.Create, organizers)
 	api.GET("/hackathons/:hackathonId/resources/:resourceId", resourceHandler.GetByID)
-	api.PUT("/hackathons/:hackathonId/resources/:resourceId", resourceHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/resources/:resourceId", resourceHandler.Delete, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/resources/:resourceId", resourceHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId/resources/:resourceId", resourceHandler.Delete, organizers)
 
 	// Data (datasets, files, variables)
-	api.POST("/hackathons/:hackathonId/data", dataHandler.Create, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/data", dataHandler.Create, organizers)
 	api.GET("/hackathons/:hackathonId/data", dataHandler.Get)
-	api.PUT("/hackathons/:hackathonId/data", dataHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/data", dataHandler.Delete, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/data/files", dataHandler.CreateFile, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/data", dataHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId/data", dataHandler.Delete, organizers)
+	api.POST("/hackathons/:hackathonId/data/files", dataHandler.CreateFile, organizers)
 	api.GET("/hackathons/:hackathonId/data/files", dataHandler.ListFiles)
 	api.GET("/hackathons/:hackathonId/data/files/:fileId", dataHandler.GetFile)
-	api.PUT("/hackathons/:hackathonId/data/files/:fileId", dataHandler.UpdateFile, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/data/files/:fileId", dataHandler.DeleteFile, adminOrOrganizer)
-	api.POST("/hackathons/:hackathonId/data/variables", dataHandler.CreateVariable, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/data/files/:fileId", dataHandler.UpdateFile, organizers)
+	api.DELETE("/hackathons/:hackathonId/data/files/:fileId", dataHandler.DeleteFile, organizers)
+	api.GET("/hackathons/:hackathonId/data/files/:fileId/download", dataHandler.DownloadFile)
+	api.POST("/hackathons/:hackathonId/data/files/upload", dataHandler.UploadFile, organizers)
+	api.POST("/hackathons/:hackathonId/data/files/uploads", dataHandler.CreateUpload, organizers)
+	api.GET("/hackathons/:hackathonId/data/files/uploads/:uploadId", dataHandler.GetUpload, organizers)
+	api.PATCH("/hackathons/:hackathonId/data/files/uploads/:uploadId", dataHandler.AppendUpload, organizers)
+	api.POST("/hackathons/:hackathonId/data/files/uploads/:uploadId/complete", dataHandler.CompleteUpload, organizers)
+	api.DELETE("/hackathons/:hackathonId/data/files/uploads/:uploadId", dataHandler.AbortUpload, organizers)
+	api.POST("/hackathons/:hackathonId/data/files/:fileId/profile", dataHandler.RequestProfile, organizers)
+	api.GET("/hackathons/:hackathonId/data/files/:fileId/profile", dataHandler.GetProfile, organizers)
+	api.POST("/hackathons/:hackathonId/data/files/:fileId/profile/accept", dataHandler.AcceptProfile, organizers)
+	api.POST("/hackathons/:hackathonId/data/versions", dataHandler.CreateVersion, organizers)
+	api.GET("/hackathons/:hackathonId/data/versions", dataHandler.ListVersions)
+	api.GET("/hackathons/:hackathonId/data/versions/diff", dataHandler.DiffVersions)
+	api.GET("/hackathons/:hackathonId/data/versions/:version", dataHandler.GetVersion)
+	api.GET("/hackathons/:hackathonId/data/versions/:version/files/:fileId/download", dataHandler.DownloadVersionFile)
+	api.POST("/hackathons/:hackathonId/data/variables", dataHandler.CreateVariable, organizers)
 	api.GET("/hackathons/:hackathonId/data/variables", dataHandler.ListVariables)
 	api.GET("/hackathons/:hackathonId/data/variables/:variableId", dataHandler.GetVariable)
-	api.PUT("/hackathons/:hackathonId/data/variables/:variableId", dataHandler.UpdateVariable, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/data/variables/:variableId", dataHandler.DeleteVariable, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/data/variables/:variableId", dataHandler.UpdateVariable, organizers)
+	api.DELETE("/hackathons/:hackathonId/data/variables/:variableId", dataHandler.DeleteVariable, organizers)
 
 	// Evaluation metrics
-	api.POST("/hackathons/:hackathonId/metrics", metricHandler.Create, adminOrOrganizer)
+	api.GET("/metrics/types", metricHandler.Types)
+	api.POST("/hackathons/:hackathonId/metrics", metricHandler.Create, organizers)
 	api.GET("/hackathons/:hackathonId/metrics", metricHandler.List)
 	api.GET("/hackathons/:hackathonId/metrics/:metricId", metricHandler.GetByID)
-	api.PUT("/hackathons/:hackathonId/metrics/:metricId", metricHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/metrics/:metricId", metricHandler.Delete, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/metrics/:metricId", metricHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId/metrics/:metricId", metricHandler.Delete, organizers)
+	api.POST("/hackathons/:hackathonId/metrics/:metricId/verify", metricHandler.Verify, organizers)
 
 	// Submission limits
-	api.POST("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Create, adminOrOrganizer)
+	api.POST("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Create, organizers)
 	api.GET("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Get)
-	api.PUT("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Update, adminOrOrganizer)
-	api.DELETE("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Delete, adminOrOrganizer)
+	api.PUT("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Update, organizers)
+	api.DELETE("/hackathons/:hackathonId/submission-limits", submissionLimitHandler.Delete, organizers)
 
 	// Governance & audit
 	api.POST("/hackathons/:hackathonId/reports", governanceHandler.CreateReport)
+	api.GET("/hackathons/:hackathonId/reports", governanceHandler.ListReports, moderators)
+	api.GET("/reports/:reportId", governanceHandler.GetReport)
+	api.POST("/reports/:reportId/assign", governanceHandler.AssignReport, moderators)
+	api.POST("/reports/:reportId/transition", governanceHandler.TransitionReport)
 	api.POST("/appeals", governanceHandler.CreateAppeal)
-	api.GET("/audit/hackathons/:hackathonId", governanceHandler.AuditHackathon, adminOrOrganizer)
+	api.GET("/hackathons/:hackathonId/appeals", governanceHandler.ListAppeals, moderators)
+	api.GET("/appeals/:appealId", governanceHandler.GetAppeal)
+	api.POST("/appeals/:appealId/assign", governanceHandler.AssignAppeal, moderators)
+	api.POST("/appeals/:appealId/transition", governanceHandler.TransitionAppeal)
+	api.GET("/audit/hackathons/:hackathonId", governanceHandler.AuditHackathon, moderators)
 
 	// Healthcheck route
 	e.GET("/health", func(c echo.Context) error {
@@ -152,4 +235,10 @@ func RegisterRoutes(e *echo.Echo, db *sql.DB, logger *logrus.Logger, authMiddlew
 	})
 
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	// Signed download links of the local blob store; they carry their own
+	// authorization and are served outside /api/v1.
+	if blobs, ok := storage.Blobs.(http.Handler); ok {
+		e.GET("/blobs/*", echo.WrapHandler(http.StripPrefix("/blobs", blobs)))
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/DataInCube/hackathon-service/api/services"
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
 )
@@ -21,7 +22,7 @@ func hasRoute(routes []*echo.Route, method, path string) bool {
 
 func TestRegisterRoutes_RegistersCoreEndpoints(t *testing.T) {
 	e := echo.New()
-	RegisterRoutes(e, nil, logrus.New(), nil, "hackathon-service", "1.2.3", nil)
+	RegisterRoutes(e, nil, logrus.New(), nil, "hackathon-service", "1.2.3", nil, services.DatasetStorage{})
 
 	routes := e.Routes()
 	checks := []struct {
@@ -48,7 +49,7 @@ func TestRegisterRoutes_RegistersCoreEndpoints(t *testing.T) {
 
 func TestRegisterRoutes_HealthAndVersionHandlers(t *testing.T) {
 	e := echo.New()
-	RegisterRoutes(e, nil, logrus.New(), nil, "hackathon-service", "1.2.3", nil)
+	RegisterRoutes(e, nil, logrus.New(), nil, "hackathon-service", "1.2.3", nil, services.DatasetStorage{})
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	rec := httptest.NewRecorder()
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+// compositeComponent is one term of the composite score: an overall metric, or
+// the per_target metrics of one metric type averaged over target variables.
+type compositeComponent struct {
+	sum    float64
+	weight float64
+	n      int
+}
+
+// normalizeMetric maps a metric value into [0, 1] with 1 the best value in
+// the metric's direction. Metric types with a known range are scaled over it
+// and clamped; unbounded ones, such as errors, use v/(1+v) with negative
+// values clamped to 0. The result depends on the value alone, so a stored
+// composite does not move when other submissions are scored.
+func normalizeMetric(m models.EvaluationMetric, v float64) float64 {
+	var n float64
+	if spec, ok := metricTypeRegistry[m.MetricType]; ok && len(spec.Range) == 2 {
+		lo, hi := spec.Range[0], spec.Range[1]
+		n = min(max((v-lo)/(hi-lo), 0), 1)
+	} else {
+		v = max(v, 0)
+		n = v / (1 + v)
+	}
+	if m.Direction == models.MetricDirectionMinimize {
+		n = 1 - n
+	}
+	return n
+}
+
+// compositeScore combines metric values keyed by metric name into one score
+// in [0, 1] where higher is better. Each value is normalized with
+// normalizeMetric, per_target metrics of the same metric_type are averaged over their targets,
+// and the components are averaged with their weights. Metrics with a weight
+// <= 0 are ignored. It reports false when a weighted metric is missing or no
+// metric carries weight.
+func compositeScore(values map[string]float64, metrics []models.EvaluationMetric) (float64, bool) {
+	components := map[string]*compositeComponent{}
+	var order []string
+	for _, m := range metrics {
+		if m.Weight <= 0 {
+			continue
+		}
+		v, ok := values[m.Name]
+		if !ok {
+			return 0, false
+		}
+		key := "metric:" + m.Name
+		if m.Scope == models.MetricScopePerTarget {
+			key = "per_target:" + m.MetricType
+		}
+		c, ok := components[key]
+		if !ok {
+			c = &compositeComponent{}
+			components[key] = c
+			order = append(order, key)
+		}
+		c.sum += normalizeMetric(m, v)
+		c.weight += m.Weight
+		c.n++
+	}
+	if len(order) == 0 {
+		return 0, false
+	}
+
+	total, weights := 0.0, 0.0
+	for _, key := range order {
+		c := components[key]
+		weight := c.weight / float64(c.n)
+		total += weight * c.sum / float64(c.n)
+		weights += weight
+	}
+	return total / weights, true
+}
+
+// boardMetricValues reads the metric values reported for board from
+// submission metadata. The private board overlays private_metrics on metrics.
+func boardMetricValues(payload map[string]any, board string) map[string]float64 {
+	blocks := []string{"metrics"}
+	if board == models.LeaderboardBoardPrivate {
+		blocks = append(blocks, "private_metrics")
+	}
+	values := map[string]float64{}
+	for _, block := range blocks {
+		reported, _ := payload[block].(map[string]any)
+		for name, raw := range reported {
+			if v, ok := numericFrom(raw); ok {
+				values[name] = v
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestCompositeScore(t *testing.T) {
+	metrics := []models.EvaluationMetric{
+		{Name: "accuracy", MetricType: "accuracy", Direction: models.MetricDirectionMaximize, Scope: models.MetricScopeOverall, Weight: 2},
+		{Name: "rmse_a", MetricType: "rmse", Direction: models.MetricDirectionMinimize, Scope: models.MetricScopePerTarget, TargetVariable: "a", Weight: 1},
+		{Name: "rmse_b", MetricType: "rmse", Direction: models.MetricDirectionMinimize, Scope: models.MetricScopePerTarget, TargetVariable: "b", Weight: 1},
+		{Name: "notes_len", Direction: models.MetricDirectionMaximize, Scope: models.MetricScopeOverall, Weight: 0},
+	}
+
+	// accuracy keeps its value 0.9 with weight 2; rmse_a 0.25 normalizes to
+	// 0.8 and rmse_b 1 to 0.5, averaging 0.65 with weight 1.
+	got, ok := compositeScore(map[string]float64{"accuracy": 0.9, "rmse_a": 0.25, "rmse_b": 1}, metrics)
+	if !ok {
+		t.Fatal("expected a composite score")
+	}
+	if want := (2*0.9 + 1*0.65) / 3; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if _, ok := compositeScore(map[string]float64{"accuracy": 0.9, "rmse_a": 0.2}, metrics); ok {
+		t.Fatal("expected no composite when a weighted metric is missing")
+	}
+	if _, ok := compositeScore(map[string]float64{"notes_len": 3}, metrics[3:]); ok {
+		t.Fatal("expected no composite without weighted metrics")
+	}
+}
+
+func TestNormalizeMetric(t *testing.T) {
+	cases := []struct {
+		name   string
+		metric models.EvaluationMetric
+		value  float64
+		want   float64
+	}{
+		{"unit range", models.EvaluationMetric{MetricType: "f1", Direction: models.MetricDirectionMaximize}, 0.9, 0.9},
+		{"clamped above", models.EvaluationMetric{MetricType: "accuracy", Direction: models.MetricDirectionMaximize}, 1.2, 1},
+		{"clamped below", models.EvaluationMetric{MetricType: "r2", Direction: models.MetricDirectionMaximize}, -3, 0},
+		{"signed range", models.EvaluationMetric{MetricType: "mcc", Direction: models.MetricDirectionMaximize}, 0, 0.5},
+		{"unbounded error", models.EvaluationMetric{MetricType: "rmse", Direction: models.MetricDirectionMinimize}, 1, 0.5},
+		{"perfect error", models.EvaluationMetric{MetricType: "rmse", Direction: models.MetricDirectionMinimize}, 0, 1},
+		{"unbounded custom", models.EvaluationMetric{MetricType: "custom", Direction: models.MetricDirectionMaximize}, 3, 0.75},
+		{"negative custom", models.EvaluationMetric{MetricType: "custom", Direction: models.MetricDirectionMaximize}, -2, 0},
+	}
+	for _, tc := range cases {
+		if got := normalizeMetric(tc.metric, tc.value); math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
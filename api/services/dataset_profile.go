@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/jsonschema"
+	"github.com/google/uuid"
+)
+
+// DatasetProfileAcceptInput selects the profiled columns to turn into
+// variables. An empty Columns list accepts every column.
+type DatasetProfileAcceptInput struct {
+	Columns   []string                           `json:"columns,omitempty"`
+	Overrides map[string]DatasetVariableOverride `json:"overrides,omitempty"`
+}
+
+// DatasetVariableOverride replaces parts of a proposed variable; empty
+// fields keep the proposal.
+type DatasetVariableOverride struct {
+	Role        string `json:"role,omitempty"`
+	DataType    string `json:"data_type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+	Category    string `json:"category,omitempty"`
+}
+
+const datasetProfileColumns = `id, dataset_id, file_id, format, status, row_count, columns, error, attempts,
+		       COALESCE(requested_by, ''), started_at, completed_at, accepted_at, created_at, updated_at`
+
+// RequestProfile queues a profiling run for an uploaded CSV or Parquet file.
+// While a run for the file is pending or running, that run is returned
+// instead of queueing another.
+func (s *DatasetService) RequestProfile(ctx context.Context, hackathonID, fileID, actorID string) (*models.DatasetProfile, error) {
+	file, err := s.GetFile(ctx, hackathonID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("data file not found: %w", ErrNotFound)
+	}
+	if file.StorageKey == "" {
+		return nil, fmt.Errorf("only uploaded files can be profiled: %w", ErrInvalid)
+	}
+	format := datasetFileFormat(file)
+	if format == "" {
+		return nil, fmt.Errorf("only csv and parquet files can be profiled: %w", ErrInvalid)
+	}
+
+	now := time.Now().UTC()
+	profile := models.DatasetProfile{
+		ID:          uuid.NewString(),
+		DatasetID:   file.DatasetID,
+		FileID:      file.ID,
+		Format:      format,
+		Status:      models.DatasetProfileStatusPending,
+		Columns:     []models.DatasetColumnProfile{},
+		RequestedBy: actorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	res, err := s.DB.ExecContext(ctx, `
+		INSERT INTO dataset_profiles (id, dataset_id, file_id, format, status, requested_by, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,NULLIF($6, ''),$7,$8)
+		ON CONFLICT DO NOTHING`,
+		profile.ID, profile.DatasetID, profile.FileID, profile.Format, profile.Status, profile.RequestedBy, profile.CreatedAt, profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return loadLatestProfile(ctx, s.DB, file.ID, false)
+	}
+	return &profile, nil
+}
+
+// GetProfile returns the latest profile of a data file, or nil if it was
+// never profiled.
+func (s *DatasetService) GetProfile(ctx context.Context, hackathonID, fileID string) (*models.DatasetProfile, error) {
+	file, err := s.GetFile(ctx, hackathonID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("data file not found: %w", ErrNotFound)
+	}
+	return loadLatestProfile(ctx, s.DB, file.ID, false)
+}
+
+// ProcessNextProfile claims the oldest queued profile and runs it. Runs that
+// stayed in running since staleBefore (e.g. after a crash) are claimed again
+// until they reach maxAttempts, and then fail. It returns nil when the queue
+// is empty.
+func (s *DatasetService) ProcessNextProfile(ctx context.Context, staleBefore time.Time, maxAttempts int) (*models.DatasetProfile, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if _, err := s.DB.ExecContext(ctx, `
+		UPDATE dataset_profiles
+		SET status = $1, error = $2, completed_at = NOW(), updated_at = NOW()
+		WHERE status = $3 AND updated_at < $4 AND attempts >= $5`,
+		models.DatasetProfileStatusFailed, fmt.Sprintf("profiling did not finish after %d attempts", maxAttempts),
+		models.DatasetProfileStatusRunning, staleBefore, maxAttempts,
+	); err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	row := s.DB.QueryRowContext(ctx, `
+		UPDATE dataset_profiles
+		SET status = $1, attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM dataset_profiles
+			WHERE status = $2 OR (status = $1 AND updated_at < $3)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+datasetProfileColumns,
+		models.DatasetProfileStatusRunning, models.DatasetProfileStatusPending, staleBefore,
+	)
+	profile, err := scanDatasetProfile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	rowCount, columns, runErr := s.runProfile(ctx, profile)
+	if runErr != nil && ctx.Err() != nil {
+		// Shutting down: leave the run to be claimed again once stale.
+		return profile, runErr
+	}
+	now := time.Now().UTC()
+	profile.CompletedAt = &now
+	profile.UpdatedAt = now
+	if runErr != nil {
+		profile.Status = models.DatasetProfileStatusFailed
+		profile.Error = runErr.Error()
+	} else {
+		profile.Status = models.DatasetProfileStatusCompleted
+		profile.RowCount = rowCount
+		profile.Columns = columns
+	}
+	columnsRaw, err := json.Marshal(profile.Columns)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.DB.ExecContext(ctx, `
+		UPDATE dataset_profiles
+		SET status = $1, row_count = $2, columns = $3, error = $4, completed_at = $5, updated_at = $5
+		WHERE id = $6`,
+		profile.Status, profile.RowCount, columnsRaw, profile.Error, now, profile.ID,
+	); err != nil {
+		return nil, mapSQLError(err)
+	}
+	return profile, nil
+}
+
+// runProfile reads the profiled file and proposes a variable per column.
+func (s *DatasetService) runProfile(ctx context.Context, profile *models.DatasetProfile) (int64, []models.DatasetColumnProfile, error) {
+	if err := s.ensureStorage(); err != nil {
+		return 0, nil, err
+	}
+	var storageKey string
+	var targetsRaw []byte
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT COALESCE(f.storage_key, ''), d.target_columns
+		FROM dataset_files f
+		JOIN hackathon_datasets d ON d.id = f.dataset_id
+		WHERE f.id = $1`, profile.FileID).Scan(&storageKey, &targetsRaw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil, fmt.Errorf("data file not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return 0, nil, mapSQLError(err)
+	}
+
+	rowCount, stats, err := s.readProfile(ctx, profile.Format, storageKey)
+	if err != nil {
+		return 0, nil, err
+	}
+	columns := make([]models.DatasetColumnProfile, len(stats))
+	for i, st := range stats {
+		columns[i] = st.profile()
+	}
+	var targets []models.DatasetTargetColumn
+	if len(targetsRaw) > 0 {
+		if err := json.Unmarshal(targetsRaw, &targets); err != nil {
+			return 0, nil, mapSQLError(err)
+		}
+	}
+	proposeRoles(columns, targetCategories(targets))
+
+	existing, err := datasetVariableIDs(ctx, s.DB, profile.DatasetID)
+	if err != nil {
+		return 0, nil, err
+	}
+	for i := range columns {
+		columns[i].VariableID = existing[columns[i].Name]
+	}
+	return rowCount, columns, nil
+}
+
+func (s *DatasetService) readProfile(ctx context.Context, format, key string) (int64, []*columnStats, error) {
+	blob, err := s.Storage.Blobs.Get(ctx, key)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer blob.Close()
+	if format == models.DatasetFileFormatCSV {
+		return profileCSV(blob)
+	}
+
+	// Parquet needs random access to read the footer first.
+	tmp, err := os.CreateTemp("", "dataset-profile-*.parquet")
+	if err != nil {
+		return 0, nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	size, err := io.Copy(tmp, blob)
+	if err != nil {
+		return 0, nil, err
+	}
+	return profileParquet(tmp, size)
+}
+
+// AcceptProfile creates a variable for each selected column of the file's
+// latest completed profile, in one transaction. Columns that already have a
+// variable are skipped.
+func (s *DatasetService) AcceptProfile(ctx context.Context, hackathonID, fileID string, input DatasetProfileAcceptInput) (*models.DatasetProfileAcceptance, error) {
+	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	file, err := s.GetFile(ctx, hackathonID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("data file not found: %w", ErrNotFound)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	profile, err := loadLatestProfile(ctx, tx, file.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("profile not found: %w", ErrNotFound)
+	}
+	if profile.Status != models.DatasetProfileStatusCompleted {
+		return nil, fmt.Errorf("profile is %s: %w", profile.Status, ErrConflict)
+	}
+	variables, err := profileVariables(profile, input)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := datasetVariableIDs(ctx, tx, profile.DatasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := &models.DatasetProfileAcceptance{Created: []models.DatasetVariable{}, Skipped: []string{}}
+	for _, v := range variables {
+		if existing[v.Name] != "" {
+			result.Skipped = append(result.Skipped, v.Name)
+			continue
+		}
+		v.ID = uuid.NewString()
+		v.DatasetID = profile.DatasetID
+		v.CreatedAt, v.UpdatedAt = now, now
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO dataset_variables (id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+			v.ID, v.DatasetID, v.Name, v.Role, v.DataType, v.Description, v.Unit, v.Category, v.CreatedAt, v.UpdatedAt,
+		); err != nil {
+			return nil, mapSQLError(err)
+		}
+		if err := publishInTx(ctx, tx, s.Events, datasetVariableEvent("hackathon.data.variable.created", hackathonID, v.ID)); err != nil {
+			return nil, err
+		}
+		result.Created = append(result.Created, v)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE dataset_profiles SET accepted_at = $1, updated_at = $1 WHERE id = $2`, now, profile.ID); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	profile.AcceptedAt = &now
+	profile.UpdatedAt = now
+	result.Profile = profile
+	return result, nil
+}
+
+// profileVariables builds the variables to create from the selected columns
+// and their overrides.
+func profileVariables(profile *models.DatasetProfile, input DatasetProfileAcceptInput) ([]models.DatasetVariable, error) {
+	byName := make(map[string]models.DatasetColumnProfile, len(profile.Columns))
+	for _, col := range profile.Columns {
+		byName[col.Name] = col
+	}
+	var errs []jsonschema.Error
+	selected := input.Columns
+	if len(selected) == 0 {
+		for _, col := range profile.Columns {
+			selected = append(selected, col.Name)
+		}
+	}
+	for i, name := range selected {
+		if _, ok := byName[name]; !ok {
+			errs = append(errs, jsonschema.Error{Path: fmt.Sprintf("/columns/%d", i), Message: fmt.Sprintf("unknown column %q", name)})
+		}
+	}
+
+	overridden := make([]string, 0, len(input.Overrides))
+	for name := range input.Overrides {
+		overridden = append(overridden, name)
+	}
+	sort.Strings(overridden)
+	for _, name := range overridden {
+		base := "/overrides/" + escapeJSONPointer(name)
+		o := input.Overrides[name]
+		if _, ok := byName[name]; !ok {
+			errs = append(errs, jsonschema.Error{Path: base, Message: fmt.Sprintf("unknown column %q", name)})
+			continue
+		}
+		if o.Role != "" && !isAllowedRole(normalizeRole(o.Role)) {
+			errs = append(errs, jsonschema.Error{Path: base + "/role", Message: "unsupported role"})
+		}
+		if o.DataType != "" && !isAllowedDataType(normalizeDataType(o.DataType)) {
+			errs = append(errs, jsonschema.Error{Path: base + "/data_type", Message: "unsupported data_type"})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &FieldErrors{Message: "invalid profile acceptance", Errors: errs}
+	}
+
+	var variables []models.DatasetVariable
+	for _, name := range uniqueStrings(selected) {
+		col := byName[name]
+		v := models.DatasetVariable{Name: col.Name, Role: col.Role, DataType: col.DataType, Category: col.Category}
+		if o, ok := input.Overrides[name]; ok {
+			if o.Role != "" {
+				v.Role = normalizeRole(o.Role)
+			}
+			if o.DataType != "" {
+				v.DataType = normalizeDataType(o.DataType)
+			}
+			if o.Category != "" {
+				v.Category = o.Category
+			}
+			v.Description, v.Unit = o.Description, o.Unit
+		}
+		variables = append(variables, v)
+	}
+	return variables, nil
+}
+
+// datasetFileFormat picks the profiler for a file from its name, falling
+// back to its content type. It returns "" for other formats.
+func datasetFileFormat(file *models.DatasetFile) string {
+	switch strings.ToLower(path.Ext(file.Name)) {
+	case ".csv", ".tsv":
+		return models.DatasetFileFormatCSV
+	case ".parquet", ".pq":
+		return models.DatasetFileFormatParquet
+	}
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(file.ContentType, ";")[0]))
+	switch contentType {
+	case "text/csv", "application/csv", "text/tab-separated-values":
+		return models.DatasetFileFormatCSV
+	case "application/vnd.apache.parquet", "application/x-parquet", "application/parquet":
+		return models.DatasetFileFormatParquet
+	}
+	return ""
+}
+
+func datasetVariableIDs(ctx context.Context, q rowsQuerier, datasetID string) (map[string]string, error) {
+	rows, err := q.QueryContext(ctx, `SELECT id, name FROM dataset_variables WHERE dataset_id = $1`, datasetID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+	ids := map[string]string{}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, mapSQLError(err)
+		}
+		ids[name] = id
+	}
+	return ids, rows.Err()
+}
+
+func loadLatestProfile(ctx context.Context, q rowQuerier, fileID string, lock bool) (*models.DatasetProfile, error) {
+	query := `
+		SELECT ` + datasetProfileColumns + `
+		FROM dataset_profiles
+		WHERE file_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+	if lock {
+		query += " FOR UPDATE"
+	}
+	profile, err := scanDatasetProfile(q.QueryRowContext(ctx, query, fileID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return profile, nil
+}
+
+func scanDatasetProfile(row rowScanner) (*models.DatasetProfile, error) {
+	var p models.DatasetProfile
+	var columns []byte
+	if err := row.Scan(&p.ID, &p.DatasetID, &p.FileID, &p.Format, &p.Status, &p.RowCount, &columns, &p.Error, &p.Attempts,
+		&p.RequestedBy, &p.StartedAt, &p.CompletedAt, &p.AcceptedAt, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(columns) > 0 {
+		if err := json.Unmarshal(columns, &p.Columns); err != nil {
+			return nil, err
+		}
+	}
+	if p.Columns == nil {
+		p.Columns = []models.DatasetColumnProfile{}
+	}
+	return &p, nil
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash/maphash"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/parquet-go/parquet-go"
+)
+
+func profileColumnsByName(t *testing.T, stats []*columnStats) map[string]models.DatasetColumnProfile {
+	t.Helper()
+	out := map[string]models.DatasetColumnProfile{}
+	for _, st := range stats {
+		out[st.name] = st.profile()
+	}
+	return out
+}
+
+func TestProfileCSVInfersTypesAndStats(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("\ufeffid;label;score;seen;flag;notes;empty\n")
+	labels := []string{"cat", "dog"}
+	for i := 0; i < 40; i++ {
+		score := "0.5"
+		if i == 3 {
+			score = "NA"
+		}
+		if i == 7 {
+			score = "-2"
+		}
+		seen := "2024-01-02"
+		if i == 9 {
+			seen = "2023-12-31T08:00:00Z"
+		}
+		flag := "yes"
+		if i%2 == 0 {
+			flag = "No"
+		}
+		notes := strings.Repeat("free text ", 7) + string(rune('a'+i%26))
+		b.WriteString(strings.Join([]string{strconv.Itoa(i + 1), labels[i%2], score, seen, flag, notes, ""}, ";") + "\n")
+	}
+
+	rows, stats, err := profileCSV(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("profileCSV: %v", err)
+	}
+	if rows != 40 || len(stats) != 7 {
+		t.Fatalf("rows=%d columns=%d", rows, len(stats))
+	}
+	cols := profileColumnsByName(t, stats)
+
+	id := cols["id"]
+	if id.DataType != models.DatasetDataTypeInteger || id.Cardinality != 40 || id.Min != int64(1) || id.Max != int64(40) {
+		t.Fatalf("unexpected id profile: %+v", id)
+	}
+	if got := cols["label"]; got.DataType != models.DatasetDataTypeCategorical || got.Cardinality != 2 {
+		t.Fatalf("unexpected label profile: %+v", got)
+	}
+	score := cols["score"]
+	if score.DataType != models.DatasetDataTypeFloat || score.Nulls != 1 || score.NullRatio != 1.0/40 || score.Min != -2.0 || score.Max != 0.5 {
+		t.Fatalf("unexpected score profile: %+v", score)
+	}
+	seen := cols["seen"]
+	if seen.DataType != models.DatasetDataTypeDatetime || seen.Min != "2023-12-31T08:00:00Z" || seen.Max != "2024-01-02T00:00:00Z" {
+		t.Fatalf("unexpected seen profile: %+v", seen)
+	}
+	if got := cols["flag"]; got.DataType != models.DatasetDataTypeBoolean || got.Min != nil {
+		t.Fatalf("unexpected flag profile: %+v", got)
+	}
+	if got := cols["notes"]; got.DataType != models.DatasetDataTypeText {
+		t.Fatalf("unexpected notes profile: %+v", got)
+	}
+	if got := cols["empty"]; got.DataType != models.DatasetDataTypeString || got.NullRatio != 1 || got.Cardinality != 0 {
+		t.Fatalf("unexpected empty profile: %+v", got)
+	}
+}
+
+func TestProfileCSVRejectsMalformedFiles(t *testing.T) {
+	if _, _, err := profileCSV(strings.NewReader("")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for an empty file, got %v", err)
+	}
+	if _, _, err := profileCSV(strings.NewReader("a,b\n1,2,3\n")); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for a ragged row, got %v", err)
+	}
+	if _, _, err := profileParquet(strings.NewReader("a,b\n1,2\n"), 8); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for a non-parquet file, got %v", err)
+	}
+}
+
+func TestProfileParquetReadsTypedColumns(t *testing.T) {
+	type row struct {
+		ID    int64     `parquet:"id"`
+		Label string    `parquet:"label"`
+		Score *float64  `parquet:"score,optional"`
+		Seen  time.Time `parquet:"seen,timestamp(millisecond)"`
+		Flag  bool      `parquet:"flag"`
+	}
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]row, 40)
+	for i := range rows {
+		rows[i] = row{ID: int64(i), Label: []string{"cat", "dog"}[i%2], Seen: base.Add(time.Duration(i) * time.Hour), Flag: i%3 == 0}
+		if i%4 != 0 {
+			score := float64(i) / 2
+			rows[i].Score = &score
+		}
+	}
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, rows, parquet.Compression(&parquet.Snappy)); err != nil {
+		t.Fatalf("write parquet: %v", err)
+	}
+
+	count, stats, err := profileParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("profileParquet: %v", err)
+	}
+	if count != 40 {
+		t.Fatalf("expected 40 rows, got %d", count)
+	}
+	cols := profileColumnsByName(t, stats)
+	if got := cols["id"]; got.DataType != models.DatasetDataTypeInteger || got.Max != int64(39) || got.Cardinality != 40 {
+		t.Fatalf("unexpected id profile: %+v", got)
+	}
+	if got := cols["label"]; got.DataType != models.DatasetDataTypeCategorical || got.Cardinality != 2 {
+		t.Fatalf("unexpected label profile: %+v", got)
+	}
+	if got := cols["score"]; got.DataType != models.DatasetDataTypeFloat || got.Nulls != 10 || got.Max != 19.5 {
+		t.Fatalf("unexpected score profile: %+v", got)
+	}
+	if got := cols["seen"]; got.DataType != models.DatasetDataTypeDatetime || got.Min != "2024-03-01T00:00:00Z" {
+		t.Fatalf("unexpected seen profile: %+v", got)
+	}
+	if got := cols["flag"]; got.DataType != models.DatasetDataTypeBoolean || got.Cardinality != 2 {
+		t.Fatalf("unexpected flag profile: %+v", got)
+	}
+}
+
+func TestProfileParquetRejectsNestedColumns(t *testing.T) {
+	type row struct {
+		ID   int64    `parquet:"id"`
+		Tags []string `parquet:"tags,list"`
+	}
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, []row{{ID: 1, Tags: []string{"a"}}}); err != nil {
+		t.Fatalf("write parquet: %v", err)
+	}
+	if _, _, err := profileParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len())); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for a nested column, got %v", err)
+	}
+}
+
+func TestColumnStatsTypedValues(t *testing.T) {
+	seed := maphash.MakeSeed()
+	ts := newColumnStats("ts", seed)
+	early := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []any{early.Add(time.Hour), nil, early} {
+		ts.addValue(v)
+	}
+	if p := ts.profile(); p.DataType != models.DatasetDataTypeDatetime || p.Min != "2020-01-01T00:00:00Z" || p.Nulls != 1 {
+		t.Fatalf("unexpected timestamp profile: %+v", p)
+	}
+
+	// Parquet strings still go through inference.
+	codes := newColumnStats("code", seed)
+	for _, v := range []any{"1", "2", []byte("3")} {
+		codes.addValue(v)
+	}
+	if p := codes.profile(); p.DataType != models.DatasetDataTypeInteger || p.Max != int64(3) {
+		t.Fatalf("unexpected code profile: %+v", p)
+	}
+
+	// NaN is a missing float.
+	ratio := newColumnStats("ratio", seed)
+	for _, v := range []any{0.25, math.NaN(), 1.5} {
+		ratio.addValue(v)
+	}
+	if p := ratio.profile(); p.DataType != models.DatasetDataTypeFloat || p.Nulls != 1 || p.Max != 1.5 {
+		t.Fatalf("unexpected ratio profile: %+v", p)
+	}
+}
+
+func TestProposeRoles(t *testing.T) {
+	targets := targetCategories([]models.DatasetTargetColumn{{Name: "Count", Category: "Grain Count"}, {Name: "label"}})
+	columns := []models.DatasetColumnProfile{
+		{Name: "ID"}, {Name: "image_id"}, {Name: "ImageId"}, {Name: "count"}, {Name: "Label"}, {Name: "width"}, {Name: "paid"},
+	}
+	proposeRoles(columns, targets)
+
+	want := map[string]string{
+		"ID":       models.DatasetVariableRoleIdentifier,
+		"image_id": models.DatasetVariableRoleIdentifier,
+		"ImageId":  models.DatasetVariableRoleIdentifier,
+		"count":    models.DatasetVariableRoleTarget,
+		"Label":    models.DatasetVariableRoleTarget,
+		"width":    models.DatasetVariableRoleFeature,
+		"paid":     models.DatasetVariableRoleFeature,
+	}
+	for _, col := range columns {
+		if col.Role != want[col.Name] {
+			t.Fatalf("column %s: role %q, want %q", col.Name, col.Role, want[col.Name])
+		}
+	}
+	if columns[3].Category != "Grain Count" {
+		t.Fatalf("expected target category, got %q", columns[3].Category)
+	}
+	if got := targetCategories(nil); len(got) != 0 {
+		t.Fatalf("expected no targets, got %v", got)
+	}
+}
+
+func TestNormalizeTargetColumns(t *testing.T) {
+	got, err := normalizeTargetColumns([]models.DatasetTargetColumn{{Name: " Count ", Category: " Grain Count "}, {Name: "label"}})
+	if err != nil || len(got) != 2 || got[0].Name != "Count" || got[0].Category != "Grain Count" {
+		t.Fatalf("unexpected targets=%+v err=%v", got, err)
+	}
+	if got, err := normalizeTargetColumns(nil); err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty list, got=%v err=%v", got, err)
+	}
+	for _, targets := range [][]models.DatasetTargetColumn{
+		{{Name: " "}},
+		{{Name: "label"}, {Name: "Label"}},
+	} {
+		if _, err := normalizeTargetColumns(targets); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%+v: expected ErrInvalid, got=%v", targets, err)
+		}
+	}
+}
+
+func TestProfileColumnNames(t *testing.T) {
+	got := profileColumnNames([]string{"a", " ", "a", "a_2", "a"})
+	want := []string{"a", "column_2", "a_2", "a_2_2", "a_3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("names = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestProfileVariablesAppliesOverrides(t *testing.T) {
+	profile := &models.DatasetProfile{Columns: []models.DatasetColumnProfile{
+		{Name: "id", Role: models.DatasetVariableRoleIdentifier, DataType: models.DatasetDataTypeInteger},
+		{Name: "x/y", Role: models.DatasetVariableRoleFeature, DataType: models.DatasetDataTypeFloat},
+		{Name: "y", Role: models.DatasetVariableRoleTarget, DataType: models.DatasetDataTypeInteger, Category: "Count"},
+	}}
+
+	vars, err := profileVariables(profile, DatasetProfileAcceptInput{
+		Columns:   []string{"x/y", "y"},
+		Overrides: map[string]DatasetVariableOverride{"y": {DataType: " Categorical ", Unit: "grains"}},
+	})
+	if err != nil {
+		t.Fatalf("profileVariables: %v", err)
+	}
+	if len(vars) != 2 || vars[0].Name != "x/y" || vars[1].DataType != models.DatasetDataTypeCategorical || vars[1].Category != "Count" || vars[1].Unit != "grains" {
+		t.Fatalf("unexpected variables: %+v", vars)
+	}
+
+	all, err := profileVariables(profile, DatasetProfileAcceptInput{})
+	if err != nil || len(all) != 3 {
+		t.Fatalf("expected every column, got %d (%v)", len(all), err)
+	}
+
+	_, err = profileVariables(profile, DatasetProfileAcceptInput{
+		Columns:   []string{"missing"},
+		Overrides: map[string]DatasetVariableOverride{"x/y": {Role: "label"}, "nope": {}},
+	})
+	var fieldErrs *FieldErrors
+	if !errors.As(err, &fieldErrs) || !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected field errors, got %v", err)
+	}
+	paths := map[string]bool{}
+	for _, e := range fieldErrs.Errors {
+		paths[e.Path] = true
+	}
+	for _, p := range []string{"/columns/0", "/overrides/x~1y/role", "/overrides/nope"} {
+		if !paths[p] {
+			t.Fatalf("missing error at %s: %+v", p, fieldErrs.Errors)
+		}
+	}
+}
+
+func TestDatasetFileFormat(t *testing.T) {
+	cases := map[string]models.DatasetFile{
+		models.DatasetFileFormatCSV:     {Name: "train.CSV"},
+		models.DatasetFileFormatParquet: {Name: "train.parquet"},
+		"":                              {Name: "images.zip", ContentType: "application/zip"},
+	}
+	for want, file := range cases {
+		if got := datasetFileFormat(&file); got != want {
+			t.Fatalf("%s: got %q, want %q", file.Name, got, want)
+		}
+	}
+	if got := datasetFileFormat(&models.DatasetFile{Name: "data", ContentType: "text/csv; charset=utf-8"}); got != models.DatasetFileFormatCSV {
+		t.Fatalf("expected csv from content type, got %q", got)
+	}
+}
+
+func TestReadProfileFromBlobStore(t *testing.T) {
+	ctx := context.Background()
+	svc := &DatasetService{Storage: testDatasetStorage(t)}
+	content := "id,label\n1,a\n2,b\n"
+	if err := svc.Storage.Blobs.Put(ctx, "datasets/d/f/train.csv", strings.NewReader(content), int64(len(content)), "text/csv"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rows, stats, err := svc.readProfile(ctx, models.DatasetFileFormatCSV, "datasets/d/f/train.csv")
+	if err != nil || rows != 2 || len(stats) != 2 {
+		t.Fatalf("rows=%d columns=%d err=%v", rows, len(stats), err)
+	}
+	if err := svc.Storage.Blobs.Put(ctx, "datasets/d/f/train.parquet", strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, _, err := svc.readProfile(ctx, models.DatasetFileFormatParquet, "datasets/d/f/train.parquet"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for a non-parquet blob, got %v", err)
+	}
+}
@@ -0,0 +1,531 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+const (
+	// profileCardinalityCap bounds the distinct values tracked per column.
+	profileCardinalityCap = 50_000
+	// String columns with at most this many distinct values, each repeated
+	// at least profileCategoricalRepeat times on average, are categorical.
+	profileCategoricalMaxDistinct = 50
+	profileCategoricalRepeat      = 20
+	// String columns whose values average this many bytes are text.
+	profileTextMinLength = 64
+)
+
+var profileNullTokens = map[string]bool{"": true, "na": true, "n/a": true, "nan": true, "null": true, "none": true}
+
+var profileTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// columnStats accumulates the statistics of one column. CSV cells (and
+// Parquet strings) go through addString, which narrows the candidate types
+// as values arrive; typed Parquet values go through addValue and fix the
+// type directly.
+type columnStats struct {
+	name      string
+	fixedType string
+
+	count     int64
+	nulls     int64
+	lengthSum int64
+	seed      maphash.Seed
+	distinct  map[uint64]struct{}
+	capped    bool
+
+	isBool, isInt, isFloat, isTime bool
+
+	minInt, maxInt   int64
+	minNum, maxNum   float64
+	minTime, maxTime time.Time
+	hasInt, hasNum   bool
+	hasTime          bool
+}
+
+func newColumnStats(name string, seed maphash.Seed) *columnStats {
+	return &columnStats{
+		name:     name,
+		seed:     seed,
+		distinct: map[uint64]struct{}{},
+		isBool:   true,
+		isInt:    true,
+		isFloat:  true,
+		isTime:   true,
+	}
+}
+
+func (c *columnStats) addNull() {
+	c.nulls++
+}
+
+func (c *columnStats) addString(raw string) {
+	v := strings.TrimSpace(raw)
+	if profileNullTokens[strings.ToLower(v)] {
+		c.addNull()
+		return
+	}
+	c.count++
+	c.lengthSum += int64(len(v))
+	c.see(v)
+	if c.isBool {
+		_, c.isBool = parseProfileBool(v)
+	}
+	if c.isInt {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			c.observeInt(n)
+		} else {
+			c.isInt = false
+		}
+	}
+	if c.isFloat {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) {
+			c.observeFloat(f)
+		} else {
+			c.isFloat = false
+		}
+	}
+	if c.isTime {
+		if t, ok := parseProfileTime(v); ok {
+			c.observeTime(t)
+		} else {
+			c.isTime = false
+		}
+	}
+}
+
+func (c *columnStats) addValue(v any) {
+	switch x := v.(type) {
+	case nil:
+		c.addNull()
+	case string:
+		c.addString(x)
+	case []byte:
+		c.addString(string(x))
+	case bool:
+		c.fixedType = models.DatasetDataTypeBoolean
+		c.count++
+		c.see(strconv.FormatBool(x))
+	case int64:
+		c.fixedType = models.DatasetDataTypeInteger
+		c.count++
+		c.see(strconv.FormatInt(x, 10))
+		c.observeInt(x)
+	case float64:
+		if math.IsNaN(x) {
+			c.addNull()
+			return
+		}
+		c.fixedType = models.DatasetDataTypeFloat
+		c.count++
+		c.see(strconv.FormatFloat(x, 'g', -1, 64))
+		c.observeFloat(x)
+	case time.Time:
+		c.fixedType = models.DatasetDataTypeDatetime
+		c.count++
+		c.see(x.Format(time.RFC3339Nano))
+		c.observeTime(x)
+	default:
+		c.addString(fmt.Sprint(x))
+	}
+}
+
+func (c *columnStats) see(v string) {
+	if c.capped {
+		return
+	}
+	c.distinct[maphash.String(c.seed, v)] = struct{}{}
+	if len(c.distinct) > profileCardinalityCap {
+		c.capped = true
+	}
+}
+
+func (c *columnStats) observeInt(n int64) {
+	if !c.hasInt || n < c.minInt {
+		c.minInt = n
+	}
+	if !c.hasInt || n > c.maxInt {
+		c.maxInt = n
+	}
+	c.hasInt = true
+}
+
+func (c *columnStats) observeFloat(f float64) {
+	if !c.hasNum || f < c.minNum {
+		c.minNum = f
+	}
+	if !c.hasNum || f > c.maxNum {
+		c.maxNum = f
+	}
+	c.hasNum = true
+}
+
+func (c *columnStats) observeTime(t time.Time) {
+	if !c.hasTime || t.Before(c.minTime) {
+		c.minTime = t
+	}
+	if !c.hasTime || t.After(c.maxTime) {
+		c.maxTime = t
+	}
+	c.hasTime = true
+}
+
+func (c *columnStats) dataType() string {
+	if c.count == 0 {
+		return models.DatasetDataTypeString
+	}
+	if c.fixedType != "" {
+		return c.fixedType
+	}
+	switch {
+	case c.isBool:
+		return models.DatasetDataTypeBoolean
+	case c.isInt:
+		return models.DatasetDataTypeInteger
+	case c.isFloat:
+		return models.DatasetDataTypeFloat
+	case c.isTime:
+		return models.DatasetDataTypeDatetime
+	}
+	distinct := int64(len(c.distinct))
+	if !c.capped && distinct <= profileCategoricalMaxDistinct && distinct*profileCategoricalRepeat <= c.count {
+		return models.DatasetDataTypeCategorical
+	}
+	if c.lengthSum/c.count >= profileTextMinLength {
+		return models.DatasetDataTypeText
+	}
+	return models.DatasetDataTypeString
+}
+
+func (c *columnStats) profile() models.DatasetColumnProfile {
+	p := models.DatasetColumnProfile{
+		Name:              c.name,
+		DataType:          c.dataType(),
+		Count:             c.count,
+		Nulls:             c.nulls,
+		Cardinality:       int64(len(c.distinct)),
+		CardinalityCapped: c.capped,
+	}
+	if total := c.count + c.nulls; total > 0 {
+		p.NullRatio = float64(c.nulls) / float64(total)
+	}
+	switch p.DataType {
+	case models.DatasetDataTypeInteger:
+		if c.hasInt {
+			p.Min, p.Max = c.minInt, c.maxInt
+		}
+	case models.DatasetDataTypeFloat:
+		if c.hasNum {
+			p.Min, p.Max = c.minNum, c.maxNum
+		}
+	case models.DatasetDataTypeDatetime:
+		if c.hasTime {
+			p.Min, p.Max = c.minTime.UTC().Format(time.RFC3339Nano), c.maxTime.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return p
+}
+
+func parseProfileBool(v string) (bool, bool) {
+	switch strings.ToLower(v) {
+	case "true", "t", "yes", "y":
+		return true, true
+	case "false", "f", "no", "n":
+		return false, true
+	}
+	return false, false
+}
+
+func parseProfileTime(v string) (time.Time, bool) {
+	// Every supported layout starts with a four digit year.
+	if len(v) < 10 || v[4] != '-' {
+		return time.Time{}, false
+	}
+	for _, layout := range profileTimeLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// profileCSV profiles a delimited text file with a header row. The delimiter
+// is guessed from the header line.
+func profileCSV(r io.Reader) (int64, []*columnStats, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\ufeff")) {
+		_, _ = br.Discard(3)
+	}
+	head, _ := br.Peek(br.Size())
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = sniffDelimiter(head)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return 0, nil, fmt.Errorf("file is empty: %w", ErrInvalid)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid csv header: %v: %w", err, ErrInvalid)
+	}
+	seed := maphash.MakeSeed()
+	names := profileColumnNames(header)
+	stats := make([]*columnStats, len(names))
+	for i, name := range names {
+		stats[i] = newColumnStats(name, seed)
+	}
+
+	var rows int64
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid csv: %v: %w", err, ErrInvalid)
+		}
+		rows++
+		for i, cell := range record {
+			stats[i].addString(cell)
+		}
+	}
+	return rows, stats, nil
+}
+
+func sniffDelimiter(line []byte) rune {
+	best, bestCount := ',', 0
+	for _, d := range []rune{',', ';', '\t', '|'} {
+		if n := bytes.Count(line, []byte(string(d))); n > bestCount {
+			best, bestCount = d, n
+		}
+	}
+	return best
+}
+
+// profileColumnNames names blank headers after their position and suffixes
+// repeated names, since variable names are unique per dataset.
+func profileColumnNames(header []string) []string {
+	names := make([]string, len(header))
+	used := map[string]bool{}
+	for i, h := range header {
+		base := strings.TrimSpace(h)
+		if base == "" {
+			base = fmt.Sprintf("column_%d", i+1)
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// profileParquet profiles a Parquet file one column at a time. Only flat
+// schemas are supported.
+func profileParquet(r io.ReaderAt, size int64) (int64, []*columnStats, error) {
+	f, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid parquet file: %v: %w", err, ErrInvalid)
+	}
+	columns := f.Root().Columns()
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		if !col.Leaf() || col.Repeated() {
+			return 0, nil, fmt.Errorf("nested or repeated parquet column %q is not supported: %w", col.Name(), ErrInvalid)
+		}
+		header[i] = col.Name()
+	}
+	seed := maphash.MakeSeed()
+	names := profileColumnNames(header)
+	stats := make([]*columnStats, len(columns))
+	for i, col := range columns {
+		st := newColumnStats(names[i], seed)
+		if err := scanParquetColumn(col, st.addValue); err != nil {
+			return 0, nil, fmt.Errorf("invalid parquet file: column %q: %v: %w", col.Name(), err, ErrInvalid)
+		}
+		stats[i] = st
+	}
+	return f.NumRows(), stats, nil
+}
+
+// scanParquetColumn calls fn with every value of a leaf column in row order,
+// converted by parquetValue.
+func scanParquetColumn(col *parquet.Column, fn func(v any)) error {
+	pages := col.Pages()
+	defer pages.Close()
+	logical := col.Type().LogicalType()
+	buf := make([]parquet.Value, 1024)
+	for {
+		page, err := pages.ReadPage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values := page.Values()
+		for {
+			n, err := values.ReadValues(buf)
+			for _, v := range buf[:n] {
+				fn(parquetValue(v, logical))
+			}
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				parquet.Release(page)
+				return err
+			}
+		}
+		parquet.Release(page)
+	}
+}
+
+// julianUnixEpoch is the Julian day number of 1970-01-01, used by INT96
+// timestamps.
+const julianUnixEpoch = 2440588
+
+// parquetValue maps a Parquet value to the Go value addValue expects: nil,
+// bool, int64, float64, string, []byte or time.Time.
+func parquetValue(v parquet.Value, logical *format.LogicalType) any {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Kind() {
+	case parquet.Boolean:
+		return v.Boolean()
+	case parquet.Int32, parquet.Int64:
+		n := v.Int64()
+		if v.Kind() == parquet.Int32 {
+			n = int64(v.Int32())
+		}
+		switch {
+		case logical == nil:
+		case logical.Date != nil:
+			return time.Unix(n*86400, 0).UTC()
+		case logical.Timestamp != nil:
+			return fromTimeUnit(n, logical.Timestamp.Unit)
+		case logical.Time != nil:
+			return fromTimeUnit(n, logical.Time.Unit).Format("15:04:05.999999999")
+		case logical.Decimal != nil:
+			return float64(n) / math.Pow10(int(logical.Decimal.Scale))
+		case logical.Integer != nil && !logical.Integer.IsSigned && v.Kind() == parquet.Int32:
+			return int64(uint32(n))
+		}
+		return n
+	case parquet.Int96:
+		i := v.Int96()
+		nanos := int64(i[0]) | int64(i[1])<<32
+		return time.Unix((int64(i[2])-julianUnixEpoch)*86400, nanos).UTC()
+	case parquet.Float:
+		return float64(v.Float())
+	case parquet.Double:
+		return v.Double()
+	}
+	b := v.ByteArray()
+	switch {
+	case logical == nil:
+	case logical.UTF8 != nil, logical.Enum != nil, logical.Json != nil:
+		return string(b)
+	case logical.Decimal != nil:
+		return decimalFromBytes(b, int(logical.Decimal.Scale))
+	case logical.UUID != nil && len(b) == 16:
+		return uuid.UUID(b).String()
+	}
+	return b
+}
+
+func fromTimeUnit(v int64, unit format.TimeUnit) time.Time {
+	switch {
+	case unit.Nanos != nil:
+		return time.Unix(0, v).UTC()
+	case unit.Micros != nil:
+		return time.UnixMicro(v).UTC()
+	default:
+		return time.UnixMilli(v).UTC()
+	}
+}
+
+// decimalFromBytes decodes a big-endian two's complement unscaled value.
+func decimalFromBytes(b []byte, scale int) float64 {
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return f / math.Pow10(scale)
+}
+
+// proposeRoles marks response-schema targets as targets and id-like columns
+// as identifiers; every other column is proposed as a feature. targets maps
+// lower-cased target names to their category.
+func proposeRoles(columns []models.DatasetColumnProfile, targets map[string]string) {
+	for i := range columns {
+		col := &columns[i]
+		if category, ok := targets[strings.ToLower(col.Name)]; ok {
+			col.Role = models.DatasetVariableRoleTarget
+			col.Category = category
+			continue
+		}
+		if isIDLikeColumn(col.Name) {
+			col.Role = models.DatasetVariableRoleIdentifier
+			continue
+		}
+		col.Role = models.DatasetVariableRoleFeature
+	}
+}
+
+// isIDLikeColumn matches names such as id, ID, row_id, image-id, ImageId,
+// customerID and uuid, and the index column pandas writes to Parquet.
+func isIDLikeColumn(name string) bool {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	switch lower {
+	case "id", "uuid", "guid", "key":
+		return true
+	}
+	if strings.HasPrefix(lower, "__index_level_") {
+		return true
+	}
+	for _, sep := range []string{"_", "-", " ", "."} {
+		if strings.HasSuffix(lower, sep+"id") || strings.HasSuffix(lower, sep+"uuid") {
+			return true
+		}
+	}
+	// camelCase: a lower-case letter followed by "Id" or "ID".
+	if n := len(name); n > 2 && (strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID")) {
+		return unicode.IsLower(rune(name[n-3]))
+	}
+	return false
+}
+
+// targetCategories maps lower-cased target column names to their category.
+func targetCategories(targets []models.DatasetTargetColumn) map[string]string {
+	categories := make(map[string]string, len(targets))
+	for _, target := range targets {
+		categories[strings.ToLower(target.Name)] = target.Category
+	}
+	return categories
+}
@@ -10,15 +10,18 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type DatasetService struct {
-	DB *sql.DB
+	DB      *sql.DB
+	Storage DatasetStorage
+	Events  events.Publisher
 }
 
-func NewDatasetService(db *sql.DB) *DatasetService {
-	return &DatasetService{DB: db}
+func NewDatasetService(db *sql.DB, storage DatasetStorage, publisher events.Publisher) *DatasetService {
+	return &DatasetService{DB: db, Storage: storage, Events: publisher}
 }
 
 func (s *DatasetService) Create(ctx context.Context, hackathonID string, input models.Dataset) (*models.Dataset, error) {
@@ -32,13 +35,21 @@ func (s *DatasetService) Create(ctx context.Context, hackathonID string, input m
 	if err != nil {
 		return nil, err
 	}
-	if err := ensureValidJSON(input.ResponseSchema, "response_schema"); err != nil {
+	if _, err := compileResponseSchema(input.ResponseSchema); err != nil {
+		return nil, err
+	}
+	targets, err := normalizeTargetColumns(input.TargetColumns)
+	if err != nil {
 		return nil, err
 	}
 	sourceRaw, err := json.Marshal(sourceURLs)
 	if err != nil {
 		return nil, fmt.Errorf("invalid source_urls: %w", ErrInvalid)
 	}
+	targetsRaw, err := json.Marshal(targets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_columns: %w", ErrInvalid)
+	}
 
 	now := time.Now().UTC()
 	ds := models.Dataset{
@@ -48,31 +59,36 @@ func (s *DatasetService) Create(ctx context.Context, hackathonID string, input m
 		Description:    input.Description,
 		SourceURLs:     sourceURLs,
 		ResponseSchema: normalizeMetadata(input.ResponseSchema),
+		TargetColumns:  targets,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		INSERT INTO hackathon_datasets (id, hackathon_id, title, description, source_urls, response_schema, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
-		ds.ID, ds.HackathonID, ds.Title, ds.Description, sourceRaw, ds.ResponseSchema, ds.CreatedAt, ds.UpdatedAt,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO hackathon_datasets (id, hackathon_id, title, description, source_urls, response_schema, target_columns, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+			ds.ID, ds.HackathonID, ds.Title, ds.Description, sourceRaw, ds.ResponseSchema, targetsRaw, ds.CreatedAt, ds.UpdatedAt,
+		)
+		return mapSQLError(err)
+	}, datasetEvent("hackathon.data.created", hackathonID, ds.ID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return &ds, nil
 }
 
 func (s *DatasetService) GetByHackathon(ctx context.Context, hackathonID string) (*models.Dataset, error) {
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, hackathon_id, title, description, source_urls, response_schema, created_at, updated_at
+		SELECT id, hackathon_id, title, description, source_urls, response_schema, target_columns, created_at, updated_at
 		FROM hackathon_datasets
 		WHERE hackathon_id = $1`, hackathonID)
 
 	var ds models.Dataset
 	var sourceRaw []byte
 	var schema []byte
-	if err := row.Scan(&ds.ID, &ds.HackathonID, &ds.Title, &ds.Description, &sourceRaw, &schema, &ds.CreatedAt, &ds.UpdatedAt); err != nil {
+	var targetsRaw []byte
+	if err := row.Scan(&ds.ID, &ds.HackathonID, &ds.Title, &ds.Description, &sourceRaw, &schema, &targetsRaw, &ds.CreatedAt, &ds.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -83,15 +99,21 @@ func (s *DatasetService) GetByHackathon(ctx context.Context, hackathonID string)
 			return nil, mapSQLError(err)
 		}
 	}
+	if len(targetsRaw) > 0 {
+		if err := json.Unmarshal(targetsRaw, &ds.TargetColumns); err != nil {
+			return nil, mapSQLError(err)
+		}
+	}
 	ds.ResponseSchema = schema
 	return &ds, nil
 }
 
 type DatasetUpdateInput struct {
-	Title          *string          `json:"title,omitempty"`
-	Description    *string          `json:"description,omitempty"`
-	SourceURLs     *[]string        `json:"source_urls,omitempty"`
-	ResponseSchema *json.RawMessage `json:"response_schema,omitempty"`
+	Title          *string                       `json:"title,omitempty"`
+	Description    *string                       `json:"description,omitempty"`
+	SourceURLs     *[]string                     `json:"source_urls,omitempty"`
+	ResponseSchema *json.RawMessage              `json:"response_schema,omitempty"`
+	TargetColumns  *[]models.DatasetTargetColumn `json:"target_columns,omitempty"`
 }
 
 func (s *DatasetService) Update(ctx context.Context, hackathonID string, input DatasetUpdateInput) (*models.Dataset, error) {
@@ -130,24 +152,37 @@ func (s *DatasetService) Update(ctx context.Context, hackathonID string, input D
 	}
 	schema := existing.ResponseSchema
 	if input.ResponseSchema != nil {
-		if err := ensureValidJSON(*input.ResponseSchema, "response_schema"); err != nil {
+		if _, err := compileResponseSchema(*input.ResponseSchema); err != nil {
 			return nil, err
 		}
 		schema = normalizeMetadata(*input.ResponseSchema)
 	}
+	targets := existing.TargetColumns
+	if input.TargetColumns != nil {
+		if targets, err = normalizeTargetColumns(*input.TargetColumns); err != nil {
+			return nil, err
+		}
+	}
 	sourceRaw, err := json.Marshal(sourceURLs)
 	if err != nil {
 		return nil, fmt.Errorf("invalid source_urls: %w", ErrInvalid)
 	}
+	targetsRaw, err := json.Marshal(targets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target_columns: %w", ErrInvalid)
+	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE hackathon_datasets
-		SET title = $1, description = $2, source_urls = $3, response_schema = $4, updated_at = NOW()
-		WHERE hackathon_id = $5`,
-		title, description, sourceRaw, schema, hackathonID,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE hackathon_datasets
+			SET title = $1, description = $2, source_urls = $3, response_schema = $4, target_columns = $5, updated_at = NOW()
+			WHERE hackathon_id = $6`,
+			title, description, sourceRaw, schema, targetsRaw, hackathonID,
+		)
+		return mapSQLError(err)
+	}, datasetEvent("hackathon.data.updated", hackathonID, existing.ID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return s.GetByHackathon(ctx, hackathonID)
 }
@@ -156,18 +191,20 @@ func (s *DatasetService) Delete(ctx context.Context, hackathonID string) error {
 	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
 		return err
 	}
-	res, err := s.DB.ExecContext(ctx, `DELETE FROM hackathon_datasets WHERE hackathon_id = $1`, hackathonID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return fmt.Errorf("dataset not found: %w", ErrNotFound)
-	}
-	return nil
+	return execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM hackathon_datasets WHERE hackathon_id = $1`, hackathonID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("dataset not found: %w", ErrNotFound)
+		}
+		return nil
+	}, domainEvent{"hackathon.data.deleted", map[string]any{"hackathon_id": hackathonID}})
 }
 
 func (s *DatasetService) getDatasetID(ctx context.Context, hackathonID string) (string, error) {
@@ -196,6 +233,29 @@ func normalizeURLList(values []string) ([]string, error) {
 	return normalized, nil
 }
 
+// normalizeTargetColumns trims target names and categories and rejects empty
+// or duplicate names; names are matched case-insensitively.
+func normalizeTargetColumns(values []models.DatasetTargetColumn) ([]models.DatasetTargetColumn, error) {
+	normalized := make([]models.DatasetTargetColumn, 0, len(values))
+	seen := map[string]bool{}
+	for _, value := range values {
+		item := models.DatasetTargetColumn{
+			Name:     strings.TrimSpace(value.Name),
+			Category: strings.TrimSpace(value.Category),
+		}
+		if item.Name == "" {
+			return nil, fmt.Errorf("target_columns entries need a name: %w", ErrInvalid)
+		}
+		key := strings.ToLower(item.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("target column %q is listed twice: %w", item.Name, ErrInvalid)
+		}
+		seen[key] = true
+		normalized = append(normalized, item)
+	}
+	return normalized, nil
+}
+
 func (s *DatasetService) CreateFile(ctx context.Context, hackathonID string, input models.DatasetFile) (*models.DatasetFile, error) {
 	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
 		return nil, err
@@ -231,42 +291,29 @@ func (s *DatasetService) CreateFile(ctx context.Context, hackathonID string, inp
 		UpdatedAt:   now,
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		INSERT INTO dataset_files (id, dataset_id, name, file_type, description, url, size_bytes, checksum, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
-		file.ID, file.DatasetID, file.Name, file.FileType, file.Description, file.URL, file.SizeBytes, file.Checksum, file.CreatedAt, file.UpdatedAt,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO dataset_files (id, dataset_id, name, file_type, description, url, size_bytes, checksum, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+			file.ID, file.DatasetID, file.Name, file.FileType, file.Description, file.URL, file.SizeBytes, file.Checksum, file.CreatedAt, file.UpdatedAt,
+		)
+		return mapSQLError(err)
+	}, datasetFileEvent("hackathon.data.file.created", hackathonID, file.ID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return &file, nil
 }
 
-func (s *DatasetService) ListFiles(ctx context.Context, hackathonID string, limit, offset int) ([]models.DatasetFile, error) {
+func (s *DatasetService) ListFiles(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.DatasetFile], error) {
 	datasetID, err := s.getDatasetID(ctx, hackathonID)
 	if err != nil {
 		return nil, err
 	}
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, dataset_id, name, file_type, description, url, size_bytes, checksum, created_at, updated_at
-		FROM dataset_files
-		WHERE dataset_id = $1
-		ORDER BY created_at
-		LIMIT $2 OFFSET $3`, datasetID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.DatasetFile
-	for rows.Next() {
-		var f models.DatasetFile
-		if err := rows.Scan(&f.ID, &f.DatasetID, &f.Name, &f.FileType, &f.Description, &f.URL, &f.SizeBytes, &f.Checksum, &f.CreatedAt, &f.UpdatedAt); err != nil {
-			return nil, mapSQLError(err)
-		}
-		items = append(items, f)
-	}
-	return items, nil
+	var q listQuery
+	q.where("dataset_id = %s", datasetID)
+	return queryPage(ctx, s.DB, keyset{columns: datasetFileColumns, from: "dataset_files", at: "created_at", asc: true}, q, page,
+		scanDatasetFile, func(f *models.DatasetFile) (time.Time, string) { return f.CreatedAt, f.ID })
 }
 
 func (s *DatasetService) GetFile(ctx context.Context, hackathonID, fileID string) (*models.DatasetFile, error) {
@@ -275,17 +322,29 @@ func (s *DatasetService) GetFile(ctx context.Context, hackathonID, fileID string
 		return nil, err
 	}
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, dataset_id, name, file_type, description, url, size_bytes, checksum, created_at, updated_at
+		SELECT `+datasetFileColumns+`
 		FROM dataset_files
 		WHERE id = $1 AND dataset_id = $2`, fileID, datasetID)
 
-	var f models.DatasetFile
-	if err := row.Scan(&f.ID, &f.DatasetID, &f.Name, &f.FileType, &f.Description, &f.URL, &f.SizeBytes, &f.Checksum, &f.CreatedAt, &f.UpdatedAt); err != nil {
+	f, err := scanDatasetFile(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, mapSQLError(err)
 	}
+	return f, nil
+}
+
+const datasetFileColumns = `id, dataset_id, name, file_type, description, url, size_bytes, checksum,
+		       COALESCE(storage_key, ''), COALESCE(content_type, ''), created_at, updated_at`
+
+func scanDatasetFile(row rowScanner) (*models.DatasetFile, error) {
+	var f models.DatasetFile
+	if err := row.Scan(&f.ID, &f.DatasetID, &f.Name, &f.FileType, &f.Description, &f.URL, &f.SizeBytes, &f.Checksum,
+		&f.StorageKey, &f.ContentType, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
 	return &f, nil
 }
 
@@ -309,6 +368,9 @@ func (s *DatasetService) UpdateFile(ctx context.Context, hackathonID, fileID str
 	if existing == nil {
 		return nil, fmt.Errorf("data file not found: %w", ErrNotFound)
 	}
+	if existing.StorageKey != "" && (input.URL != nil || input.SizeBytes != nil || input.Checksum != nil) {
+		return nil, fmt.Errorf("url, size_bytes and checksum of uploaded files cannot be changed: %w", ErrInvalid)
+	}
 
 	name := existing.Name
 	if input.Name != nil {
@@ -351,14 +413,17 @@ func (s *DatasetService) UpdateFile(ctx context.Context, hackathonID, fileID str
 		checksum = *input.Checksum
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE dataset_files
-		SET name = $1, file_type = $2, description = $3, url = $4, size_bytes = $5, checksum = $6, updated_at = NOW()
-		WHERE id = $7 AND dataset_id = $8`,
-		name, fileType, description, url, sizeBytes, checksum, fileID, existing.DatasetID,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE dataset_files
+			SET name = $1, file_type = $2, description = $3, url = $4, size_bytes = $5, checksum = $6, updated_at = NOW()
+			WHERE id = $7 AND dataset_id = $8`,
+			name, fileType, description, url, sizeBytes, checksum, fileID, existing.DatasetID,
+		)
+		return mapSQLError(err)
+	}, datasetFileEvent("hackathon.data.file.updated", hackathonID, fileID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return s.GetFile(ctx, hackathonID, fileID)
 }
@@ -374,16 +439,30 @@ func (s *DatasetService) DeleteFile(ctx context.Context, hackathonID, fileID str
 	if existing == nil {
 		return fmt.Errorf("data file not found: %w", ErrNotFound)
 	}
-	res, err := s.DB.ExecContext(ctx, `DELETE FROM dataset_files WHERE id = $1 AND dataset_id = $2`, fileID, existing.DatasetID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM dataset_files WHERE id = $1 AND dataset_id = $2`, fileID, existing.DatasetID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("data file not found: %w", ErrNotFound)
+		}
+		return nil
+	}, datasetFileEvent("hackathon.data.file.deleted", hackathonID, fileID))
 	if err != nil {
 		return err
 	}
-	if affected == 0 {
-		return fmt.Errorf("data file not found: %w", ErrNotFound)
+	if existing.StorageKey != "" && s.Storage.Blobs != nil {
+		// Dataset versions keep serving the blob; otherwise the row is gone
+		// and a blob left behind is only wasted space.
+		versioned, err := storageKeyInVersion(ctx, s.DB, hackathonID, existing.StorageKey)
+		if err == nil && !versioned {
+			_ = s.Storage.Blobs.Delete(ctx, existing.StorageKey)
+		}
 	}
 	return nil
 }
@@ -424,42 +503,29 @@ func (s *DatasetService) CreateVariable(ctx context.Context, hackathonID string,
 		UpdatedAt:   now,
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		INSERT INTO dataset_variables (id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
-		variable.ID, variable.DatasetID, variable.Name, variable.Role, variable.DataType, variable.Description, variable.Unit, variable.Category, variable.CreatedAt, variable.UpdatedAt,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO dataset_variables (id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+			variable.ID, variable.DatasetID, variable.Name, variable.Role, variable.DataType, variable.Description, variable.Unit, variable.Category, variable.CreatedAt, variable.UpdatedAt,
+		)
+		return mapSQLError(err)
+	}, datasetVariableEvent("hackathon.data.variable.created", hackathonID, variable.ID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return &variable, nil
 }
 
-func (s *DatasetService) ListVariables(ctx context.Context, hackathonID string, limit, offset int) ([]models.DatasetVariable, error) {
+func (s *DatasetService) ListVariables(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.DatasetVariable], error) {
 	datasetID, err := s.getDatasetID(ctx, hackathonID)
 	if err != nil {
 		return nil, err
 	}
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at
-		FROM dataset_variables
-		WHERE dataset_id = $1
-		ORDER BY created_at
-		LIMIT $2 OFFSET $3`, datasetID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.DatasetVariable
-	for rows.Next() {
-		var v models.DatasetVariable
-		if err := rows.Scan(&v.ID, &v.DatasetID, &v.Name, &v.Role, &v.DataType, &v.Description, &v.Unit, &v.Category, &v.CreatedAt, &v.UpdatedAt); err != nil {
-			return nil, mapSQLError(err)
-		}
-		items = append(items, v)
-	}
-	return items, nil
+	var q listQuery
+	q.where("dataset_id = %s", datasetID)
+	return queryPage(ctx, s.DB, keyset{columns: datasetVariableColumns, from: "dataset_variables", at: "created_at", asc: true}, q, page,
+		scanDatasetVariable, func(v *models.DatasetVariable) (time.Time, string) { return v.CreatedAt, v.ID })
 }
 
 func (s *DatasetService) GetVariable(ctx context.Context, hackathonID, variableID string) (*models.DatasetVariable, error) {
@@ -468,17 +534,27 @@ func (s *DatasetService) GetVariable(ctx context.Context, hackathonID, variableI
 		return nil, err
 	}
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at
+		SELECT `+datasetVariableColumns+`
 		FROM dataset_variables
 		WHERE id = $1 AND dataset_id = $2`, variableID, datasetID)
 
-	var v models.DatasetVariable
-	if err := row.Scan(&v.ID, &v.DatasetID, &v.Name, &v.Role, &v.DataType, &v.Description, &v.Unit, &v.Category, &v.CreatedAt, &v.UpdatedAt); err != nil {
+	v, err := scanDatasetVariable(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, mapSQLError(err)
 	}
+	return v, nil
+}
+
+const datasetVariableColumns = `id, dataset_id, name, role, data_type, description, unit, category, created_at, updated_at`
+
+func scanDatasetVariable(row rowScanner) (*models.DatasetVariable, error) {
+	var v models.DatasetVariable
+	if err := row.Scan(&v.ID, &v.DatasetID, &v.Name, &v.Role, &v.DataType, &v.Description, &v.Unit, &v.Category, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
 	return &v, nil
 }
 
@@ -545,14 +621,17 @@ func (s *DatasetService) UpdateVariable(ctx context.Context, hackathonID, variab
 		category = *input.Category
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE dataset_variables
-		SET name = $1, role = $2, data_type = $3, description = $4, unit = $5, category = $6, updated_at = NOW()
-		WHERE id = $7 AND dataset_id = $8`,
-		name, role, dataType, description, unit, category, variableID, existing.DatasetID,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE dataset_variables
+			SET name = $1, role = $2, data_type = $3, description = $4, unit = $5, category = $6, updated_at = NOW()
+			WHERE id = $7 AND dataset_id = $8`,
+			name, role, dataType, description, unit, category, variableID, existing.DatasetID,
+		)
+		return mapSQLError(err)
+	}, datasetVariableEvent("hackathon.data.variable.updated", hackathonID, variableID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return s.GetVariable(ctx, hackathonID, variableID)
 }
@@ -568,18 +647,32 @@ func (s *DatasetService) DeleteVariable(ctx context.Context, hackathonID, variab
 	if existing == nil {
 		return fmt.Errorf("variable not found: %w", ErrNotFound)
 	}
-	res, err := s.DB.ExecContext(ctx, `DELETE FROM dataset_variables WHERE id = $1 AND dataset_id = $2`, variableID, existing.DatasetID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return fmt.Errorf("variable not found: %w", ErrNotFound)
-	}
-	return nil
+	return execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM dataset_variables WHERE id = $1 AND dataset_id = $2`, variableID, existing.DatasetID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("variable not found: %w", ErrNotFound)
+		}
+		return nil
+	}, datasetVariableEvent("hackathon.data.variable.deleted", hackathonID, variableID))
+}
+
+func datasetEvent(subject, hackathonID, datasetID string) domainEvent {
+	return domainEvent{subject, map[string]any{"hackathon_id": hackathonID, "dataset_id": datasetID}}
+}
+
+func datasetFileEvent(subject, hackathonID, fileID string) domainEvent {
+	return domainEvent{subject, map[string]any{"hackathon_id": hackathonID, "file_id": fileID}}
+}
+
+func datasetVariableEvent(subject, hackathonID, variableID string) domainEvent {
+	return domainEvent{subject, map[string]any{"hackathon_id": hackathonID, "variable_id": variableID}}
 }
 
 func normalizeFileType(value string) string {
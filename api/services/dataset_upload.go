@@ -0,0 +1,650 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/blobstore"
+	"github.com/google/uuid"
+)
+
+// DatasetStorage configures where uploaded dataset files are kept. Zero
+// durations and sizes fall back to the defaults below.
+type DatasetStorage struct {
+	Blobs          blobstore.Store
+	SignedURLTTL   time.Duration
+	UploadTTL      time.Duration
+	MaxUploadBytes int64
+}
+
+const (
+	defaultSignedURLTTL   = 15 * time.Minute
+	defaultUploadTTL      = 24 * time.Hour
+	defaultMaxUploadBytes = 5 << 30
+
+	// uploadReservationTTL bounds how long a crashed chunk write or assembly
+	// can hold an upload; it matches the upload request timeout.
+	uploadReservationTTL = time.Hour
+)
+
+// DatasetFileUploadInput describes a file sent in one multipart request.
+type DatasetFileUploadInput struct {
+	Name        string `json:"name"`
+	FileType    string `json:"file_type"`
+	Description string `json:"description,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// DatasetUploadInput opens a resumable upload of SizeBytes bytes.
+type DatasetUploadInput struct {
+	DatasetFileUploadInput
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// UploadFile stores body in the blob store and records it as a dataset file.
+// Size and SHA-256 checksum are computed from the bytes received.
+func (s *DatasetService) UploadFile(ctx context.Context, hackathonID string, input DatasetFileUploadInput, body io.Reader, size int64) (*models.DatasetFile, error) {
+	if err := s.ensureStorage(); err != nil {
+		return nil, err
+	}
+	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	datasetID, err := s.getDatasetID(ctx, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	input, err = s.normalizeUploadInput(input, size)
+	if err != nil {
+		return nil, err
+	}
+
+	file := newUploadedFile(datasetID, input)
+	written, checksum, err := s.putDigest(ctx, file.StorageKey, body, size, input.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	file.SizeBytes, file.Checksum = written, checksum
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		return insertUploadedFile(ctx, tx, file)
+	}, datasetFileEvent("hackathon.data.file.created", hackathonID, file.ID))
+	if err != nil {
+		_ = s.Storage.Blobs.Delete(ctx, file.StorageKey)
+		return nil, err
+	}
+	return file, nil
+}
+
+// CreateUpload opens a resumable upload session for a new dataset file.
+func (s *DatasetService) CreateUpload(ctx context.Context, hackathonID string, input DatasetUploadInput, actorID string) (*models.DatasetUpload, error) {
+	if err := s.ensureStorage(); err != nil {
+		return nil, err
+	}
+	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	datasetID, err := s.getDatasetID(ctx, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if input.SizeBytes <= 0 {
+		return nil, fmt.Errorf("size_bytes must be > 0: %w", ErrInvalid)
+	}
+	file, err := s.normalizeUploadInput(input.DatasetFileUploadInput, input.SizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	var exists bool
+	if err := s.DB.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM dataset_files WHERE dataset_id = $1 AND name = $2)`,
+		datasetID, file.Name).Scan(&exists); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if exists {
+		return nil, fmt.Errorf("a data file named %q already exists: %w", file.Name, ErrConflict)
+	}
+
+	now := time.Now().UTC()
+	upload := &models.DatasetUpload{
+		ID:          uuid.NewString(),
+		HackathonID: hackathonID,
+		DatasetID:   datasetID,
+		Name:        file.Name,
+		FileType:    file.FileType,
+		Description: file.Description,
+		ContentType: file.ContentType,
+		SizeBytes:   input.SizeBytes,
+		Status:      models.DatasetUploadStatusPending,
+		CreatedBy:   actorID,
+		ExpiresAt:   now.Add(s.uploadTTL()),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO dataset_uploads (
+			id, hackathon_id, dataset_id, name, file_type, description, content_type, size_bytes,
+			offset_bytes, parts, status, created_by, expires_at, created_at, updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,0,0,$9,NULLIF($10, ''),$11,$12,$12)`,
+		upload.ID, upload.HackathonID, upload.DatasetID, upload.Name, upload.FileType, upload.Description,
+		upload.ContentType, upload.SizeBytes, upload.Status, upload.CreatedBy, upload.ExpiresAt, now,
+	)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return upload, nil
+}
+
+// GetUpload returns an upload session, with its file once completed.
+func (s *DatasetService) GetUpload(ctx context.Context, hackathonID, uploadID string) (*models.DatasetUpload, error) {
+	upload, err := loadDatasetUpload(ctx, s.DB, hackathonID, uploadID, false)
+	if err != nil || upload == nil {
+		return upload, err
+	}
+	if upload.FileID != "" {
+		if upload.File, err = s.GetFile(ctx, hackathonID, upload.FileID); err != nil {
+			return nil, err
+		}
+	}
+	return upload, nil
+}
+
+// AppendUpload stores the chunk that starts at offset. A chunk that does not
+// start at the current offset, or arrives while another chunk is being
+// written, is rejected with ErrConflict so the client can resume from
+// GetUpload. The offset is reserved and committed in two short transactions
+// so the upload row is not locked while the chunk is written to the blob
+// store. The chunk that completes the upload marks it uploaded and runs
+// CompleteUpload.
+func (s *DatasetService) AppendUpload(ctx context.Context, hackathonID, uploadID string, offset int64, body io.Reader, length int64) (*models.DatasetUpload, error) {
+	if err := s.ensureStorage(); err != nil {
+		return nil, err
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("chunk Content-Length is required: %w", ErrInvalid)
+	}
+
+	upload, reservation, err := s.reserveUploadChunk(ctx, hackathonID, uploadID, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	partKey := uploadPartKey(upload.ID, upload.Parts)
+	counter := &countingReader{r: body}
+	if err := s.Storage.Blobs.Put(ctx, partKey, counter, length, ""); err != nil {
+		s.releaseUpload(ctx, upload.ID, reservation)
+		if counter.n != length {
+			return nil, fmt.Errorf("chunk has %d bytes, Content-Length is %d: %w", counter.n, length, ErrInvalid)
+		}
+		return nil, err
+	}
+	if err := s.commitUploadChunk(ctx, upload, reservation, length); err != nil {
+		_ = s.Storage.Blobs.Delete(ctx, partKey)
+		s.releaseUpload(ctx, upload.ID, reservation)
+		return nil, err
+	}
+	if upload.Status == models.DatasetUploadStatusUploaded {
+		return s.CompleteUpload(ctx, hackathonID, uploadID)
+	}
+	return upload, nil
+}
+
+// reserveUploadChunk checks that a chunk of length bytes can be written at
+// offset and reserves the upload for it until uploadReservationTTL.
+func (s *DatasetService) reserveUploadChunk(ctx context.Context, hackathonID, uploadID string, offset, length int64) (*models.DatasetUpload, string, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	upload, err := loadDatasetUpload(ctx, tx, hackathonID, uploadID, true)
+	if err != nil {
+		return nil, "", err
+	}
+	if upload == nil {
+		return nil, "", fmt.Errorf("upload not found: %w", ErrNotFound)
+	}
+	if upload.Status != models.DatasetUploadStatusPending {
+		return nil, "", fmt.Errorf("upload is %s: %w", upload.Status, ErrInvalid)
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, "", fmt.Errorf("upload expired at %s: %w", upload.ExpiresAt.Format(time.RFC3339), ErrInvalid)
+	}
+	if offset != upload.OffsetBytes {
+		return nil, "", fmt.Errorf("upload offset is %d, not %d: %w", upload.OffsetBytes, offset, ErrConflict)
+	}
+	if offset+length > upload.SizeBytes {
+		return nil, "", fmt.Errorf("chunk ends at %d, past the upload size %d: %w", offset+length, upload.SizeBytes, ErrInvalid)
+	}
+	reservation, err := reserveUpload(ctx, tx, upload.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if reservation == "" {
+		return nil, "", fmt.Errorf("another chunk is being written: %w", ErrConflict)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, "", err
+	}
+	return upload, reservation, nil
+}
+
+// commitUploadChunk advances the offset past a stored chunk, records the part
+// and releases the reservation. The upload becomes uploaded once every byte
+// was received.
+func (s *DatasetService) commitUploadChunk(ctx context.Context, upload *models.DatasetUpload, reservation string, length int64) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	err = tx.QueryRowContext(ctx, `
+		UPDATE dataset_uploads
+		SET offset_bytes = offset_bytes + $1, parts = parts + 1,
+		    status = CASE WHEN offset_bytes + $1 = size_bytes THEN $2 ELSE status END,
+		    reservation_id = NULL, reserved_until = NULL, updated_at = $3
+		WHERE id = $4 AND reservation_id = $5 AND status = $6
+		RETURNING offset_bytes, parts, status`,
+		length, models.DatasetUploadStatusUploaded, now, upload.ID, reservation, models.DatasetUploadStatusPending,
+	).Scan(&upload.OffsetBytes, &upload.Parts, &upload.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("upload was aborted or its reservation expired: %w", ErrConflict)
+	}
+	if err != nil {
+		return mapSQLError(err)
+	}
+	upload.UpdatedAt = now
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dataset_upload_parts (upload_id, part, offset_bytes, size_bytes, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		upload.ID, upload.Parts-1, upload.OffsetBytes-length, length, now); err != nil {
+		return mapSQLError(err)
+	}
+	return tx.Commit()
+}
+
+// CompleteUpload assembles the parts of an uploaded session into the dataset
+// file. The upload is reserved while the parts are copied, outside of any
+// transaction, and the file is created in a short transaction afterwards. It
+// can be retried when a previous assembly failed and returns completed
+// uploads as they are.
+func (s *DatasetService) CompleteUpload(ctx context.Context, hackathonID, uploadID string) (*models.DatasetUpload, error) {
+	if err := s.ensureStorage(); err != nil {
+		return nil, err
+	}
+	upload, err := loadDatasetUpload(ctx, s.DB, hackathonID, uploadID, false)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil {
+		return nil, fmt.Errorf("upload not found: %w", ErrNotFound)
+	}
+	switch upload.Status {
+	case models.DatasetUploadStatusCompleted:
+		return s.GetUpload(ctx, hackathonID, uploadID)
+	case models.DatasetUploadStatusUploaded:
+	default:
+		return nil, fmt.Errorf("upload is %s: %w", upload.Status, ErrInvalid)
+	}
+	reservation, err := reserveUpload(ctx, s.DB, upload.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation == "" {
+		return nil, fmt.Errorf("upload is being assembled: %w", ErrConflict)
+	}
+
+	file, err := s.assembleUpload(ctx, upload)
+	if err != nil {
+		s.releaseUpload(ctx, upload.ID, reservation)
+		return nil, err
+	}
+	now := time.Now().UTC()
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		if err := insertUploadedFile(ctx, tx, file); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			UPDATE dataset_uploads
+			SET status = $1, file_id = $2, reservation_id = NULL, reserved_until = NULL, updated_at = $3
+			WHERE id = $4 AND reservation_id = $5 AND status = $6`,
+			models.DatasetUploadStatusCompleted, file.ID, now, upload.ID, reservation, models.DatasetUploadStatusUploaded)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return fmt.Errorf("upload was aborted or its reservation expired: %w", ErrConflict)
+		}
+		return nil
+	}, datasetFileEvent("hackathon.data.file.created", hackathonID, file.ID))
+	if err != nil {
+		_ = s.Storage.Blobs.Delete(ctx, file.StorageKey)
+		s.releaseUpload(ctx, upload.ID, reservation)
+		return nil, err
+	}
+	s.deleteUploadParts(ctx, upload)
+
+	upload.Status = models.DatasetUploadStatusCompleted
+	upload.FileID = file.ID
+	upload.File = file
+	upload.UpdatedAt = now
+	return upload, nil
+}
+
+// AbortUpload cancels an unfinished upload and deletes its stored chunks.
+// Uploads with a chunk write or an assembly in progress are left alone.
+func (s *DatasetService) AbortUpload(ctx context.Context, hackathonID, uploadID string) error {
+	upload, err := loadDatasetUpload(ctx, s.DB, hackathonID, uploadID, false)
+	if err != nil {
+		return err
+	}
+	if upload == nil {
+		return fmt.Errorf("upload not found: %w", ErrNotFound)
+	}
+	if upload.Status != models.DatasetUploadStatusPending && upload.Status != models.DatasetUploadStatusUploaded {
+		return fmt.Errorf("upload is %s: %w", upload.Status, ErrInvalid)
+	}
+	res, err := s.DB.ExecContext(ctx, `
+		UPDATE dataset_uploads SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status IN ($3, $4) AND (reserved_until IS NULL OR reserved_until <= NOW())`,
+		models.DatasetUploadStatusAborted, uploadID, models.DatasetUploadStatusPending, models.DatasetUploadStatusUploaded)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("upload is busy or no longer in progress: %w", ErrConflict)
+	}
+	if s.Storage.Blobs != nil {
+		s.deleteUploadParts(ctx, upload)
+	}
+	return nil
+}
+
+// DownloadFile returns a signed, expiring link for an uploaded file, or the
+// external URL of a file that was only registered by link.
+func (s *DatasetService) DownloadFile(ctx context.Context, hackathonID, fileID string) (*models.DatasetFileDownload, error) {
+	file, err := s.GetFile(ctx, hackathonID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("data file not found: %w", ErrNotFound)
+	}
+	if file.StorageKey == "" {
+		return &models.DatasetFileDownload{URL: file.URL}, nil
+	}
+	return s.signedDownload(ctx, file.StorageKey)
+}
+
+func (s *DatasetService) signedDownload(ctx context.Context, key string) (*models.DatasetFileDownload, error) {
+	if err := s.ensureStorage(); err != nil {
+		return nil, err
+	}
+	ttl := s.Storage.SignedURLTTL
+	if ttl <= 0 {
+		ttl = defaultSignedURLTTL
+	}
+	link, err := s.Storage.Blobs.SignedURL(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+	expires := time.Now().UTC().Add(ttl)
+	return &models.DatasetFileDownload{URL: link, ExpiresAt: &expires}, nil
+}
+
+// assembleUpload concatenates the parts of a finished upload into the final
+// blob. The caller records the returned file.
+func (s *DatasetService) assembleUpload(ctx context.Context, upload *models.DatasetUpload) (*models.DatasetFile, error) {
+	file := newUploadedFile(upload.DatasetID, DatasetFileUploadInput{
+		Name:        upload.Name,
+		FileType:    upload.FileType,
+		Description: upload.Description,
+		ContentType: upload.ContentType,
+	})
+	parts := &partsReader{ctx: ctx, store: s.Storage.Blobs, keys: uploadPartKeys(upload)}
+	defer parts.Close()
+
+	written, checksum, err := s.putDigest(ctx, file.StorageKey, parts, upload.SizeBytes, upload.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	file.SizeBytes, file.Checksum = written, checksum
+	return file, nil
+}
+
+// putDigest writes r under key and returns the byte count and hex SHA-256 of
+// what was written.
+func (s *DatasetService) putDigest(ctx context.Context, key string, r io.Reader, size int64, contentType string) (int64, string, error) {
+	digest := &digestReader{r: r, h: sha256.New()}
+	if err := s.Storage.Blobs.Put(ctx, key, digest, size, contentType); err != nil {
+		return 0, "", err
+	}
+	if size >= 0 && digest.n != size {
+		_ = s.Storage.Blobs.Delete(ctx, key)
+		return 0, "", fmt.Errorf("received %d bytes, expected %d: %w", digest.n, size, ErrInvalid)
+	}
+	return digest.n, hex.EncodeToString(digest.h.Sum(nil)), nil
+}
+
+func (s *DatasetService) normalizeUploadInput(input DatasetFileUploadInput, size int64) (DatasetFileUploadInput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.FileType = normalizeFileType(input.FileType)
+	input.ContentType = strings.TrimSpace(input.ContentType)
+	if input.Name == "" || input.FileType == "" {
+		return input, fmt.Errorf("file name and file_type are required: %w", ErrInvalid)
+	}
+	if !isAllowedFileType(input.FileType) {
+		return input, fmt.Errorf("unsupported file_type: %w", ErrInvalid)
+	}
+	limit := s.Storage.MaxUploadBytes
+	if limit <= 0 {
+		limit = defaultMaxUploadBytes
+	}
+	if size > limit {
+		return input, fmt.Errorf("file is larger than the %d byte upload limit: %w", limit, ErrInvalid)
+	}
+	return input, nil
+}
+
+func (s *DatasetService) ensureStorage() error {
+	if s.Storage.Blobs == nil {
+		return fmt.Errorf("file storage is not configured: %w", ErrInvalid)
+	}
+	return nil
+}
+
+func (s *DatasetService) uploadTTL() time.Duration {
+	if s.Storage.UploadTTL > 0 {
+		return s.Storage.UploadTTL
+	}
+	return defaultUploadTTL
+}
+
+// reserveUpload takes the upload for uploadReservationTTL unless a live
+// reservation exists, and returns the new reservation id ("" when taken).
+func reserveUpload(ctx context.Context, db execer, uploadID string) (string, error) {
+	reservation := uuid.NewString()
+	now := time.Now().UTC()
+	res, err := db.ExecContext(ctx, `
+		UPDATE dataset_uploads SET reservation_id = $1, reserved_until = $2
+		WHERE id = $3 AND (reserved_until IS NULL OR reserved_until <= $4)`,
+		reservation, now.Add(uploadReservationTTL), uploadID, now)
+	if err != nil {
+		return "", mapSQLError(err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return "", err
+	} else if n == 0 {
+		return "", nil
+	}
+	return reservation, nil
+}
+
+// releaseUpload drops a reservation after a failed chunk or assembly so the
+// client can retry right away.
+func (s *DatasetService) releaseUpload(ctx context.Context, uploadID, reservation string) {
+	_, _ = s.DB.ExecContext(ctx, `
+		UPDATE dataset_uploads SET reservation_id = NULL, reserved_until = NULL
+		WHERE id = $1 AND reservation_id = $2`, uploadID, reservation)
+}
+
+func (s *DatasetService) deleteUploadParts(ctx context.Context, upload *models.DatasetUpload) {
+	for _, key := range uploadPartKeys(upload) {
+		_ = s.Storage.Blobs.Delete(ctx, key)
+	}
+}
+
+func newUploadedFile(datasetID string, input DatasetFileUploadInput) *models.DatasetFile {
+	now := time.Now().UTC()
+	id := uuid.NewString()
+	return &models.DatasetFile{
+		ID:          id,
+		DatasetID:   datasetID,
+		Name:        input.Name,
+		FileType:    input.FileType,
+		Description: input.Description,
+		StorageKey:  datasetFileKey(datasetID, id, input.Name),
+		ContentType: input.ContentType,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func insertUploadedFile(ctx context.Context, db execer, file *models.DatasetFile) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO dataset_files (
+			id, dataset_id, name, file_type, description, url, size_bytes, checksum, storage_key, content_type, created_at, updated_at
+		) VALUES ($1,$2,$3,$4,$5,'',$6,$7,$8,NULLIF($9, ''),$10,$11)`,
+		file.ID, file.DatasetID, file.Name, file.FileType, file.Description, file.SizeBytes, file.Checksum,
+		file.StorageKey, file.ContentType, file.CreatedAt, file.UpdatedAt,
+	)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	return nil
+}
+
+const datasetUploadColumns = `id, hackathon_id, dataset_id, name, file_type, description, content_type, size_bytes,
+		       offset_bytes, parts, status, COALESCE(file_id::text, ''), COALESCE(created_by, ''),
+		       expires_at, created_at, updated_at`
+
+func loadDatasetUpload(ctx context.Context, q rowQuerier, hackathonID, uploadID string, lock bool) (*models.DatasetUpload, error) {
+	query := `SELECT ` + datasetUploadColumns + ` FROM dataset_uploads WHERE id = $1 AND hackathon_id = $2`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var u models.DatasetUpload
+	err := q.QueryRowContext(ctx, query, uploadID, hackathonID).Scan(
+		&u.ID, &u.HackathonID, &u.DatasetID, &u.Name, &u.FileType, &u.Description, &u.ContentType, &u.SizeBytes,
+		&u.OffsetBytes, &u.Parts, &u.Status, &u.FileID, &u.CreatedBy,
+		&u.ExpiresAt, &u.CreatedAt, &u.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return &u, nil
+}
+
+// datasetFileKey places a file under its dataset and id, keeping only the
+// base name the organizer chose.
+func datasetFileKey(datasetID, fileID, name string) string {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if base == "." || base == ".." || base == "/" {
+		base = "file"
+	}
+	return "datasets/" + datasetID + "/" + fileID + "/" + base
+}
+
+func uploadPartKey(uploadID string, index int) string {
+	return fmt.Sprintf("uploads/%s/%06d", uploadID, index)
+}
+
+func uploadPartKeys(upload *models.DatasetUpload) []string {
+	keys := make([]string, upload.Parts)
+	for i := range keys {
+		keys[i] = uploadPartKey(upload.ID, i)
+	}
+	return keys
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type digestReader struct {
+	r io.Reader
+	h hash.Hash
+	n int64
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.h.Write(p[:n])
+	d.n += int64(n)
+	return n, err
+}
+
+// partsReader reads the stored parts of an upload one after another.
+type partsReader struct {
+	ctx   context.Context
+	store blobstore.Store
+	keys  []string
+	cur   io.ReadCloser
+}
+
+func (p *partsReader) Read(b []byte) (int, error) {
+	for {
+		if p.cur == nil {
+			if len(p.keys) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := p.store.Get(p.ctx, p.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			p.cur, p.keys = rc, p.keys[1:]
+		}
+		n, err := p.cur.Read(b)
+		if errors.Is(err, io.EOF) {
+			p.cur.Close()
+			p.cur = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (p *partsReader) Close() error {
+	if p.cur != nil {
+		return p.cur.Close()
+	}
+	return nil
+}
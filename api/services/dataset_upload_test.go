@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/blobstore"
+)
+
+func testDatasetStorage(t *testing.T) DatasetStorage {
+	t.Helper()
+	store, err := blobstore.NewLocalStore(t.TempDir(), "http://localhost/blobs", []byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	return DatasetStorage{Blobs: store}
+}
+
+func TestPutDigestComputesSizeAndChecksum(t *testing.T) {
+	ctx := context.Background()
+	svc := &DatasetService{Storage: testDatasetStorage(t)}
+	content := "id,label\n1,0\n2,1\n"
+
+	size, checksum, err := svc.putDigest(ctx, "datasets/d/f/train.csv", strings.NewReader(content), int64(len(content)), "text/csv")
+	if err != nil {
+		t.Fatalf("putDigest: %v", err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	if size != int64(len(content)) || checksum != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected size=%d checksum=%s", size, checksum)
+	}
+	if _, _, err := svc.putDigest(ctx, "datasets/d/f/short.csv", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Fatalf("expected size mismatch error")
+	}
+}
+
+func TestPartsReaderConcatenatesUploadParts(t *testing.T) {
+	ctx := context.Background()
+	storage := testDatasetStorage(t)
+	upload := &models.DatasetUpload{ID: "u1", Parts: 3}
+	for i, chunk := range []string{"hello ", "", "world"} {
+		if err := storage.Blobs.Put(ctx, uploadPartKey(upload.ID, i), strings.NewReader(chunk), int64(len(chunk)), ""); err != nil {
+			t.Fatalf("Put part %d: %v", i, err)
+		}
+	}
+	parts := &partsReader{ctx: ctx, store: storage.Blobs, keys: uploadPartKeys(upload)}
+	defer parts.Close()
+	body, err := io.ReadAll(parts)
+	if err != nil || string(body) != "hello world" {
+		t.Fatalf("unexpected body=%q err=%v", body, err)
+	}
+
+	missing := &partsReader{ctx: ctx, store: storage.Blobs, keys: []string{uploadPartKey("u2", 0)}}
+	if _, err := io.ReadAll(missing); !errors.Is(err, blobstore.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got=%v", err)
+	}
+}
+
+func TestDatasetFileKeyKeepsBaseName(t *testing.T) {
+	cases := map[string]string{
+		"train.csv":          "datasets/d/f/train.csv",
+		"../../etc/passwd":   "datasets/d/f/passwd",
+		`C:\data\test.csv`:   "datasets/d/f/test.csv",
+		"..":                 "datasets/d/f/file",
+		"folder/sub/a b.csv": "datasets/d/f/a b.csv",
+	}
+	for name, want := range cases {
+		if got := datasetFileKey("d", "f", name); got != want {
+			t.Fatalf("%q: got=%q want=%q", name, got, want)
+		}
+	}
+	if got := uploadPartKey("u", 12); got != "uploads/u/000012" {
+		t.Fatalf("unexpected part key %q", got)
+	}
+}
+
+func TestNormalizeUploadInput(t *testing.T) {
+	svc := &DatasetService{Storage: DatasetStorage{MaxUploadBytes: 100}}
+	input, err := svc.normalizeUploadInput(DatasetFileUploadInput{Name: " train.csv ", FileType: " TRAIN "}, 10)
+	if err != nil || input.Name != "train.csv" || input.FileType != "train" {
+		t.Fatalf("unexpected input=%+v err=%v", input, err)
+	}
+	for _, tc := range []struct {
+		input DatasetFileUploadInput
+		size  int64
+	}{
+		{DatasetFileUploadInput{FileType: "train"}, 10},
+		{DatasetFileUploadInput{Name: "x", FileType: "binary"}, 10},
+		{DatasetFileUploadInput{Name: "x", FileType: "train"}, 101},
+	} {
+		if _, err := svc.normalizeUploadInput(tc.input, tc.size); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%+v: expected ErrInvalid, got=%v", tc.input, err)
+		}
+	}
+	if err := (&DatasetService{}).ensureStorage(); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid without a blob store, got=%v", err)
+	}
+}
+
+func TestAppendUploadWritesChunkBetweenShortTransactions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	svc := &DatasetService{DB: db, Storage: testDatasetStorage(t)}
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM dataset_uploads WHERE id = \\$1 AND hackathon_id = \\$2 FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "hackathon_id", "dataset_id", "name", "file_type", "description", "content_type", "size_bytes",
+			"offset_bytes", "parts", "status", "file_id", "created_by", "expires_at", "created_at", "updated_at",
+		}).AddRow("u1", "h1", "d1", "train.csv", "train", "", "", 10, 4, 1, models.DatasetUploadStatusPending, "", "", now.Add(time.Hour), now, now))
+	mock.ExpectExec("UPDATE dataset_uploads SET reservation_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE dataset_uploads\\s+SET offset_bytes = offset_bytes \\+ \\$1").
+		WillReturnRows(sqlmock.NewRows([]string{"offset_bytes", "parts", "status"}).AddRow(7, 2, models.DatasetUploadStatusPending))
+	mock.ExpectExec("INSERT INTO dataset_upload_parts").
+		WithArgs("u1", 1, int64(4), int64(3), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	upload, err := svc.AppendUpload(ctx, "h1", "u1", 4, strings.NewReader("abc"), 3)
+	if err != nil {
+		t.Fatalf("AppendUpload: %v", err)
+	}
+	if upload.OffsetBytes != 7 || upload.Parts != 2 || upload.Status != models.DatasetUploadStatusPending {
+		t.Fatalf("unexpected upload %+v", upload)
+	}
+	rc, err := svc.Storage.Blobs.Get(ctx, uploadPartKey("u1", 1))
+	if err != nil {
+		t.Fatalf("chunk was not stored: %v", err)
+	}
+	rc.Close()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppendUploadRejectsChunkWhileAnotherIsWritten(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	svc := &DatasetService{DB: db, Storage: testDatasetStorage(t)}
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM dataset_uploads").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "hackathon_id", "dataset_id", "name", "file_type", "description", "content_type", "size_bytes",
+			"offset_bytes", "parts", "status", "file_id", "created_by", "expires_at", "created_at", "updated_at",
+		}).AddRow("u1", "h1", "d1", "train.csv", "train", "", "", 10, 0, 0, models.DatasetUploadStatusPending, "", "", now.Add(time.Hour), now, now))
+	mock.ExpectExec("UPDATE dataset_uploads SET reservation_id").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	if _, err := svc.AppendUpload(context.Background(), "h1", "u1", 0, strings.NewReader("abc"), 3); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got=%v", err)
+	}
+	if _, err := svc.Storage.Blobs.Get(context.Background(), uploadPartKey("u1", 0)); !errors.Is(err, blobstore.ErrNotFound) {
+		t.Fatalf("no chunk should be stored, got=%v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
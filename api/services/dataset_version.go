@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// CreateVersion snapshots the current dataset on demand. created is false
+// when the content matches the latest version, which is returned instead.
+func (s *DatasetService) CreateVersion(ctx context.Context, hackathonID, actorID string) (*models.DatasetVersion, bool, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	version, created, err := snapshotDataset(ctx, tx, hackathonID, models.DatasetVersionReasonManual, actorID)
+	if err != nil {
+		return nil, false, err
+	}
+	if version == nil {
+		return nil, false, fmt.Errorf("dataset not found: %w", ErrNotFound)
+	}
+	if created {
+		if err := publishInTx(ctx, tx, s.Events, datasetVersionEvent(version)); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return version, created, nil
+}
+
+// ListVersions returns the versions newest first, without their content.
+func (s *DatasetService) ListVersions(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.DatasetVersion], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, dataset_id, version, reason, checksum, created_by, created_at`,
+		from:    "dataset_versions",
+		at:      "created_at",
+	}, q, page, func(row rowScanner) (*models.DatasetVersion, error) {
+		var v models.DatasetVersion
+		if err := row.Scan(&v.ID, &v.HackathonID, &v.DatasetID, &v.Version, &v.Reason, &v.Checksum, &v.CreatedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}, func(v *models.DatasetVersion) (time.Time, string) { return v.CreatedAt, v.ID })
+}
+
+func (s *DatasetService) GetVersion(ctx context.Context, hackathonID string, version int) (*models.DatasetVersion, error) {
+	v, err := scanDatasetVersion(s.DB.QueryRowContext(ctx, `
+		SELECT `+datasetVersionColumns+`
+		FROM dataset_versions
+		WHERE hackathon_id = $1 AND version = $2`, hackathonID, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return v, nil
+}
+
+// DiffVersions compares two dataset versions of a hackathon by number.
+func (s *DatasetService) DiffVersions(ctx context.Context, hackathonID string, from, to int) (*models.DatasetVersionDiff, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("from and to must be positive version numbers: %w", ErrInvalid)
+	}
+	fromVersion, err := s.GetVersion(ctx, hackathonID, from)
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion == nil {
+		return nil, fmt.Errorf("dataset version %d not found: %w", from, ErrNotFound)
+	}
+	toVersion, err := s.GetVersion(ctx, hackathonID, to)
+	if err != nil {
+		return nil, err
+	}
+	if toVersion == nil {
+		return nil, fmt.Errorf("dataset version %d not found: %w", to, ErrNotFound)
+	}
+	return diffDatasetVersions(fromVersion, toVersion)
+}
+
+// DownloadVersionFile returns a link to a file as it was in the given version.
+// Blobs referenced by a version are kept when the live file is deleted.
+func (s *DatasetService) DownloadVersionFile(ctx context.Context, hackathonID string, version int, fileID string) (*models.DatasetFileDownload, error) {
+	v, err := s.GetVersion(ctx, hackathonID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, fmt.Errorf("dataset version %d not found: %w", version, ErrNotFound)
+	}
+	for _, file := range v.Content.Files {
+		if file.ID != fileID {
+			continue
+		}
+		if file.StorageKey == "" {
+			return &models.DatasetFileDownload{URL: file.URL}, nil
+		}
+		return s.signedDownload(ctx, file.StorageKey)
+	}
+	return nil, fmt.Errorf("data file not found in version %d: %w", version, ErrNotFound)
+}
+
+func diffDatasetVersions(from, to *models.DatasetVersion) (*models.DatasetVersionDiff, error) {
+	fromRaw, err := json.Marshal(from.Content)
+	if err != nil {
+		return nil, err
+	}
+	toRaw, err := json.Marshal(to.Content)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffJSON(fromRaw, toRaw)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DatasetVersionDiff{
+		HackathonID:   to.HackathonID,
+		FromVersion:   from.Version,
+		ToVersion:     to.Version,
+		FromVersionID: from.ID,
+		ToVersionID:   to.ID,
+		Changes:       changes,
+	}, nil
+}
+
+// snapshotDataset records the hackathon's current dataset as a new version in
+// tx, unless it is identical to the latest version, which is then returned
+// with created false. It returns nil when the hackathon has no dataset.
+func snapshotDataset(ctx context.Context, tx *sql.Tx, hackathonID, reason, actorID string) (*models.DatasetVersion, bool, error) {
+	// Locking the dataset row serialises snapshots and keeps the files and
+	// variables read below consistent with it.
+	var datasetID string
+	var sourceRaw, schema []byte
+	snapshot := models.DatasetSnapshot{
+		Files:     map[string]models.DatasetSnapshotFile{},
+		Variables: map[string]models.DatasetSnapshotVariable{},
+	}
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, title, description, source_urls, response_schema
+		FROM hackathon_datasets
+		WHERE hackathon_id = $1
+		FOR UPDATE`, hackathonID).Scan(&datasetID, &snapshot.Title, &snapshot.Description, &sourceRaw, &schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	if err := json.Unmarshal(sourceRaw, &snapshot.SourceURLs); err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	snapshot.ResponseSchema = schema
+	if err := loadSnapshotFiles(ctx, tx, datasetID, snapshot.Files); err != nil {
+		return nil, false, err
+	}
+	if err := loadSnapshotVariables(ctx, tx, datasetID, snapshot.Variables); err != nil {
+		return nil, false, err
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, false, err
+	}
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	latest, err := scanDatasetVersion(tx.QueryRowContext(ctx, `
+		SELECT `+datasetVersionColumns+`
+		FROM dataset_versions
+		WHERE hackathon_id = $1
+		ORDER BY version DESC
+		LIMIT 1`, hackathonID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, mapSQLError(err)
+	}
+	next := 1
+	if latest != nil {
+		if latest.Checksum == checksum && latest.DatasetID == datasetID {
+			return latest, false, nil
+		}
+		next = latest.Version + 1
+	}
+
+	v := models.DatasetVersion{
+		ID:          uuid.NewString(),
+		HackathonID: hackathonID,
+		DatasetID:   datasetID,
+		Version:     next,
+		Reason:      reason,
+		Checksum:    checksum,
+		Content:     &snapshot,
+		CreatedBy:   actorID,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO dataset_versions (id, hackathon_id, dataset_id, version, reason, checksum, content, created_by, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+		v.ID, v.HackathonID, v.DatasetID, v.Version, v.Reason, v.Checksum, content, v.CreatedBy, v.CreatedAt,
+	); err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	return &v, true, nil
+}
+
+func loadSnapshotFiles(ctx context.Context, q rowsQuerier, datasetID string, out map[string]models.DatasetSnapshotFile) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, file_type, COALESCE(description, ''), url, COALESCE(size_bytes, 0),
+		       COALESCE(checksum, ''), COALESCE(storage_key, ''), COALESCE(content_type, '')
+		FROM dataset_files
+		WHERE dataset_id = $1`, datasetID)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f models.DatasetSnapshotFile
+		var name string
+		if err := rows.Scan(&f.ID, &name, &f.FileType, &f.Description, &f.URL, &f.SizeBytes, &f.Checksum, &f.StorageKey, &f.ContentType); err != nil {
+			return mapSQLError(err)
+		}
+		out[name] = f
+	}
+	return mapSQLError(rows.Err())
+}
+
+func loadSnapshotVariables(ctx context.Context, q rowsQuerier, datasetID string, out map[string]models.DatasetSnapshotVariable) error {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, role, data_type, COALESCE(description, ''), COALESCE(unit, ''), COALESCE(category, '')
+		FROM dataset_variables
+		WHERE dataset_id = $1`, datasetID)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v models.DatasetSnapshotVariable
+		var name string
+		if err := rows.Scan(&v.ID, &name, &v.Role, &v.DataType, &v.Description, &v.Unit, &v.Category); err != nil {
+			return mapSQLError(err)
+		}
+		out[name] = v
+	}
+	return mapSQLError(rows.Err())
+}
+
+func datasetVersionEvent(v *models.DatasetVersion) domainEvent {
+	return domainEvent{"hackathon.data.version.created", map[string]any{
+		"hackathon_id":       v.HackathonID,
+		"dataset_id":         v.DatasetID,
+		"dataset_version_id": v.ID,
+		"version":            v.Version,
+		"reason":             v.Reason,
+		"checksum":           v.Checksum,
+	}}
+}
+
+// latestDatasetVersionID returns the newest dataset version of a hackathon,
+// or nil when none has been taken.
+func latestDatasetVersionID(ctx context.Context, q rowQuerier, hackathonID string) (*string, error) {
+	var id string
+	err := q.QueryRowContext(ctx, `
+		SELECT id FROM dataset_versions
+		WHERE hackathon_id = $1
+		ORDER BY version DESC
+		LIMIT 1`, hackathonID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return &id, nil
+}
+
+// storageKeyInVersion reports whether any dataset version still references
+// the blob at key.
+func storageKeyInVersion(ctx context.Context, q rowQuerier, hackathonID, key string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM dataset_versions v, jsonb_each(v.content->'files') f
+			WHERE v.hackathon_id = $1 AND f.value->>'storage_key' = $2
+		)`, hackathonID, key).Scan(&exists)
+	if err != nil {
+		return false, mapSQLError(err)
+	}
+	return exists, nil
+}
+
+const datasetVersionColumns = `id, hackathon_id, dataset_id, version, reason, checksum, content, created_by, created_at`
+
+func scanDatasetVersion(row rowScanner) (*models.DatasetVersion, error) {
+	var v models.DatasetVersion
+	var content []byte
+	if err := row.Scan(&v.ID, &v.HackathonID, &v.DatasetID, &v.Version, &v.Reason, &v.Checksum, &content, &v.CreatedBy, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	var snapshot models.DatasetSnapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, err
+	}
+	v.Content = &snapshot
+	return &v, nil
+}
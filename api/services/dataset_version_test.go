@@ -0,0 +1,79 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestDiffDatasetVersions(t *testing.T) {
+	from := &models.DatasetVersion{ID: "v1", HackathonID: "h1", Version: 1, Content: &models.DatasetSnapshot{
+		Title:          "Grains",
+		SourceURLs:     []string{},
+		ResponseSchema: json.RawMessage(`{"format":"csv"}`),
+		Files: map[string]models.DatasetSnapshotFile{
+			"test.csv":  {ID: "f1", FileType: models.DatasetFileTypeTest, URL: "s3://a", Checksum: "aaa"},
+			"train.csv": {ID: "f2", FileType: models.DatasetFileTypeTrain, URL: "s3://b", Checksum: "bbb"},
+		},
+		Variables: map[string]models.DatasetSnapshotVariable{
+			"count": {ID: "var1", Role: models.DatasetVariableRoleTarget, DataType: models.DatasetDataTypeInteger},
+		},
+	}}
+	to := &models.DatasetVersion{ID: "v2", HackathonID: "h1", Version: 2, Content: &models.DatasetSnapshot{
+		Title:          "Grains",
+		SourceURLs:     []string{},
+		ResponseSchema: json.RawMessage(`{"format":"csv"}`),
+		Files: map[string]models.DatasetSnapshotFile{
+			"test.csv": {ID: "f3", FileType: models.DatasetFileTypeTest, URL: "s3://c", Checksum: "ccc"},
+		},
+		Variables: map[string]models.DatasetSnapshotVariable{
+			"count": {ID: "var1", Role: models.DatasetVariableRoleTarget, DataType: models.DatasetDataTypeInteger},
+			"width": {ID: "var2", Role: models.DatasetVariableRoleFeature, DataType: models.DatasetDataTypeFloat},
+		},
+	}}
+
+	diff, err := diffDatasetVersions(from, to)
+	if err != nil {
+		t.Fatalf("diffDatasetVersions: %v", err)
+	}
+	if diff.FromVersion != 1 || diff.ToVersion != 2 || diff.FromVersionID != "v1" || diff.ToVersionID != "v2" {
+		t.Fatalf("unexpected diff header: %+v", diff)
+	}
+	got := map[string]string{}
+	for _, c := range diff.Changes {
+		got[c.Path] = c.Op
+	}
+	want := map[string]string{
+		"/files/test.csv/checksum": models.JSONChangeReplace,
+		"/files/test.csv/id":       models.JSONChangeReplace,
+		"/files/test.csv/url":      models.JSONChangeReplace,
+		"/files/train.csv":         models.JSONChangeRemove,
+		"/variables/width":         models.JSONChangeAdd,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %+v, want %v", diff.Changes, want)
+	}
+	for path, op := range want {
+		if got[path] != op {
+			t.Fatalf("change at %s = %q, want %q (%+v)", path, got[path], op, diff.Changes)
+		}
+	}
+
+	same, err := diffDatasetVersions(from, from)
+	if err != nil || len(same.Changes) != 0 {
+		t.Fatalf("expected no changes, got %+v (%v)", same, err)
+	}
+}
+
+func TestSubmissionEventPayloadDatasetVersion(t *testing.T) {
+	sub := &models.Submission{ID: "s1", HackathonID: "h1", Status: models.SubmissionStatusCreated}
+	if _, ok := submissionEventPayload(sub)["dataset_version_id"]; ok {
+		t.Fatal("expected no dataset_version_id without a version")
+	}
+	version := "dv1"
+	sub.DatasetVersionID = &version
+	if got := submissionEventPayload(sub)["dataset_version_id"]; got != "dv1" {
+		t.Fatalf("dataset_version_id = %v", got)
+	}
+}
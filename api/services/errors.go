@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 
+	"github.com/DataInCube/hackathon-service/pkg/jsonschema"
 	"github.com/lib/pq"
 )
 
@@ -11,8 +12,24 @@ var (
 	ErrConflict = errors.New("conflict")
 	ErrInvalid  = errors.New("invalid")
 	ErrForbidden = errors.New("forbidden")
+	ErrQuotaExceeded = errors.New("submission quota exceeded")
 )
 
+// FieldErrors reports path-level validation failures. It matches ErrInvalid
+// with errors.Is so callers that only care about the category keep working.
+type FieldErrors struct {
+	Message string
+	Errors  []jsonschema.Error
+}
+
+func (e *FieldErrors) Error() string {
+	return e.Message + ": " + jsonschema.Errors(e.Errors).Error()
+}
+
+func (e *FieldErrors) Unwrap() error {
+	return ErrInvalid
+}
+
 func mapSQLError(err error) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func submissionEventPayload(sub *models.Submission) map[string]any {
+	payload := map[string]any{
+		"submission_id": sub.ID,
+		"hackathon_id":  sub.HackathonID,
+		"status":        sub.Status,
+	}
+	if sub.DatasetVersionID != nil {
+		payload["dataset_version_id"] = *sub.DatasetVersionID
+	}
+	return payload
+}
+
+func evaluationCompletedPayload(sub *models.Submission) map[string]any {
+	metadataMap := metadataToMap(sub.Metadata)
+	secondary := extractSecondaryMetricsFromMetadata(sub.Metadata)
+	updatesLeaderboard := extractBoolFromMetadata(sub.Metadata, true, "updates_leaderboard", "updatesLeaderboard")
+	payload := map[string]any{
+		"submission_id":       sub.ID,
+		"hackathon_id":        sub.HackathonID,
+		"user_id":             sub.SubmittedBy,
+		"source":              "git",
+		"is_official":         true,
+		"is_practice":         false,
+		"updates_leaderboard": updatesLeaderboard,
+		"evaluated_at":        time.Now().UTC().Format(time.RFC3339),
+		"metadata":            metadataMap,
+	}
+	if sub.TeamID != nil && *sub.TeamID != "" {
+		payload["team_id"] = *sub.TeamID
+	}
+	if sub.PublicScore != nil {
+		payload["public_score"] = *sub.PublicScore
+	}
+	if sub.PrivateScore != nil {
+		payload["private_score"] = *sub.PrivateScore
+	}
+	if score, ok := submissionScore(sub); ok {
+		payload["score"] = score
+		scores := map[string]any{
+			"primary":   score,
+			"secondary": secondary,
+		}
+		if sub.CompositeScore != nil {
+			scores["composite"] = *sub.CompositeScore
+		}
+		payload["scores"] = scores
+	}
+	if sub.CompositeScore != nil {
+		payload["composite_score"] = *sub.CompositeScore
+	}
+	if metric := extractStringFromMetadata(sub.Metadata, "primary_metric", "metric"); metric != "" {
+		payload["primary_metric"] = metric
+	}
+	if commit := extractStringFromMetadata(sub.Metadata, "commit_sha", "commit", "git_commit"); commit != "" {
+		payload["commit_sha"] = commit
+	}
+	if boardType := extractStringFromMetadata(sub.Metadata, "board_type", "boardType"); boardType != "" {
+		payload["board_type"] = boardType
+	}
+	if submittedAt := extractStringFromMetadata(sub.Metadata, "submitted_at", "submission_time", "created_at"); submittedAt != "" {
+		payload["submission_time"] = submittedAt
+	}
+	if evaluationJobID := extractStringFromMetadata(sub.Metadata, "evaluation_job_id", "job_id"); evaluationJobID != "" {
+		payload["evaluation_job_id"] = evaluationJobID
+	}
+	if practiceJobID := extractStringFromMetadata(sub.Metadata, "practice_job_id", "last_practice_job_id"); practiceJobID != "" {
+		payload["practice_job_id"] = practiceJobID
+	}
+	return payload
+}
+
+// submissionScore prefers the public score recorded by MarkScored over the
+// score found in metadata.
+func submissionScore(sub *models.Submission) (float64, bool) {
+	if sub.PublicScore != nil {
+		return *sub.PublicScore, true
+	}
+	return extractScoreFromMetadata(sub.Metadata)
+}
+
+func extractScoreFromMetadata(raw json.RawMessage) (float64, bool) {
+	if len(raw) == 0 {
+		return 0, false
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return 0, false
+	}
+	if score, ok := numericFrom(payload["score"]); ok {
+		return score, true
+	}
+	if metrics, ok := payload["metrics"].(map[string]any); ok {
+		if score, ok := numericFrom(metrics["score"]); ok {
+			return score, true
+		}
+		if score, ok := numericFrom(metrics["primary"]); ok {
+			return score, true
+		}
+		if score, ok := numericFrom(metrics["value"]); ok {
+			return score, true
+		}
+	}
+	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
+		if score, ok := numericFrom(evaluation["score"]); ok {
+			return score, true
+		}
+	}
+	return 0, false
+}
+
+func extractStringFromMetadata(raw json.RawMessage, keys ...string) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ""
+	}
+	for _, key := range keys {
+		if v, ok := payload[key]; ok {
+			if s, ok := v.(string); ok {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
+		for _, key := range keys {
+			if v, ok := evaluation[key]; ok {
+				if s, ok := v.(string); ok {
+					return strings.TrimSpace(s)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func extractSecondaryMetricsFromMetadata(raw json.RawMessage) map[string]any {
+	if len(raw) == 0 {
+		return map[string]any{}
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return map[string]any{}
+	}
+	if scores, ok := payload["scores"].(map[string]any); ok {
+		if secondary, ok := scores["secondary"].(map[string]any); ok {
+			return secondary
+		}
+	}
+	for _, key := range []string{"efficiency_metrics", "secondary_metrics", "metrics_secondary"} {
+		if secondary, ok := payload[key].(map[string]any); ok {
+			return secondary
+		}
+	}
+	if evaluation, ok := payload["evaluation"].(map[string]any); ok {
+		if secondary, ok := evaluation["secondary"].(map[string]any); ok {
+			return secondary
+		}
+		if secondary, ok := evaluation["efficiency_metrics"].(map[string]any); ok {
+			return secondary
+		}
+	}
+	return map[string]any{}
+}
+
+func extractBoolFromMetadata(raw json.RawMessage, fallback bool, keys ...string) bool {
+	if len(raw) == 0 {
+		return fallback
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return fallback
+	}
+	for _, key := range keys {
+		value, ok := payload[key]
+		if !ok {
+			continue
+		}
+		switch typed := value.(type) {
+		case bool:
+			return typed
+		case string:
+			lower := strings.ToLower(strings.TrimSpace(typed))
+			if lower == "true" || lower == "1" || lower == "yes" {
+				return true
+			}
+			if lower == "false" || lower == "0" || lower == "no" {
+				return false
+			}
+		}
+	}
+	return fallback
+}
+
+func metadataToMap(raw json.RawMessage) map[string]any {
+	if len(raw) == 0 {
+		return map[string]any{}
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return map[string]any{}
+	}
+	return payload
+}
+
+func numericFrom(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		if err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
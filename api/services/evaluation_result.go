@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/jsonschema"
+)
+
+// RecordEvaluationResult validates a typed score callback against the
+// hackathon's metrics and marks the submission scored. The primary metric
+// value becomes the public score, its private value the private score, and
+// the weighted metrics the composite score.
+func (s *SubmissionService) RecordEvaluationResult(ctx context.Context, id string, result models.EvaluationResult) (*models.Submission, error) {
+	sub, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("submission not found: %w", ErrNotFound)
+	}
+	metrics, err := loadLeaderboardMetrics(ctx, s.DB, sub.HackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateEvaluationResult(result, metrics); err != nil {
+		return nil, err
+	}
+
+	primary := primaryLeaderboardMetric(metrics)
+	metadata, err := evaluationResultMetadata(result, primary)
+	if err != nil {
+		return nil, err
+	}
+	update := EvaluationUpdate{
+		Metadata: &metadata,
+		JobID:    result.JobID,
+		Executor: result.Executor,
+	}
+	if composite, ok := compositeScore(result.Metrics, metrics); ok {
+		update.CompositeScore = &composite
+	}
+	if primary != nil {
+		if v, ok := result.Metrics[primary.Name]; ok {
+			update.PublicScore = &v
+		}
+		if v, ok := result.PrivateMetrics[primary.Name]; ok {
+			update.PrivateScore = &v
+		}
+	}
+	return s.UpdateEvaluationStatus(ctx, id, models.SubmissionStatusScored, update)
+}
+
+// validateEvaluationResult checks the callback version and metric values.
+// Primary, per-target and weighted metrics are required, since the composite
+// score needs every weighted value; names must match configured metrics, and
+// every value must be finite. Without configured metrics any names are
+// accepted.
+func validateEvaluationResult(result models.EvaluationResult, metrics []models.EvaluationMetric) error {
+	var errs []jsonschema.Error
+	if result.Version != models.EvaluationResultVersion {
+		errs = append(errs, jsonschema.Error{
+			Path:    "/version",
+			Message: fmt.Sprintf("unsupported version %q, expected %q", result.Version, models.EvaluationResultVersion),
+		})
+	}
+	if len(result.Metrics) == 0 {
+		errs = append(errs, jsonschema.Error{Path: "/metrics", Message: "at least one metric value is required"})
+	}
+
+	known := make(map[string]bool, len(metrics))
+	for _, m := range metrics {
+		known[m.Name] = true
+		if !m.IsPrimary && m.Scope != models.MetricScopePerTarget && m.Weight <= 0 {
+			continue
+		}
+		if _, ok := result.Metrics[m.Name]; !ok {
+			errs = append(errs, jsonschema.Error{Path: "/metrics/" + escapeJSONPointer(m.Name), Message: "required metric is missing"})
+		}
+		if m.IsPrimary && len(result.PrivateMetrics) > 0 {
+			if _, ok := result.PrivateMetrics[m.Name]; !ok {
+				errs = append(errs, jsonschema.Error{Path: "/private_metrics/" + escapeJSONPointer(m.Name), Message: "primary metric is missing"})
+			}
+		}
+	}
+
+	for _, block := range []struct {
+		path   string
+		values map[string]float64
+	}{{"/metrics/", result.Metrics}, {"/private_metrics/", result.PrivateMetrics}} {
+		names := make([]string, 0, len(block.values))
+		for name := range block.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := block.path + escapeJSONPointer(name)
+			if len(metrics) > 0 && !known[name] {
+				errs = append(errs, jsonschema.Error{Path: path, Message: "unknown metric"})
+				continue
+			}
+			if v := block.values[name]; math.IsNaN(v) || math.IsInf(v, 0) {
+				errs = append(errs, jsonschema.Error{Path: path, Message: "value must be a finite number"})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &FieldErrors{Message: "evaluation result does not match the hackathon metrics", Errors: errs}
+	}
+	return nil
+}
+
+// evaluationResultMetadata builds the metadata patch stored on the submission,
+// using the keys the leaderboard and evaluation.completed event read first.
+func evaluationResultMetadata(result models.EvaluationResult, primary *models.EvaluationMetric) (json.RawMessage, error) {
+	patch := map[string]any{
+		"evaluation_result_version": result.Version,
+		"metrics":                   result.Metrics,
+	}
+	if len(result.PrivateMetrics) > 0 {
+		patch["private_metrics"] = result.PrivateMetrics
+	}
+	if primary != nil {
+		patch["primary_metric"] = primary.Name
+	}
+	if v := strings.TrimSpace(result.JobID); v != "" {
+		patch["evaluation_job_id"] = v
+	}
+	if v := strings.TrimSpace(result.CommitSHA); v != "" {
+		patch["commit_sha"] = v
+	}
+	if result.UpdatesLeaderboard != nil {
+		patch["updates_leaderboard"] = *result.UpdatesLeaderboard
+	}
+	if len(result.Details) > 0 {
+		patch["details"] = result.Details
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evaluation result: %w", ErrInvalid)
+	}
+	return raw, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestValidateEvaluationResult(t *testing.T) {
+	metrics := []models.EvaluationMetric{
+		{Name: "rmse", IsPrimary: true, Scope: models.MetricScopeOverall},
+		{Name: "rmse_price", Scope: models.MetricScopePerTarget, TargetVariable: "price"},
+		{Name: "latency", Scope: models.MetricScopeOverall},
+		{Name: "mae", Scope: models.MetricScopeOverall, Weight: 0.5},
+	}
+
+	valid := models.EvaluationResult{
+		Version: models.EvaluationResultVersion,
+		Metrics: map[string]float64{"rmse": 0.4, "rmse_price": 0.5, "mae": 0.3},
+	}
+	if err := validateEvaluationResult(valid, metrics); err != nil {
+		t.Fatalf("expected valid result, got %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		result models.EvaluationResult
+		path   string
+	}{
+		{"wrong version", models.EvaluationResult{Version: "v0", Metrics: valid.Metrics}, "/version"},
+		{"missing primary", models.EvaluationResult{Version: "v1", Metrics: map[string]float64{"rmse_price": 0.5}}, "/metrics/rmse"},
+		{"missing per target", models.EvaluationResult{Version: "v1", Metrics: map[string]float64{"rmse": 0.5, "mae": 0.3}}, "/metrics/rmse_price"},
+		{"missing weighted secondary", models.EvaluationResult{Version: "v1", Metrics: map[string]float64{"rmse": 0.5, "rmse_price": 0.5}}, "/metrics/mae"},
+		{"unknown metric", models.EvaluationResult{Version: "v1", Metrics: map[string]float64{"rmse": 1, "rmse_price": 1, "mae": 1, "f1": 1}}, "/metrics/f1"},
+		{"not finite", models.EvaluationResult{Version: "v1", Metrics: map[string]float64{"rmse": math.Inf(1), "rmse_price": 1, "mae": 1}}, "/metrics/rmse"},
+		{"private without primary", models.EvaluationResult{Version: "v1", Metrics: valid.Metrics, PrivateMetrics: map[string]float64{"latency": 3}}, "/private_metrics/rmse"},
+	}
+	for _, tc := range cases {
+		err := validateEvaluationResult(tc.result, metrics)
+		var fieldErrs *FieldErrors
+		if !errors.As(err, &fieldErrs) || !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%s: expected FieldErrors, got %v", tc.name, err)
+		}
+		if fieldErrs.Errors[0].Path != tc.path {
+			t.Fatalf("%s: expected path %s, got %+v", tc.name, tc.path, fieldErrs.Errors)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+// EvaluationTimeoutDefaults apply to hackathons whose submission limits leave
+// evaluation_timeout_seconds or max_evaluation_attempts at 0.
+type EvaluationTimeoutDefaults struct {
+	Timeout     time.Duration
+	MaxAttempts int
+	BatchSize   int
+}
+
+// EvaluationTimeoutSweep reports one watchdog pass. Stuck counts every stale
+// submission per status before the pass, not only the batch it handled;
+// Errors counts the submissions that could not be timed out.
+type EvaluationTimeoutSweep struct {
+	Stuck    map[string]int
+	Requeued int
+	Failed   int
+	Errors   int
+}
+
+type staleEvaluation struct {
+	id          string
+	status      string
+	timeout     time.Duration
+	maxAttempts int
+}
+
+// TimeOutStaleEvaluations closes evaluations that have been queued or running
+// for longer than their hackathon's timeout. A timed-out running submission is
+// requeued while it has attempts left and marked evaluation_failed otherwise;
+// a submission that never left the queue is marked evaluation_failed. A
+// submission that cannot be handled does not stop the pass: the returned
+// error joins the failures of every submission.
+func (s *SubmissionService) TimeOutStaleEvaluations(ctx context.Context, now time.Time, defaults EvaluationTimeoutDefaults, actorID string) (*EvaluationTimeoutSweep, error) {
+	if defaults.Timeout <= 0 {
+		return nil, fmt.Errorf("evaluation timeout must be positive: %w", ErrInvalid)
+	}
+	if defaults.MaxAttempts <= 0 {
+		defaults.MaxAttempts = 1
+	}
+	if defaults.BatchSize <= 0 {
+		defaults.BatchSize = 100
+	}
+
+	stuck, err := s.countStaleEvaluations(ctx, now, defaults)
+	if err != nil {
+		return nil, err
+	}
+	sweep := &EvaluationTimeoutSweep{Stuck: stuck}
+	stale, err := s.staleEvaluations(ctx, now, defaults)
+	if err != nil {
+		return sweep, err
+	}
+	var errs []error
+	for _, st := range stale {
+		requeued, handled, err := s.timeOutEvaluation(ctx, st, now, actorID)
+		if err != nil {
+			sweep.Errors++
+			errs = append(errs, fmt.Errorf("submission %s: %w", st.id, err))
+			continue
+		}
+		switch {
+		case !handled:
+		case requeued:
+			sweep.Requeued++
+		default:
+			sweep.Failed++
+		}
+	}
+	return sweep, errors.Join(errs...)
+}
+
+// staleEvaluationFilter selects the submissions of s (joined with their
+// submission limits as l) that are past their timeout at $3.
+const staleEvaluationFilter = `s.status IN ($1, $2)
+		  AND s.updated_at < $3::timestamptz - make_interval(secs => COALESCE(NULLIF(l.evaluation_timeout_seconds, 0), $4))`
+
+func (s *SubmissionService) countStaleEvaluations(ctx context.Context, now time.Time, defaults EvaluationTimeoutDefaults) (map[string]int, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT s.status, COUNT(*)
+		FROM submissions s
+		LEFT JOIN submission_limits l ON l.hackathon_id = s.hackathon_id
+		WHERE `+staleEvaluationFilter+`
+		GROUP BY s.status`,
+		models.SubmissionStatusQueuedForEval, models.SubmissionStatusEvaluationRunning,
+		now, int(defaults.Timeout/time.Second))
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	stuck := map[string]int{
+		models.SubmissionStatusQueuedForEval:     0,
+		models.SubmissionStatusEvaluationRunning: 0,
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, mapSQLError(err)
+		}
+		stuck[status] = count
+	}
+	return stuck, rows.Err()
+}
+
+func (s *SubmissionService) staleEvaluations(ctx context.Context, now time.Time, defaults EvaluationTimeoutDefaults) ([]staleEvaluation, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT s.id, s.status,
+		       COALESCE(NULLIF(l.evaluation_timeout_seconds, 0), $4),
+		       COALESCE(NULLIF(l.max_evaluation_attempts, 0), $5)
+		FROM submissions s
+		LEFT JOIN submission_limits l ON l.hackathon_id = s.hackathon_id
+		WHERE `+staleEvaluationFilter+`
+		ORDER BY s.updated_at
+		LIMIT $6`,
+		models.SubmissionStatusQueuedForEval, models.SubmissionStatusEvaluationRunning,
+		now, int(defaults.Timeout/time.Second), defaults.MaxAttempts, defaults.BatchSize)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	var out []staleEvaluation
+	for rows.Next() {
+		var st staleEvaluation
+		var timeoutSeconds int
+		if err := rows.Scan(&st.id, &st.status, &timeoutSeconds, &st.maxAttempts); err != nil {
+			return nil, mapSQLError(err)
+		}
+		st.timeout = time.Duration(timeoutSeconds) * time.Second
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
+
+// timeOutEvaluation handles one stale submission in its own transaction. It
+// reports handled=false when the submission moved on since it was selected.
+func (s *SubmissionService) timeOutEvaluation(ctx context.Context, st staleEvaluation, now time.Time, actorID string) (requeued, handled bool, err error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, false, err
+	}
+	defer tx.Rollback()
+
+	sub, err := loadSubmission(ctx, tx, st.id, true)
+	if err != nil {
+		return false, false, err
+	}
+	if sub == nil || sub.Status != st.status || !sub.UpdatedAt.Before(now.Add(-st.timeout)) {
+		return false, false, nil
+	}
+
+	if !isSubmissionTransitionAllowed(sub.Status, models.SubmissionStatusEvaluationFailed) {
+		return false, false, fmt.Errorf("cannot time out a submission in status %s: %w", sub.Status, ErrInvalid)
+	}
+	// Only a running submission has an open attempt to close; one that never
+	// left the queue gets no attempt row and is not requeued.
+	running := sub.Status == models.SubmissionStatusEvaluationRunning
+	if running {
+		msg := fmt.Sprintf("evaluation timed out after %s in %s", st.timeout, sub.Status)
+		if err := recordEvaluationAttempt(ctx, tx, sub, evaluationAttempt{
+			Status:   models.SubmissionStatusEvaluationFailed,
+			Error:    msg,
+			Metadata: json.RawMessage(`{}`),
+		}); err != nil {
+			return false, false, err
+		}
+	}
+	var attempts int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM submission_evaluations
+		WHERE submission_id = $1 AND superseded_at IS NULL`, sub.ID).Scan(&attempts); err != nil {
+		return false, false, mapSQLError(err)
+	}
+
+	target := models.SubmissionStatusEvaluationFailed
+	if running && attempts < st.maxAttempts &&
+		isSubmissionTransitionAllowed(models.SubmissionStatusEvaluationFailed, models.SubmissionStatusQueuedForEval) {
+		target = models.SubmissionStatusQueuedForEval
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE submissions SET status = $1, updated_at = $2 WHERE id = $3`, target, now, sub.ID); err != nil {
+		return false, false, mapSQLError(err)
+	}
+
+	previous := sub.Status
+	sub.Status = target
+	sub.UpdatedAt = now
+	payload := submissionEventPayload(sub)
+	payload["previous_status"] = previous
+	payload["attempts"] = attempts
+	payload["max_attempts"] = st.maxAttempts
+	payload["timeout_seconds"] = int(st.timeout / time.Second)
+	payload["requeued"] = target == models.SubmissionStatusQueuedForEval
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return false, false, err
+	}
+	if err := insertAuditLog(ctx, tx, models.AuditLog{
+		HackathonID: sub.HackathonID,
+		ActorID:     actorID,
+		Action:      "submission.evaluation.timed_out",
+		Payload:     raw,
+	}); err != nil {
+		return false, false, err
+	}
+	evts := []domainEvent{{"submission.evaluation.timed_out", payload}}
+	if target == models.SubmissionStatusQueuedForEval {
+		rescore := submissionEventPayload(sub)
+		rescore["reason"] = "evaluation_timeout"
+		evts = append(evts, domainEvent{"submission.rescore.requested", rescore})
+	}
+	if err := publishInTx(ctx, tx, s.Events, evts...); err != nil {
+		return false, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, false, err
+	}
+	return target == models.SubmissionStatusQueuedForEval, true, nil
+}
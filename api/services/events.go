@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DataInCube/hackathon-service/pkg/events"
+)
+
+type domainEvent struct {
+	subject string
+	payload any
+}
+
+// publishInTx records events in the same transaction as the domain change when
+// the publisher supports it (the outbox). Other publishers are best-effort.
+func publishInTx(ctx context.Context, tx *sql.Tx, publisher events.Publisher, evts ...domainEvent) error {
+	if publisher == nil {
+		return nil
+	}
+	txPublisher, ok := publisher.(events.TxPublisher)
+	for _, evt := range evts {
+		if !ok {
+			_ = publisher.Publish(ctx, evt.subject, evt.payload)
+			continue
+		}
+		if err := txPublisher.PublishTx(ctx, tx, evt.subject, evt.payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execWithEvents runs a single-statement write in a transaction together with
+// its events, so it reaches the outbox only if the write commits.
+func execWithEvents(ctx context.Context, db *sql.DB, publisher events.Publisher, write func(tx *sql.Tx) error, evts ...domainEvent) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := publishInTx(ctx, tx, publisher, evts...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
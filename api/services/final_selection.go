@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+// SelectFinal marks a submission as one of its participant's final
+// submissions for private scoring. Selection closes at submission_frozen.
+func (s *SubmissionService) SelectFinal(ctx context.Context, id string) (*models.Submission, error) {
+	return s.setFinalSelection(ctx, id, true)
+}
+
+func (s *SubmissionService) UnselectFinal(ctx context.Context, id string) (*models.Submission, error) {
+	return s.setFinalSelection(ctx, id, false)
+}
+
+func (s *SubmissionService) setFinalSelection(ctx context.Context, id string, selected bool) (*models.Submission, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sub, err := loadSubmission(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("submission not found: %w", ErrNotFound)
+	}
+	// FOR SHARE orders this selection against a concurrent transition to
+	// submission_frozen, which runs the automatic selection.
+	var state string
+	if err := tx.QueryRowContext(ctx, `SELECT state FROM hackathons WHERE id = $1 FOR SHARE`, sub.HackathonID).Scan(&state); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if state != models.HackathonStateLive {
+		return nil, fmt.Errorf("final selection is closed in state %s: %w", state, ErrInvalid)
+	}
+	maxFinal, err := loadMaxFinalSelections(ctx, tx, sub.HackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if maxFinal == 0 {
+		return nil, fmt.Errorf("final selection is not enabled for this hackathon: %w", ErrInvalid)
+	}
+	if selected == (sub.FinalSelection != "") {
+		return sub, nil
+	}
+
+	var subject string
+	if selected {
+		if sub.Status == models.SubmissionStatusInvalidated || sub.Status == models.SubmissionStatusEvaluationFailed {
+			return nil, fmt.Errorf("%s submissions cannot be selected: %w", sub.Status, ErrInvalid)
+		}
+		participantKey := leaderboardParticipantKey(sub.SubmittedBy, sub.TeamID)
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "final:"+sub.HackathonID+":"+participantKey); err != nil {
+			return nil, mapSQLError(err)
+		}
+		count, err := countFinalSelections(ctx, tx, sub.HackathonID, sub.SubmittedBy, sub.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= maxFinal {
+			return nil, fmt.Errorf("already selected %d final submissions: %w", maxFinal, ErrConflict)
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE submissions SET final_selection = $1, final_selected_at = NOW(), updated_at = NOW()
+			WHERE id = $2`, models.FinalSelectionManual, id)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		subject = "submission.final.selected"
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE submissions SET final_selection = NULL, final_selected_at = NULL, updated_at = NOW()
+			WHERE id = $1`, id)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		subject = "submission.final.unselected"
+	}
+
+	if err := refreshLeaderboard(ctx, tx, sub.HackathonID); err != nil {
+		return nil, err
+	}
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{subject, submissionEventPayload(sub)}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// FinalSelections returns the final submissions of the participant identified
+// by userID, or by teamID when set.
+func (s *SubmissionService) FinalSelections(ctx context.Context, hackathonID, userID string, teamID *string) (*models.FinalSelections, error) {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	maxFinal, err := loadMaxFinalSelections(ctx, s.DB, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+submissionColumns+`
+		FROM submissions
+		WHERE hackathon_id = $1 AND final_selection IS NOT NULL AND `+participantFilter+`
+		ORDER BY final_selected_at`, hackathonID, userID, teamIDValue(teamID))
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	out := models.FinalSelections{
+		HackathonID:        hackathonID,
+		ParticipantKey:     leaderboardParticipantKey(userID, teamID),
+		MaxFinalSelections: maxFinal,
+		Submissions:        []models.Submission{},
+	}
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		out.Submissions = append(out.Submissions, *sub)
+	}
+	if out.Remaining = maxFinal - len(out.Submissions); out.Remaining < 0 {
+		out.Remaining = 0
+	}
+	return &out, nil
+}
+
+// PrivateScoresVisible reports whether private scores may be shown to
+// participants, which happens once the leaderboard is published.
+func (s *SubmissionService) PrivateScoresVisible(ctx context.Context, hackathonID string) (bool, error) {
+	var published bool
+	err := s.DB.QueryRowContext(ctx, `SELECT leaderboard_published FROM hackathons WHERE id = $1`, hackathonID).Scan(&published)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return false, mapSQLError(err)
+	}
+	return published, nil
+}
+
+// participantFilter matches the submissions of one participant: the team when
+// $3 is set, otherwise the user's own submissions without a team.
+const participantFilter = `(
+			($3 <> '' AND team_id = $3) OR
+			($3 = '' AND COALESCE(team_id, '') = '' AND submitted_by = $2)
+		)`
+
+func countFinalSelections(ctx context.Context, q rowQuerier, hackathonID, userID string, teamID *string) (int, error) {
+	var count int
+	err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM submissions
+		WHERE hackathon_id = $1 AND final_selection IS NOT NULL AND `+participantFilter,
+		hackathonID, userID, teamIDValue(teamID)).Scan(&count)
+	if err != nil {
+		return 0, mapSQLError(err)
+	}
+	return count, nil
+}
+
+func teamIDValue(teamID *string) string {
+	if teamID == nil {
+		return ""
+	}
+	return *teamID
+}
+
+func loadMaxFinalSelections(ctx context.Context, q rowQuerier, hackathonID string) (int, error) {
+	var maxFinal int
+	err := q.QueryRowContext(ctx, `SELECT max_final_selections FROM submission_limits WHERE hackathon_id = $1`, hackathonID).Scan(&maxFinal)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, mapSQLError(err)
+	}
+	return maxFinal, nil
+}
+
+// autoSelectFinalSubmissions picks the best public-scored submissions for
+// participants that made no selection. It runs inside the transition to
+// submission_frozen and returns the number of submissions selected.
+func autoSelectFinalSubmissions(ctx context.Context, tx *sql.Tx, hackathonID string) (int, error) {
+	maxFinal, err := loadMaxFinalSelections(ctx, tx, hackathonID)
+	if err != nil || maxFinal == 0 {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT CASE WHEN COALESCE(team_id, '') <> '' THEN 'team:' || team_id ELSE 'user:' || submitted_by END
+		FROM submissions
+		WHERE hackathon_id = $1 AND final_selection IS NOT NULL`, hackathonID)
+	if err != nil {
+		return 0, mapSQLError(err)
+	}
+	chosen := map[string]bool{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return 0, mapSQLError(err)
+		}
+		chosen[key] = true
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+
+	metrics, err := loadLeaderboardMetrics(ctx, tx, hackathonID)
+	if err != nil {
+		return 0, err
+	}
+	subs, err := loadScoredSubmissions(ctx, tx, hackathonID)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := pickAutoFinalSelections(subs, chosen, metrics, maxFinal)
+	now := time.Now().UTC()
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE submissions SET final_selection = $1, final_selected_at = $2, updated_at = $2
+			WHERE id = $3`, models.FinalSelectionAuto, now, id); err != nil {
+			return 0, mapSQLError(err)
+		}
+	}
+	if len(ids) > 0 {
+		if err := refreshLeaderboard(ctx, tx, hackathonID); err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+// pickAutoFinalSelections returns, for every participant not in chosen, the
+// ids of its maxFinal best submissions on the public board, ranked the same
+// way as the leaderboard.
+func pickAutoFinalSelections(subs []leaderboardSubmission, chosen map[string]bool, metrics []models.EvaluationMetric, maxFinal int) []string {
+	byParticipant := map[string][]*leaderboardCandidate{}
+	for _, c := range leaderboardCandidates(models.LeaderboardBoardPublic, subs, metrics) {
+		if chosen[c.entry.ParticipantKey] {
+			continue
+		}
+		byParticipant[c.entry.ParticipantKey] = append(byParticipant[c.entry.ParticipantKey], c)
+	}
+
+	keys := make([]string, 0, len(byParticipant))
+	for key := range byParticipant {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var ids []string
+	for _, key := range keys {
+		candidates := byParticipant[key]
+		sort.Slice(candidates, func(i, j int) bool { return leaderboardBetter(candidates[i], candidates[j]) })
+		for i := 0; i < len(candidates) && i < maxFinal; i++ {
+			ids = append(ids, candidates[i].entry.SubmissionID)
+		}
+	}
+	return ids
+}
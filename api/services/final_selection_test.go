@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestPickAutoFinalSelections(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	score := func(v float64) *float64 { return &v }
+	team := "team-a"
+	subs := []leaderboardSubmission{
+		{ID: "u1-a", UserID: "u1", PublicScore: score(0.4), ScoredAt: base},
+		{ID: "u1-b", UserID: "u1", PublicScore: score(0.9), ScoredAt: base},
+		{ID: "u1-c", UserID: "u1", PublicScore: score(0.7), ScoredAt: base},
+		{ID: "t-a", UserID: "u2", TeamID: &team, PublicScore: score(0.5), ScoredAt: base},
+		{ID: "u3-a", UserID: "u3", PublicScore: score(0.99), ScoredAt: base},
+		{ID: "u4-a", UserID: "u4", Metadata: json.RawMessage(`{}`), ScoredAt: base},
+	}
+	chosen := map[string]bool{"user:u3": true}
+
+	got := pickAutoFinalSelections(subs, chosen, nil, 2)
+	want := []string{"t-a", "u1-b", "u1-c"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestPickAutoFinalSelections_Composite(t *testing.T) {
+	subs := []leaderboardSubmission{
+		{ID: "fast", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.8, "metrics": {"accuracy": 0.8, "latency": 10}}`)},
+		{ID: "slow", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.9, "metrics": {"accuracy": 0.9, "latency": 100}}`)},
+		{ID: "mid", UserID: "u2", Metadata: json.RawMessage(`{"score": 0.85, "metrics": {"accuracy": 0.85, "latency": 55}}`)},
+	}
+	metrics := []models.EvaluationMetric{
+		{ID: "m1", Name: "accuracy", MetricType: "accuracy", Direction: models.MetricDirectionMaximize, IsPrimary: true, Weight: 1},
+		{ID: "m2", Name: "latency", MetricType: "custom", Direction: models.MetricDirectionMinimize, Weight: 3},
+	}
+
+	got := pickAutoFinalSelections(subs, map[string]bool{}, metrics, 1)
+	if len(got) != 2 || got[0] != "fast" || got[1] != "mid" {
+		t.Fatalf("expected the best composite per participant, got %v", got)
+	}
+}
+
+func TestRankLeaderboard_UsesRecordedScores(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	subs := []leaderboardSubmission{
+		{ID: "s1", UserID: "u1", PublicScore: score(0.8), PrivateScore: score(0.6), Metadata: json.RawMessage(`{"score": 0.1}`)},
+		{ID: "s2", UserID: "u2", PublicScore: score(0.7), PrivateScore: score(0.9)},
+	}
+	public := rankLeaderboard(models.LeaderboardBoardPublic, subs, nil)
+	if public[0].UserID != "u1" || public[0].Score != 0.8 {
+		t.Fatalf("unexpected public board: %+v", public)
+	}
+	private := rankLeaderboard(models.LeaderboardBoardPrivate, finalSubmissions(subs), nil)
+	if len(private) != 0 {
+		t.Fatalf("expected no private entries without final selections, got %+v", private)
+	}
+	subs[0].FinalSelection = models.FinalSelectionManual
+	subs[1].FinalSelection = models.FinalSelectionAuto
+	private = rankLeaderboard(models.LeaderboardBoardPrivate, finalSubmissions(subs), nil)
+	if len(private) != 2 || private[0].UserID != "u2" {
+		t.Fatalf("unexpected private board: %+v", private)
+	}
+}
+
+func TestValidateScore(t *testing.T) {
+	ok := 0.5
+	if err := validateScore("public_score", &ok); err != nil {
+		t.Fatalf("expected valid score, got %v", err)
+	}
+	if err := validateScore("public_score", nil); err != nil {
+		t.Fatalf("expected nil score to be valid, got %v", err)
+	}
+	nan := math.NaN()
+	if err := validateScore("private_score", &nan); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for NaN, got %v", err)
+	}
+}
@@ -0,0 +1,397 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+type GovernanceAssignInput struct {
+	AssigneeID string `json:"assignee_id"`
+}
+
+type GovernanceTransitionInput struct {
+	Status          string `json:"status"`
+	ResolutionNotes string `json:"resolution_notes,omitempty"`
+	// Action is only used when upholding an appeal: invalidate or reevaluate
+	// the appealed submission.
+	Action string `json:"action,omitempty"`
+}
+
+type GovernanceFilter struct {
+	Status     string
+	AssigneeID string
+}
+
+func (s *GovernanceService) ListReports(ctx context.Context, hackathonID string, filter GovernanceFilter, page PageRequest) (*models.Page[models.Report], error) {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	return queryPage(ctx, s.DB, keyset{columns: reportColumns, from: "reports", at: "created_at"}, governanceListQuery(hackathonID, filter), page,
+		scanReport, func(r *models.Report) (time.Time, string) { return r.CreatedAt, r.ID })
+}
+
+func (s *GovernanceService) GetReport(ctx context.Context, id string) (*models.Report, error) {
+	return loadReport(ctx, s.DB, id, false)
+}
+
+func (s *GovernanceService) AssignReport(ctx context.Context, id string, input GovernanceAssignInput, actorID string) (*models.Report, error) {
+	assignee := strings.TrimSpace(input.AssigneeID)
+	if assignee == "" {
+		return nil, fmt.Errorf("assignee_id is required: %w", ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	r, err := loadReport(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("report not found: %w", ErrNotFound)
+	}
+	if isGovernanceResolved(r.Status) {
+		return nil, fmt.Errorf("report already %s: %w", r.Status, ErrInvalid)
+	}
+
+	r.AssigneeID = assignee
+	r.UpdatedAt = time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE reports SET assignee_id = $1, updated_at = $2 WHERE id = $3`, r.AssigneeID, r.UpdatedAt, id); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := s.recordReportStep(ctx, tx, r, actorID, "governance.report.assigned"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TransitionReport moves a report through the review state machine. Only the
+// reporter may withdraw without being privileged.
+func (s *GovernanceService) TransitionReport(ctx context.Context, id string, input GovernanceTransitionInput, actorID string, privileged bool) (*models.Report, error) {
+	if input.Action != "" {
+		return nil, fmt.Errorf("action is only supported for appeals: %w", ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	r, err := loadReport(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("report not found: %w", ErrNotFound)
+	}
+	if err := checkGovernanceTransition(r.Status, input, actorID, r.ReporterID, privileged); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	applyGovernanceTransition(&r.Status, &r.AssigneeID, &r.ResolutionNotes, &r.ResolvedAt, input, actorID, now)
+	r.UpdatedAt = now
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE reports
+		SET status = $1, assignee_id = NULLIF($2, ''), resolution_notes = $3, resolved_at = $4, updated_at = $5
+		WHERE id = $6`, r.Status, r.AssigneeID, r.ResolutionNotes, r.ResolvedAt, r.UpdatedAt, id); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := s.recordReportStep(ctx, tx, r, actorID, "governance.report."+r.Status); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *GovernanceService) ListAppeals(ctx context.Context, hackathonID string, filter GovernanceFilter, page PageRequest) (*models.Page[models.Appeal], error) {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	return queryPage(ctx, s.DB, keyset{columns: appealColumns, from: "appeals", at: "created_at"}, governanceListQuery(hackathonID, filter), page,
+		scanAppeal, func(a *models.Appeal) (time.Time, string) { return a.CreatedAt, a.ID })
+}
+
+func governanceListQuery(hackathonID string, filter GovernanceFilter) listQuery {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	if filter.Status != "" {
+		q.where("status = %s", filter.Status)
+	}
+	if filter.AssigneeID != "" {
+		q.where("assignee_id = %s", filter.AssigneeID)
+	}
+	return q
+}
+
+func (s *GovernanceService) GetAppeal(ctx context.Context, id string) (*models.Appeal, error) {
+	return loadAppeal(ctx, s.DB, id, false)
+}
+
+func (s *GovernanceService) AssignAppeal(ctx context.Context, id string, input GovernanceAssignInput, actorID string) (*models.Appeal, error) {
+	assignee := strings.TrimSpace(input.AssigneeID)
+	if assignee == "" {
+		return nil, fmt.Errorf("assignee_id is required: %w", ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	a, err := loadAppeal(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("appeal not found: %w", ErrNotFound)
+	}
+	if isGovernanceResolved(a.Status) {
+		return nil, fmt.Errorf("appeal already %s: %w", a.Status, ErrInvalid)
+	}
+
+	a.AssigneeID = assignee
+	a.UpdatedAt = time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE appeals SET assignee_id = $1, updated_at = $2 WHERE id = $3`, a.AssigneeID, a.UpdatedAt, id); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := s.recordAppealStep(ctx, tx, a, actorID, "governance.appeal.assigned"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// TransitionAppeal moves an appeal through the review state machine. Upholding
+// an appeal can invalidate or re-evaluate the appealed submission in the same
+// transaction.
+func (s *GovernanceService) TransitionAppeal(ctx context.Context, id string, input GovernanceTransitionInput, actorID string, privileged bool) (*models.Appeal, error) {
+	switch input.Action {
+	case "", models.AppealActionInvalidate, models.AppealActionReevaluate:
+	default:
+		return nil, fmt.Errorf("action must be invalidate or reevaluate: %w", ErrInvalid)
+	}
+	if input.Action != "" && input.Status != models.GovernanceStatusUpheld {
+		return nil, fmt.Errorf("action requires status upheld: %w", ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	a, err := loadAppeal(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, fmt.Errorf("appeal not found: %w", ErrNotFound)
+	}
+	if err := checkGovernanceTransition(a.Status, input, actorID, a.AppellantID, privileged); err != nil {
+		return nil, err
+	}
+
+	switch input.Action {
+	case models.AppealActionInvalidate:
+		if _, err := invalidateSubmission(ctx, tx, s.Events, a.SubmissionID); err != nil {
+			return nil, err
+		}
+		a.Resolution = "submission_invalidated"
+	case models.AppealActionReevaluate:
+		if _, err := requeueSubmission(ctx, tx, s.Events, a.SubmissionID, "appeal:"+a.ID, actorID); err != nil {
+			return nil, err
+		}
+		a.Resolution = "submission_requeued"
+	}
+
+	now := time.Now().UTC()
+	applyGovernanceTransition(&a.Status, &a.AssigneeID, &a.ResolutionNotes, &a.ResolvedAt, input, actorID, now)
+	a.UpdatedAt = now
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE appeals
+		SET status = $1, assignee_id = NULLIF($2, ''), resolution_notes = $3, resolution = $4, resolved_at = $5, updated_at = $6
+		WHERE id = $7`, a.Status, a.AssigneeID, a.ResolutionNotes, a.Resolution, a.ResolvedAt, a.UpdatedAt, id); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := s.recordAppealStep(ctx, tx, a, actorID, "governance.appeal."+a.Status); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func isGovernanceTransitionAllowed(current, target string) bool {
+	allowed := map[string][]string{
+		models.GovernanceStatusOpen:        {models.GovernanceStatusUnderReview, models.GovernanceStatusWithdrawn},
+		models.GovernanceStatusUnderReview: {models.GovernanceStatusUpheld, models.GovernanceStatusRejected, models.GovernanceStatusWithdrawn},
+	}
+	for _, next := range allowed[current] {
+		if next == target {
+			return true
+		}
+	}
+	return false
+}
+
+func isGovernanceResolved(status string) bool {
+	return status == models.GovernanceStatusUpheld || status == models.GovernanceStatusRejected || status == models.GovernanceStatusWithdrawn
+}
+
+// checkGovernanceTransition guards a review step. Reviewers must be
+// privileged; the author may only withdraw.
+func checkGovernanceTransition(current string, input GovernanceTransitionInput, actorID, authorID string, privileged bool) error {
+	if current == input.Status {
+		return fmt.Errorf("already %s: %w", current, ErrConflict)
+	}
+	if !isGovernanceTransitionAllowed(current, input.Status) {
+		return fmt.Errorf("transition not allowed from %s to %s: %w", current, input.Status, ErrInvalid)
+	}
+	if !privileged && (input.Status != models.GovernanceStatusWithdrawn || actorID == "" || actorID != authorID) {
+		return fmt.Errorf("only reviewers can move to %s: %w", input.Status, ErrForbidden)
+	}
+	if (input.Status == models.GovernanceStatusUpheld || input.Status == models.GovernanceStatusRejected) && strings.TrimSpace(input.ResolutionNotes) == "" {
+		return fmt.Errorf("resolution_notes are required to resolve: %w", ErrInvalid)
+	}
+	return nil
+}
+
+// applyGovernanceTransition updates the shared review fields. Starting a
+// review without an assignee assigns the acting reviewer.
+func applyGovernanceTransition(status, assigneeID, notes *string, resolvedAt **time.Time, input GovernanceTransitionInput, actorID string, now time.Time) {
+	*status = input.Status
+	if input.Status == models.GovernanceStatusUnderReview && *assigneeID == "" {
+		*assigneeID = actorID
+	}
+	if n := strings.TrimSpace(input.ResolutionNotes); n != "" {
+		*notes = n
+	}
+	if isGovernanceResolved(input.Status) {
+		*resolvedAt = &now
+	}
+}
+
+func (s *GovernanceService) recordReportStep(ctx context.Context, tx *sql.Tx, r *models.Report, actorID, subject string) error {
+	payload := map[string]any{
+		"report_id":    r.ID,
+		"hackathon_id": r.HackathonID,
+		"status":       r.Status,
+		"assignee_id":  r.AssigneeID,
+		"actor_id":     actorID,
+	}
+	return s.recordGovernanceStep(ctx, tx, r.HackathonID, actorID, subject, payload)
+}
+
+func (s *GovernanceService) recordAppealStep(ctx context.Context, tx *sql.Tx, a *models.Appeal, actorID, subject string) error {
+	payload := map[string]any{
+		"appeal_id":     a.ID,
+		"hackathon_id":  a.HackathonID,
+		"submission_id": a.SubmissionID,
+		"status":        a.Status,
+		"assignee_id":   a.AssigneeID,
+		"actor_id":      actorID,
+	}
+	if a.Resolution != "" {
+		payload["resolution"] = a.Resolution
+	}
+	return s.recordGovernanceStep(ctx, tx, a.HackathonID, actorID, subject, payload)
+}
+
+// recordGovernanceStep writes the audit entry and the domain event of a review
+// step in the same transaction as the change.
+func (s *GovernanceService) recordGovernanceStep(ctx context.Context, tx *sql.Tx, hackathonID, actorID, subject string, payload map[string]any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if err := insertAuditLog(ctx, tx, models.AuditLog{
+		HackathonID: hackathonID,
+		ActorID:     actorID,
+		Action:      subject,
+		Payload:     raw,
+	}); err != nil {
+		return err
+	}
+	return publishInTx(ctx, tx, s.Events, domainEvent{subject, payload})
+}
+
+const reportColumns = `id, hackathon_id, COALESCE(reporter_id, ''), type, content, status,
+		       COALESCE(assignee_id, ''), resolution_notes, created_at, updated_at, resolved_at`
+
+func scanReport(row rowScanner) (*models.Report, error) {
+	var r models.Report
+	if err := row.Scan(
+		&r.ID, &r.HackathonID, &r.ReporterID, &r.Type, &r.Content, &r.Status,
+		&r.AssigneeID, &r.ResolutionNotes, &r.CreatedAt, &r.UpdatedAt, &r.ResolvedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func loadReport(ctx context.Context, q rowQuerier, id string, lock bool) (*models.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	r, err := scanReport(q.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return r, nil
+}
+
+const appealColumns = `id, hackathon_id, submission_id, COALESCE(appellant_id, ''), content, status,
+		       COALESCE(assignee_id, ''), resolution_notes, resolution, created_at, updated_at, resolved_at`
+
+func scanAppeal(row rowScanner) (*models.Appeal, error) {
+	var a models.Appeal
+	if err := row.Scan(
+		&a.ID, &a.HackathonID, &a.SubmissionID, &a.AppellantID, &a.Content, &a.Status,
+		&a.AssigneeID, &a.ResolutionNotes, &a.Resolution, &a.CreatedAt, &a.UpdatedAt, &a.ResolvedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func loadAppeal(ctx context.Context, q rowQuerier, id string, lock bool) (*models.Appeal, error) {
+	query := `SELECT ` + appealColumns + ` FROM appeals WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	a, err := scanAppeal(q.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return a, nil
+}
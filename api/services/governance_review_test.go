@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestIsGovernanceTransitionAllowed(t *testing.T) {
+	cases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{models.GovernanceStatusOpen, models.GovernanceStatusUnderReview, true},
+		{models.GovernanceStatusOpen, models.GovernanceStatusWithdrawn, true},
+		{models.GovernanceStatusOpen, models.GovernanceStatusUpheld, false},
+		{models.GovernanceStatusUnderReview, models.GovernanceStatusUpheld, true},
+		{models.GovernanceStatusUnderReview, models.GovernanceStatusRejected, true},
+		{models.GovernanceStatusUnderReview, models.GovernanceStatusWithdrawn, true},
+		{models.GovernanceStatusUpheld, models.GovernanceStatusUnderReview, false},
+		{models.GovernanceStatusWithdrawn, models.GovernanceStatusOpen, false},
+	}
+	for _, tc := range cases {
+		if got := isGovernanceTransitionAllowed(tc.from, tc.to); got != tc.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
+
+func TestCheckGovernanceTransition(t *testing.T) {
+	withdraw := GovernanceTransitionInput{Status: models.GovernanceStatusWithdrawn}
+	if err := checkGovernanceTransition(models.GovernanceStatusOpen, withdraw, "u1", "u1", false); err != nil {
+		t.Fatalf("author withdraw: unexpected error %v", err)
+	}
+	if err := checkGovernanceTransition(models.GovernanceStatusOpen, withdraw, "u2", "u1", false); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("other user withdraw: expected ErrForbidden, got %v", err)
+	}
+
+	review := GovernanceTransitionInput{Status: models.GovernanceStatusUnderReview}
+	if err := checkGovernanceTransition(models.GovernanceStatusOpen, review, "u1", "u1", false); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("author review: expected ErrForbidden, got %v", err)
+	}
+
+	uphold := GovernanceTransitionInput{Status: models.GovernanceStatusUpheld}
+	if err := checkGovernanceTransition(models.GovernanceStatusUnderReview, uphold, "r1", "u1", true); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("uphold without notes: expected ErrInvalid, got %v", err)
+	}
+	uphold.ResolutionNotes = "metric bug confirmed"
+	if err := checkGovernanceTransition(models.GovernanceStatusUnderReview, uphold, "r1", "u1", true); err != nil {
+		t.Fatalf("uphold: unexpected error %v", err)
+	}
+	if err := checkGovernanceTransition(models.GovernanceStatusUpheld, uphold, "r1", "u1", true); !errors.Is(err, ErrConflict) {
+		t.Fatalf("repeat uphold: expected ErrConflict, got %v", err)
+	}
+}
@@ -8,15 +8,17 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type GovernanceService struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Events events.Publisher
 }
 
-func NewGovernanceService(db *sql.DB) *GovernanceService {
-	return &GovernanceService{DB: db}
+func NewGovernanceService(db *sql.DB, publisher events.Publisher) *GovernanceService {
+	return &GovernanceService{DB: db, Events: publisher}
 }
 
 func (s *GovernanceService) CreateReport(ctx context.Context, hackathonID string, input models.Report, reporterID string) (*models.Report, error) {
@@ -31,18 +33,31 @@ func (s *GovernanceService) CreateReport(ctx context.Context, hackathonID string
 		ReporterID:  reporterID,
 		Type:        input.Type,
 		Content:     input.Content,
-		Status:      "open",
+		Status:      models.GovernanceStatusOpen,
 		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	_, err := s.DB.ExecContext(ctx, `
-		INSERT INTO reports (id, hackathon_id, reporter_id, type, content, status, created_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7)`,
-		report.ID, report.HackathonID, report.ReporterID, report.Type, report.Content, report.Status, report.CreatedAt,
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO reports (id, hackathon_id, reporter_id, type, content, status, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		report.ID, report.HackathonID, report.ReporterID, report.Type, report.Content, report.Status, report.CreatedAt, report.UpdatedAt,
 	)
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
+	if err := s.recordReportStep(ctx, tx, &report, reporterID, "governance.report.created"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &report, nil
 }
 
@@ -51,61 +66,73 @@ func (s *GovernanceService) CreateAppeal(ctx context.Context, input models.Appea
 		return nil, fmt.Errorf("submission_id and content are required: %w", ErrInvalid)
 	}
 
-	var exists int
-	if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM submissions WHERE id = $1`, input.SubmissionID).Scan(&exists); err != nil {
-		return nil, mapSQLError(err)
-	}
-	if exists == 0 {
+	var hackathonID string
+	err := s.DB.QueryRowContext(ctx, `SELECT hackathon_id FROM submissions WHERE id = $1`, input.SubmissionID).Scan(&hackathonID)
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("submission not found: %w", ErrNotFound)
 	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
 
 	now := time.Now().UTC()
 	appeal := models.Appeal{
 		ID:           uuid.NewString(),
+		HackathonID:  hackathonID,
 		SubmissionID: input.SubmissionID,
 		AppellantID:  appellantID,
 		Content:      input.Content,
-		Status:       "open",
+		Status:       models.GovernanceStatusOpen,
 		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
-	_, err := s.DB.ExecContext(ctx, `
-		INSERT INTO appeals (id, submission_id, appellant_id, content, status, created_at)
-		VALUES ($1,$2,$3,$4,$5,$6)`,
-		appeal.ID, appeal.SubmissionID, appeal.AppellantID, appeal.Content, appeal.Status, appeal.CreatedAt,
-	)
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
-	return &appeal, nil
-}
+	defer tx.Rollback()
 
-func (s *GovernanceService) AuditLogs(ctx context.Context, hackathonID string, limit, offset int) ([]models.AuditLog, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, actor_id, action, payload, created_at
-		FROM audit_logs
-		WHERE hackathon_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO appeals (id, hackathon_id, submission_id, appellant_id, content, status, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		appeal.ID, appeal.HackathonID, appeal.SubmissionID, appeal.AppellantID, appeal.Content, appeal.Status, appeal.CreatedAt, appeal.UpdatedAt,
+	)
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
-	defer rows.Close()
+	if err := s.recordAppealStep(ctx, tx, &appeal, appellantID, "governance.appeal.created"); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &appeal, nil
+}
 
-	var items []models.AuditLog
-	for rows.Next() {
+func (s *GovernanceService) AuditLogs(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.AuditLog], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, actor_id, action, payload, created_at`,
+		from:    "audit_logs",
+		at:      "created_at",
+	}, q, page, func(row rowScanner) (*models.AuditLog, error) {
 		var log models.AuditLog
 		var payload []byte
-		if err := rows.Scan(&log.ID, &log.HackathonID, &log.ActorID, &log.Action, &payload, &log.CreatedAt); err != nil {
-			return nil, mapSQLError(err)
+		if err := row.Scan(&log.ID, &log.HackathonID, &log.ActorID, &log.Action, &payload, &log.CreatedAt); err != nil {
+			return nil, err
 		}
 		log.Payload = payload
-		items = append(items, log)
-	}
-	return items, nil
+		return &log, nil
+	}, func(log *models.AuditLog) (time.Time, string) { return log.CreatedAt, log.ID })
 }
 
 func (s *GovernanceService) AppendAudit(ctx context.Context, log models.AuditLog) error {
+	return insertAuditLog(ctx, s.DB, log)
+}
+
+func insertAuditLog(ctx context.Context, q execer, log models.AuditLog) error {
 	if log.Action == "" {
 		return fmt.Errorf("action is required: %w", ErrInvalid)
 	}
@@ -116,7 +143,7 @@ func (s *GovernanceService) AppendAudit(ctx context.Context, log models.AuditLog
 		log.CreatedAt = time.Now().UTC()
 	}
 
-	_, err := s.DB.ExecContext(ctx, `
+	_, err := q.ExecContext(ctx, `
 		INSERT INTO audit_logs (id, hackathon_id, actor_id, action, payload, created_at)
 		VALUES ($1,$2,$3,$4,$5,$6)`,
 		log.ID, log.HackathonID, log.ActorID, log.Action, log.Payload, log.CreatedAt,
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const maxMembersPerRequest = 500
+
+// Viewer is the caller a hackathon's visibility is checked against.
+// Unrestricted viewers (platform admins, the evaluation executor) see every
+// hackathon.
+type Viewer struct {
+	UserID       string
+	Unrestricted bool
+}
+
+// hackathonVisibilityCond returns the condition, over the hackathons table,
+// matching the hackathons viewer may see, or ok=false when it sees them all.
+// Drafts are only visible to their creator and staff; private hackathons only
+// to those, allowlisted members and registered participants.
+func hackathonVisibilityCond(viewer Viewer) (format string, args []any, ok bool) {
+	if viewer.Unrestricted {
+		return "", nil, false
+	}
+	listed := `(state <> '` + models.HackathonStateDraft + `' AND visibility <> '` + models.HackathonVisibilityPrivate + `')`
+	if viewer.UserID == "" {
+		return listed, nil, true
+	}
+	return `(created_by = %s OR ` + listed + `
+		OR EXISTS (SELECT 1 FROM hackathon_staff hs WHERE hs.hackathon_id = hackathons.id AND hs.user_id = %s)
+		OR (state <> '` + models.HackathonStateDraft + `' AND (
+			EXISTS (SELECT 1 FROM hackathon_members hm WHERE hm.hackathon_id = hackathons.id AND hm.user_id = %s)
+			OR EXISTS (SELECT 1 FROM hackathon_participants hp WHERE hp.hackathon_id = hackathons.id AND hp.user_id = %s AND hp.status = '` + models.ParticipantStatusRegistered + `'))))`,
+		[]any{viewer.UserID, viewer.UserID, viewer.UserID, viewer.UserID}, true
+}
+
+// hackathonScopes resolve the hackathon of a route parameter naming a
+// hackathon-scoped entity; %s in query is the entity id.
+var hackathonScopes = map[string]struct{ entity, query string }{
+	"hackathonId":   {"hackathon", `SELECT id FROM hackathons WHERE id = %s`},
+	"ruleId":        {"rule", `SELECT hackathon_id FROM rules WHERE id = %s`},
+	"ruleVersionId": {"rule version", `SELECT r.hackathon_id FROM rule_versions v JOIN rules r ON r.id = v.rule_id WHERE v.id = %s`},
+	"submissionId":  {"submission", `SELECT hackathon_id FROM submissions WHERE id = %s`},
+	"reportId":      {"report", `SELECT hackathon_id FROM reports WHERE id = %s`},
+	"appealId":      {"appeal", `SELECT hackathon_id FROM appeals WHERE id = %s`},
+}
+
+// HackathonScopeParams lists the route parameters ResolveAccess understands.
+// A route is scoped by the first one it has.
+var HackathonScopeParams = []string{"hackathonId", "ruleId", "ruleVersionId", "submissionId", "reportId", "appealId"}
+
+type AccessService struct {
+	DB *sql.DB
+}
+
+func NewAccessService(db *sql.DB) *AccessService {
+	return &AccessService{DB: db}
+}
+
+// HackathonAccess is what a caller may do on the hackathon of a route.
+type HackathonAccess struct {
+	HackathonID string
+	Visible     bool
+	StaffRoles  []string
+}
+
+// ResolveAccess returns, in one query, the hackathon owning the entity named
+// by a route parameter, whether viewer may see it and viewer's staff roles on
+// it. An unknown entity is ErrNotFound.
+func (s *AccessService) ResolveAccess(ctx context.Context, param, id string, viewer Viewer) (*HackathonAccess, error) {
+	scope, ok := hackathonScopes[param]
+	if !ok {
+		return nil, fmt.Errorf("unknown hackathon scope %q: %w", param, ErrInvalid)
+	}
+	var q listQuery
+	visible := "TRUE"
+	if format, args, ok := hackathonVisibilityCond(viewer); ok {
+		visible = q.expr(format, args...)
+	}
+	staff := q.expr("hs.user_id = %s", viewer.UserID)
+	q.where("id = ("+scope.query+")", id)
+
+	access := &HackathonAccess{}
+	var roles pq.StringArray
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT id, `+visible+`,
+		       ARRAY(SELECT hs.role FROM hackathon_staff hs WHERE hs.hackathon_id = hackathons.id AND `+staff+` ORDER BY hs.role)
+		FROM hackathons`+q.whereClause(), q.args...).Scan(&access.HackathonID, &access.Visible, &roles)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s not found: %w", scope.entity, ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	access.StaffRoles = append([]string{}, roles...)
+	return access, nil
+}
+
+func (s *AccessService) ListMembers(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.HackathonMember], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{columns: hackathonMemberColumns, from: "hackathon_members", at: "created_at", asc: true}, q, page,
+		scanHackathonMember, func(m *models.HackathonMember) (time.Time, string) { return m.CreatedAt, m.ID })
+}
+
+// AddMembers allowlists userIDs and returns the members that were added;
+// users already on the list are skipped.
+func (s *AccessService) AddMembers(ctx context.Context, hackathonID string, userIDs []string, actorID string) ([]models.HackathonMember, error) {
+	seen := make(map[string]struct{}, len(userIDs))
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			return nil, fmt.Errorf("user_ids must not contain empty values: %w", ErrInvalid)
+		}
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxMembersPerRequest {
+		return nil, fmt.Errorf("user_ids must contain 1 to %d users: %w", maxMembersPerRequest, ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	added := []models.HackathonMember{}
+	for _, userID := range ids {
+		m, err := insertHackathonMember(ctx, tx, hackathonID, userID, nil, actorID, now)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			added = append(added, *m)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (s *AccessService) RemoveMember(ctx context.Context, hackathonID, userID string) error {
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM hackathon_members WHERE hackathon_id = $1 AND user_id = $2`, hackathonID, userID)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("member not found: %w", ErrNotFound)
+	}
+	return nil
+}
+
+type InviteInput struct {
+	MaxUses   int        `json:"max_uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (s *AccessService) CreateInvite(ctx context.Context, hackathonID string, input InviteInput, actorID string) (*models.HackathonInvite, error) {
+	now := time.Now().UTC()
+	if input.MaxUses < 0 {
+		return nil, fmt.Errorf("max_uses must be >= 0: %w", ErrInvalid)
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("expires_at must be in the future: %w", ErrInvalid)
+	}
+	code, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	invite := &models.HackathonInvite{
+		ID:          uuid.NewString(),
+		HackathonID: hackathonID,
+		Code:        code,
+		MaxUses:     input.MaxUses,
+		ExpiresAt:   input.ExpiresAt,
+		CreatedBy:   actorID,
+		CreatedAt:   now,
+	}
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO hackathon_invites (id, hackathon_id, code, max_uses, uses, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, 0, $5, $6, $7)`,
+		invite.ID, invite.HackathonID, invite.Code, invite.MaxUses, invite.ExpiresAt, invite.CreatedBy, invite.CreatedAt)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return invite, nil
+}
+
+func (s *AccessService) ListInvites(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.HackathonInvite], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{columns: hackathonInviteColumns, from: "hackathon_invites", at: "created_at"}, q, page,
+		scanHackathonInvite, func(i *models.HackathonInvite) (time.Time, string) { return i.CreatedAt, i.ID })
+}
+
+// RevokeInvite stops an invite from being redeemed. Members it already added
+// stay on the allowlist.
+func (s *AccessService) RevokeInvite(ctx context.Context, hackathonID, inviteID string) (*models.HackathonInvite, error) {
+	invite, err := scanHackathonInvite(s.DB.QueryRowContext(ctx, `
+		UPDATE hackathon_invites SET revoked_at = NOW()
+		WHERE id = $1 AND hackathon_id = $2 AND revoked_at IS NULL
+		RETURNING `+hackathonInviteColumns, inviteID, hackathonID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("active invite not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return invite, nil
+}
+
+// RedeemInvite adds userID to the members of the invite's hackathon. Unknown,
+// revoked, expired and used-up codes all report ErrNotFound so codes cannot be
+// probed. Redeeming again as an existing member returns the membership without
+// using the invite.
+func (s *AccessService) RedeemInvite(ctx context.Context, code, userID string) (*models.HackathonMember, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("authenticated user required: %w", ErrForbidden)
+	}
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("code is required: %w", ErrInvalid)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	invite, err := scanHackathonInvite(tx.QueryRowContext(ctx, `
+		SELECT `+hackathonInviteColumns+` FROM hackathon_invites
+		WHERE code = $1
+		FOR UPDATE`, code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("invite not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	now := time.Now().UTC()
+	if !inviteRedeemable(invite, now) {
+		return nil, fmt.Errorf("invite not found: %w", ErrNotFound)
+	}
+
+	member, err := insertHackathonMember(ctx, tx, invite.HackathonID, userID, &invite.ID, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		member, err = scanHackathonMember(tx.QueryRowContext(ctx, `
+			SELECT `+hackathonMemberColumns+` FROM hackathon_members
+			WHERE hackathon_id = $1 AND user_id = $2`, invite.HackathonID, userID))
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+	} else if _, err := tx.ExecContext(ctx, `UPDATE hackathon_invites SET uses = uses + 1 WHERE id = $1`, invite.ID); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func inviteRedeemable(invite *models.HackathonInvite, now time.Time) bool {
+	if invite.RevokedAt != nil {
+		return false
+	}
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(now) {
+		return false
+	}
+	return invite.MaxUses == 0 || invite.Uses < invite.MaxUses
+}
+
+// isHackathonMember reports whether userID is on the hackathon's allowlist.
+func isHackathonMember(ctx context.Context, db rowQuerier, hackathonID, userID string) (bool, error) {
+	var member bool
+	err := db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM hackathon_members WHERE hackathon_id = $1 AND user_id = $2)`,
+		hackathonID, userID).Scan(&member)
+	if err != nil {
+		return false, mapSQLError(err)
+	}
+	return member, nil
+}
+
+// insertHackathonMember adds a member and returns it, or nil when the user
+// already is one.
+func insertHackathonMember(ctx context.Context, tx rowQuerier, hackathonID, userID string, inviteID *string, actorID string, now time.Time) (*models.HackathonMember, error) {
+	m, err := scanHackathonMember(tx.QueryRowContext(ctx, `
+		INSERT INTO hackathon_members (id, hackathon_id, user_id, invite_id, added_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (hackathon_id, user_id) DO NOTHING
+		RETURNING `+hackathonMemberColumns,
+		uuid.NewString(), hackathonID, userID, inviteID, actorID, now))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return m, nil
+}
+
+// newInviteCode returns 16 random base32 characters (80 bits).
+func newInviteCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+const hackathonMemberColumns = `id, hackathon_id, user_id, invite_id, added_by, created_at`
+
+func scanHackathonMember(row rowScanner) (*models.HackathonMember, error) {
+	var m models.HackathonMember
+	if err := row.Scan(&m.ID, &m.HackathonID, &m.UserID, &m.InviteID, &m.AddedBy, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+const hackathonInviteColumns = `id, hackathon_id, code, max_uses, uses, expires_at, revoked_at, created_by, created_at`
+
+func scanHackathonInvite(row rowScanner) (*models.HackathonInvite, error) {
+	var i models.HackathonInvite
+	if err := row.Scan(&i.ID, &i.HackathonID, &i.Code, &i.MaxUses, &i.Uses, &i.ExpiresAt, &i.RevokedAt, &i.CreatedBy, &i.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestHackathonVisibilityCond(t *testing.T) {
+	if _, _, ok := hackathonVisibilityCond(Viewer{UserID: "u1", Unrestricted: true}); ok {
+		t.Fatal("expected no condition for unrestricted viewers")
+	}
+
+	format, args, ok := hackathonVisibilityCond(Viewer{})
+	if !ok || len(args) != 0 || strings.Contains(format, "%s") {
+		t.Fatalf("unexpected anonymous condition: %q %v", format, args)
+	}
+
+	var q listQuery
+	format, args, ok = hackathonVisibilityCond(Viewer{UserID: "u1"})
+	if !ok {
+		t.Fatal("expected a condition for regular viewers")
+	}
+	q.where(format, args...)
+	where := q.whereClause()
+	if len(q.args) != 4 || !strings.Contains(where, "created_by = $1") || !strings.Contains(where, "hs.user_id = $2") ||
+		!strings.Contains(where, "hm.user_id = $3") || !strings.Contains(where, "hp.user_id = $4") {
+		t.Fatalf("unexpected condition: %q %v", where, q.args)
+	}
+}
+
+func TestInviteRedeemable(t *testing.T) {
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	cases := []struct {
+		name   string
+		invite models.HackathonInvite
+		want   bool
+	}{
+		{"unlimited", models.HackathonInvite{Uses: 40}, true},
+		{"uses left", models.HackathonInvite{MaxUses: 2, Uses: 1, ExpiresAt: &future}, true},
+		{"used up", models.HackathonInvite{MaxUses: 2, Uses: 2}, false},
+		{"expired", models.HackathonInvite{ExpiresAt: &past}, false},
+		{"revoked", models.HackathonInvite{RevokedAt: &past}, false},
+	}
+	for _, tc := range cases {
+		if got := inviteRedeemable(&tc.invite, now); got != tc.want {
+			t.Fatalf("%s: inviteRedeemable = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNewInviteCode(t *testing.T) {
+	a, err := newInviteCode()
+	if err != nil {
+		t.Fatalf("newInviteCode: %v", err)
+	}
+	b, _ := newInviteCode()
+	if len(a) != 16 || a == b || strings.ToUpper(a) != a {
+		t.Fatalf("unexpected codes %q %q", a, b)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+// ScheduledTransition is a phase change that is due according to a hackathon schedule.
+type ScheduledTransition struct {
+	HackathonID string
+	From        string
+	Target      string
+	DueAt       time.Time
+}
+
+func (s *HackathonService) GetSchedule(ctx context.Context, hackathonID string) (*models.HackathonSchedule, error) {
+	row := s.DB.QueryRowContext(ctx, `
+		SELECT hackathon_id, warmup_at, live_at, freeze_at, evaluation_only_at, completed_at,
+		       COALESCE(last_error, ''), last_attempt_at, failed_attempts, created_at, updated_at
+		FROM hackathon_schedules
+		WHERE hackathon_id = $1`, hackathonID)
+
+	schedule, err := scanHackathonSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return schedule, nil
+}
+
+// PutSchedule creates or replaces the phase schedule of a hackathon. Any
+// previous scheduler error is cleared so the new timestamps are retried.
+func (s *HackathonService) PutSchedule(ctx context.Context, hackathonID string, input models.HackathonSchedule) (*models.HackathonSchedule, error) {
+	state, err := loadHackathonState(ctx, s.DB, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if isStateAtLeast(state, models.HackathonStateCompleted) {
+		return nil, fmt.Errorf("hackathon schedule cannot change in state %s: %w", state, ErrInvalid)
+	}
+	if err := validateHackathonSchedule(input); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO hackathon_schedules (
+			hackathon_id, warmup_at, live_at, freeze_at, evaluation_only_at, completed_at,
+			last_error, last_attempt_at, failed_attempts, created_at, updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,NULL,NULL,0,$7,$7)
+		ON CONFLICT (hackathon_id) DO UPDATE
+		SET warmup_at = EXCLUDED.warmup_at, live_at = EXCLUDED.live_at,
+		    freeze_at = EXCLUDED.freeze_at, evaluation_only_at = EXCLUDED.evaluation_only_at,
+		    completed_at = EXCLUDED.completed_at, last_error = NULL, last_attempt_at = NULL,
+		    failed_attempts = 0, updated_at = EXCLUDED.updated_at`,
+		hackathonID, input.WarmupAt, input.LiveAt, input.FreezeAt, input.EvaluationOnlyAt, input.CompletedAt, now,
+	)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return s.GetSchedule(ctx, hackathonID)
+}
+
+func (s *HackathonService) DeleteSchedule(ctx context.Context, hackathonID string) error {
+	res, err := s.DB.ExecContext(ctx, `DELETE FROM hackathon_schedules WHERE hackathon_id = $1`, hackathonID)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("hackathon schedule not found: %w", ErrNotFound)
+	}
+	return nil
+}
+
+const (
+	scheduleRetryBase = time.Minute
+	scheduleRetryMax  = time.Hour
+)
+
+// scheduleRetryAt returns when a schedule whose last transition failed may be
+// retried: the delay doubles with every failed attempt, from
+// scheduleRetryBase up to scheduleRetryMax. It is nil when nothing failed.
+func scheduleRetryAt(sc models.HackathonSchedule) *time.Time {
+	if sc.FailedAttempts <= 0 || sc.LastAttemptAt == nil {
+		return nil
+	}
+	delay := min(scheduleRetryBase<<min(sc.FailedAttempts-1, 6), scheduleRetryMax)
+	at := sc.LastAttemptAt.Add(delay)
+	return &at
+}
+
+// DueScheduledTransitions lists the next phase change of every hackathon whose
+// scheduled timestamp for its current state has passed, leaving out schedules
+// still backing off after a failed transition.
+func (s *HackathonService) DueScheduledTransitions(ctx context.Context, now time.Time) ([]ScheduledTransition, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT h.state, s.hackathon_id, s.warmup_at, s.live_at, s.freeze_at, s.evaluation_only_at, s.completed_at,
+		       COALESCE(s.last_error, ''), s.last_attempt_at, s.failed_attempts, s.created_at, s.updated_at
+		FROM hackathon_schedules s
+		JOIN hackathons h ON h.id = s.hackathon_id
+		WHERE h.state IN ($1, $2, $3, $4, $5)
+		  AND LEAST(s.warmup_at, s.live_at, s.freeze_at, s.evaluation_only_at, s.completed_at) <= $6
+		ORDER BY s.hackathon_id`,
+		models.HackathonStatePublished, models.HackathonStateWarmup, models.HackathonStateLive,
+		models.HackathonStateSubmissionFrozen, models.HackathonStateEvaluationOnly, now,
+	)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	var due []ScheduledTransition
+	for rows.Next() {
+		var state string
+		var sc models.HackathonSchedule
+		if err := rows.Scan(
+			&state, &sc.HackathonID, &sc.WarmupAt, &sc.LiveAt, &sc.FreezeAt, &sc.EvaluationOnlyAt, &sc.CompletedAt,
+			&sc.LastError, &sc.LastAttemptAt, &sc.FailedAttempts, &sc.CreatedAt, &sc.UpdatedAt,
+		); err != nil {
+			return nil, mapSQLError(err)
+		}
+		target, at := scheduledTarget(sc, state)
+		if at == nil || at.After(now) {
+			continue
+		}
+		if retryAt := scheduleRetryAt(sc); retryAt != nil && retryAt.After(now) {
+			continue
+		}
+		due = append(due, ScheduledTransition{HackathonID: sc.HackathonID, From: state, Target: target, DueAt: *at})
+	}
+	return due, rows.Err()
+}
+
+// RunScheduledTransition applies a due transition through the regular
+// lifecycle path and records the outcome on the schedule. Failures are
+// counted so the schedule backs off; a success resets the count.
+func (s *HackathonService) RunScheduledTransition(ctx context.Context, st ScheduledTransition) (*models.Hackathon, error) {
+	updated, err := s.transition(ctx, st.HackathonID, st.Target, "hackathon.phase.changed")
+
+	var lastError *string
+	if err != nil {
+		msg := err.Error()
+		lastError = &msg
+	}
+	if _, recErr := s.DB.ExecContext(ctx, `
+		UPDATE hackathon_schedules
+		SET last_error = $1, last_attempt_at = NOW(),
+		    failed_attempts = CASE WHEN $1::text IS NULL THEN 0 ELSE failed_attempts + 1 END
+		WHERE hackathon_id = $2`, lastError, st.HackathonID); recErr != nil && err == nil {
+		err = mapSQLError(recErr)
+	}
+	return updated, err
+}
+
+// scheduledTarget returns the phase that follows state and the time it is
+// scheduled for, or a nil time when that phase has no timestamp.
+func scheduledTarget(sc models.HackathonSchedule, state string) (string, *time.Time) {
+	switch state {
+	case models.HackathonStatePublished:
+		return models.HackathonStateWarmup, sc.WarmupAt
+	case models.HackathonStateWarmup:
+		return models.HackathonStateLive, sc.LiveAt
+	case models.HackathonStateLive:
+		return models.HackathonStateSubmissionFrozen, sc.FreezeAt
+	case models.HackathonStateSubmissionFrozen:
+		return models.HackathonStateEvaluationOnly, sc.EvaluationOnlyAt
+	case models.HackathonStateEvaluationOnly:
+		return models.HackathonStateCompleted, sc.CompletedAt
+	default:
+		return "", nil
+	}
+}
+
+func validateHackathonSchedule(sc models.HackathonSchedule) error {
+	phases := []struct {
+		name string
+		at   *time.Time
+	}{
+		{"warmup_at", sc.WarmupAt},
+		{"live_at", sc.LiveAt},
+		{"freeze_at", sc.FreezeAt},
+		{"evaluation_only_at", sc.EvaluationOnlyAt},
+		{"completed_at", sc.CompletedAt},
+	}
+
+	var prevName string
+	var prev *time.Time
+	for _, p := range phases {
+		if p.at == nil {
+			continue
+		}
+		if prev != nil && p.at.Before(*prev) {
+			return fmt.Errorf("%s must not be before %s: %w", p.name, prevName, ErrInvalid)
+		}
+		prevName, prev = p.name, p.at
+	}
+	if prev == nil {
+		return fmt.Errorf("at least one phase timestamp is required: %w", ErrInvalid)
+	}
+	return nil
+}
+
+func scanHackathonSchedule(row *sql.Row) (*models.HackathonSchedule, error) {
+	var sc models.HackathonSchedule
+	if err := row.Scan(
+		&sc.HackathonID, &sc.WarmupAt, &sc.LiveAt, &sc.FreezeAt, &sc.EvaluationOnlyAt, &sc.CompletedAt,
+		&sc.LastError, &sc.LastAttemptAt, &sc.FailedAttempts, &sc.CreatedAt, &sc.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
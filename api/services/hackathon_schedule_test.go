@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestScheduledTarget(t *testing.T) {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	warmup, live, freeze := base, base.Add(time.Hour), base.Add(48*time.Hour)
+	sc := models.HackathonSchedule{WarmupAt: &warmup, LiveAt: &live, FreezeAt: &freeze}
+
+	cases := []struct {
+		state  string
+		target string
+		at     *time.Time
+	}{
+		{models.HackathonStateDraft, "", nil},
+		{models.HackathonStatePublished, models.HackathonStateWarmup, &warmup},
+		{models.HackathonStateWarmup, models.HackathonStateLive, &live},
+		{models.HackathonStateLive, models.HackathonStateSubmissionFrozen, &freeze},
+		{models.HackathonStateSubmissionFrozen, models.HackathonStateEvaluationOnly, nil},
+		{models.HackathonStateArchived, "", nil},
+	}
+
+	for _, tc := range cases {
+		target, at := scheduledTarget(sc, tc.state)
+		if tc.at == nil {
+			if at != nil {
+				t.Fatalf("%s: expected no scheduled time, got=%v", tc.state, at)
+			}
+			continue
+		}
+		if target != tc.target || at == nil || !at.Equal(*tc.at) {
+			t.Fatalf("%s: expected %s at %v, got %s at %v", tc.state, tc.target, tc.at, target, at)
+		}
+		if !isTransitionAllowed(tc.state, target) {
+			t.Fatalf("%s: scheduled target %s is not an allowed transition", tc.state, target)
+		}
+	}
+}
+
+func TestValidateHackathonSchedule(t *testing.T) {
+	early := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	late := early.Add(24 * time.Hour)
+
+	cases := []struct {
+		name  string
+		input models.HackathonSchedule
+		valid bool
+	}{
+		{name: "empty", input: models.HackathonSchedule{}},
+		{name: "ordered", input: models.HackathonSchedule{LiveAt: &early, FreezeAt: &late}, valid: true},
+		{name: "same time", input: models.HackathonSchedule{WarmupAt: &early, LiveAt: &early}, valid: true},
+		{name: "freeze before live", input: models.HackathonSchedule{LiveAt: &late, FreezeAt: &early}},
+		{name: "gap then out of order", input: models.HackathonSchedule{WarmupAt: &late, CompletedAt: &early}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHackathonSchedule(tc.input)
+			if tc.valid && err != nil {
+				t.Fatalf("expected valid schedule, got=%v", err)
+			}
+			if !tc.valid && !errors.Is(err, ErrInvalid) {
+				t.Fatalf("expected ErrInvalid, got=%v", err)
+			}
+		})
+	}
+}
+
+func TestScheduleRetryAt(t *testing.T) {
+	last := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	if at := scheduleRetryAt(models.HackathonSchedule{LastAttemptAt: &last}); at != nil {
+		t.Fatalf("expected no backoff without failures, got %v", at)
+	}
+	cases := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+		{50, time.Hour},
+	}
+	for _, tc := range cases {
+		at := scheduleRetryAt(models.HackathonSchedule{LastAttemptAt: &last, FailedAttempts: tc.attempts})
+		if at == nil || !at.Equal(last.Add(tc.delay)) {
+			t.Fatalf("attempts=%d: expected retry after %v, got %v", tc.attempts, tc.delay, at)
+		}
+	}
+}
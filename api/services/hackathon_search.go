@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+const (
+	hackathonSearchConfig = "english"
+	maxSearchTextLength   = 200
+)
+
+// hackathonSearchVector is the SQL expression stored in hackathons.search_vector
+// for the given title and description expressions. It must match the backfill
+// in migrations/0015_hackathon_search.up.sql.
+func hackathonSearchVector(title, description string) string {
+	return fmt.Sprintf(`setweight(to_tsvector('%[1]s', coalesce(%[2]s, '')), 'A') ||
+		setweight(to_tsvector('%[1]s', coalesce(%[3]s, '')), 'B')`, hackathonSearchConfig, title, description)
+}
+
+const hackathonTeamPolicyExpr = `CASE WHEN requires_teams THEN '` + models.TeamPolicyRequired +
+	`' WHEN allows_teams THEN '` + models.TeamPolicyOptional +
+	`' ELSE '` + models.TeamPolicySolo + `' END`
+
+const (
+	facetState       = "state"
+	facetVisibility  = "visibility"
+	facetTeamPolicy  = "team_policy"
+	facetMetricType  = "metric_type"
+	facetStartsMonth = "starts_month"
+)
+
+// hackathonFacets maps each facet to the expression it groups by. The
+// metric_type facet is counted from evaluation_metrics instead.
+var hackathonFacets = map[string]string{
+	facetState:       "state",
+	facetVisibility:  "visibility",
+	facetTeamPolicy:  hackathonTeamPolicyExpr,
+	facetStartsMonth: `to_char(starts_at AT TIME ZONE 'UTC', 'YYYY-MM')`,
+}
+
+// HackathonSearchQuery is a full-text search with facet filters. Text uses
+// web search syntax ("quoted phrases", -exclusions, or); an empty Text lists
+// the filtered hackathons newest first.
+type HackathonSearchQuery struct {
+	Text       string
+	Filter     HackathonFilter
+	TeamPolicy string
+	MetricType string
+	Limit      int
+	Offset     int
+}
+
+// searchCond is one WHERE condition of a search, tagged with the facet it
+// filters so facet counts can leave their own filter out.
+type searchCond struct {
+	facet  string
+	format string
+	args   []any
+}
+
+func hackathonSearchConds(query HackathonSearchQuery) ([]searchCond, error) {
+	text := strings.TrimSpace(query.Text)
+	if len(text) > maxSearchTextLength {
+		return nil, fmt.Errorf("search text is longer than %d characters: %w", maxSearchTextLength, ErrInvalid)
+	}
+	var conds []searchCond
+	// The text condition goes first so its argument is always $1.
+	if text != "" {
+		conds = append(conds, searchCond{format: "search_vector @@ websearch_to_tsquery('" + hackathonSearchConfig + "', %s)", args: []any{text}})
+	}
+	if query.Filter.Viewer != nil {
+		if format, args, ok := hackathonVisibilityCond(*query.Filter.Viewer); ok {
+			conds = append(conds, searchCond{format: format, args: args})
+		}
+	}
+	if query.Filter.State != "" {
+		conds = append(conds, searchCond{facet: facetState, format: "state = %s", args: []any{query.Filter.State}})
+	}
+	if query.Filter.Visibility != "" {
+		conds = append(conds, searchCond{facet: facetVisibility, format: "visibility = %s", args: []any{query.Filter.Visibility}})
+	}
+	switch query.TeamPolicy {
+	case "":
+	case models.TeamPolicySolo, models.TeamPolicyOptional, models.TeamPolicyRequired:
+		conds = append(conds, searchCond{facet: facetTeamPolicy, format: hackathonTeamPolicyExpr + " = %s", args: []any{query.TeamPolicy}})
+	default:
+		return nil, fmt.Errorf("team_policy must be one of %s, %s, %s: %w", models.TeamPolicySolo, models.TeamPolicyOptional, models.TeamPolicyRequired, ErrInvalid)
+	}
+	if query.MetricType != "" {
+		conds = append(conds, searchCond{facet: facetMetricType, format: "EXISTS (SELECT 1 FROM evaluation_metrics m WHERE m.hackathon_id = hackathons.id AND m.metric_type = %s)", args: []any{query.MetricType}})
+	}
+	if query.Filter.StartsAfter != nil {
+		conds = append(conds, searchCond{facet: facetStartsMonth, format: "starts_at >= %s", args: []any{*query.Filter.StartsAfter}})
+	}
+	if query.Filter.StartsBefore != nil {
+		conds = append(conds, searchCond{facet: facetStartsMonth, format: "starts_at <= %s", args: []any{*query.Filter.StartsBefore}})
+	}
+	if query.Filter.EndsAfter != nil {
+		conds = append(conds, searchCond{format: "ends_at >= %s", args: []any{*query.Filter.EndsAfter}})
+	}
+	if query.Filter.EndsBefore != nil {
+		conds = append(conds, searchCond{format: "ends_at <= %s", args: []any{*query.Filter.EndsBefore}})
+	}
+	return conds, nil
+}
+
+// searchListQuery builds the WHERE clause from conds, leaving out the
+// conditions of the skipped facet.
+func searchListQuery(conds []searchCond, skip string) listQuery {
+	var q listQuery
+	for _, c := range conds {
+		if skip != "" && c.facet == skip {
+			continue
+		}
+		q.where(c.format, c.args...)
+	}
+	return q
+}
+
+// Search ranks hackathons by relevance to query.Text and returns facet counts
+// over the matches. Each facet is counted with every filter except its own,
+// so clients can show the alternatives to a selected value.
+func (s *HackathonService) Search(ctx context.Context, query HackathonSearchQuery) (*models.HackathonSearchResult, error) {
+	conds, err := hackathonSearchConds(query)
+	if err != nil {
+		return nil, err
+	}
+	out := &models.HackathonSearchResult{Items: []models.HackathonSearchHit{}, Facets: map[string][]models.FacetCount{}}
+	q := searchListQuery(conds, "")
+	if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM hackathons`+q.whereClause(), q.args...).Scan(&out.Total); err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	ranked := `0::real AS rank, NULL, NULL`
+	if strings.TrimSpace(query.Text) != "" {
+		const tsq = `websearch_to_tsquery('` + hackathonSearchConfig + `', $1)`
+		// Matches are delimited with control characters stripped from the
+		// source, so highlightHTML can escape the text and mark only them.
+		const sel = `'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', `
+		ranked = `ts_rank_cd(search_vector, ` + tsq + `) AS rank,
+		       ts_headline('` + hackathonSearchConfig + `', translate(title, chr(2) || chr(3), ''), ` + tsq + `, ` + sel + `HighlightAll=true'),
+		       ts_headline('` + hackathonSearchConfig + `', translate(coalesce(description, ''), chr(2) || chr(3), ''), ` + tsq + `,
+		                   ` + sel + `MaxWords=35, MinWords=15, MaxFragments=2')`
+	}
+	q.args = append(q.args, query.Limit, query.Offset)
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+hackathonColumns+`,
+		       `+ranked+`
+		FROM hackathons`+q.whereClause()+`
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $`+strconv.Itoa(len(q.args)-1)+` OFFSET $`+strconv.Itoa(len(q.args)), q.args...)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		hit, err := scanHackathonSearchHit(rows)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		out.Items = append(out.Items, *hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	for facet, expr := range hackathonFacets {
+		fq := searchListQuery(conds, facet)
+		counts, err := s.facetCounts(ctx, `
+			SELECT `+expr+`, COUNT(*)
+			FROM hackathons`+fq.whereClause()+`
+			GROUP BY 1`, fq.args)
+		if err != nil {
+			return nil, err
+		}
+		out.Facets[facet] = counts
+	}
+	mq := searchListQuery(conds, facetMetricType)
+	counts, err := s.facetCounts(ctx, `
+		SELECT metric_type, COUNT(DISTINCT hackathon_id)
+		FROM evaluation_metrics
+		WHERE hackathon_id IN (SELECT id FROM hackathons`+mq.whereClause()+`)
+		GROUP BY 1`, mq.args)
+	if err != nil {
+		return nil, err
+	}
+	out.Facets[facetMetricType] = counts
+	return out, nil
+}
+
+// facetCounts runs a (value, count) aggregation, dropping NULL values and
+// sorting by count then value.
+func (s *HackathonService) facetCounts(ctx context.Context, query string, args []any) ([]models.FacetCount, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT * FROM (`+query+`) AS f (value, count) WHERE value IS NOT NULL ORDER BY count DESC, value`, args...)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+	counts := []models.FacetCount{}
+	for rows.Next() {
+		var fc models.FacetCount
+		if err := rows.Scan(&fc.Value, &fc.Count); err != nil {
+			return nil, mapSQLError(err)
+		}
+		counts = append(counts, fc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapSQLError(err)
+	}
+	return counts, nil
+}
+
+func scanHackathonSearchHit(row rowScanner) (*models.HackathonSearchHit, error) {
+	var h models.Hackathon
+	var metadata []byte
+	var hit models.HackathonSearchHit
+	var title, description sql.NullString
+	if err := row.Scan(
+		&h.ID, &h.Title, &h.Description, &h.State, &h.Visibility, &h.StartsAt, &h.EndsAt,
+		&h.AllowsTeams, &h.RequiresTeams, &h.MinTeamSize, &h.MaxTeamSize, &h.MaxParticipants,
+		&h.ActiveRuleVersionID, &h.LeaderboardFrozen, &h.LeaderboardPublished,
+		&h.CreatedBy, &metadata, &h.CreatedAt, &h.UpdatedAt, &h.PublishedAt, &h.CompletedAt, &h.ArchivedAt,
+		&hit.Rank, &title, &description,
+	); err != nil {
+		return nil, err
+	}
+	h.Metadata = metadata
+	hit.Hackathon = h
+	if title.Valid {
+		hit.Highlights = &models.HackathonHighlights{Title: highlightHTML(title.String), Description: highlightHTML(description.String)}
+	}
+	return &hit, nil
+}
+
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+var highlightMarks = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// highlightHTML escapes a ts_headline snippet and turns its match delimiters
+// into <mark> tags, the only markup a highlight may carry.
+func highlightHTML(headline string) string {
+	return highlightMarks.Replace(html.EscapeString(headline))
+}
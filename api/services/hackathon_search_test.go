@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestHackathonSearchConds(t *testing.T) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	conds, err := hackathonSearchConds(HackathonSearchQuery{
+		Text:       "  crop yield ",
+		Filter:     HackathonFilter{State: models.HackathonStateLive, StartsAfter: &start},
+		TeamPolicy: models.TeamPolicyOptional,
+		MetricType: "rmse",
+	})
+	if err != nil {
+		t.Fatalf("hackathonSearchConds: %v", err)
+	}
+
+	q := searchListQuery(conds, "")
+	where := q.whereClause()
+	if !strings.HasPrefix(where, " WHERE search_vector @@ websearch_to_tsquery('english', $1)") || q.args[0] != "crop yield" {
+		t.Fatalf("expected the text condition first, got %q %v", where, q.args)
+	}
+	if len(q.args) != 5 || !strings.Contains(where, "m.metric_type = $4") || !strings.Contains(where, "starts_at >= $5") {
+		t.Fatalf("unexpected query: %q %v", where, q.args)
+	}
+
+	withoutState := searchListQuery(conds, facetState)
+	if strings.Contains(withoutState.whereClause(), "state =") || len(withoutState.args) != 4 || withoutState.args[0] != "crop yield" {
+		t.Fatalf("expected the state filter to be skipped: %q %v", withoutState.whereClause(), withoutState.args)
+	}
+}
+
+func TestHackathonSearchCondsInvalid(t *testing.T) {
+	cases := []HackathonSearchQuery{
+		{TeamPolicy: "duo"},
+		{Text: strings.Repeat("a", maxSearchTextLength+1)},
+	}
+	for _, query := range cases {
+		if _, err := hackathonSearchConds(query); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("expected ErrInvalid for %+v, got %v", query, err)
+		}
+	}
+	conds, err := hackathonSearchConds(HackathonSearchQuery{Text: "   "})
+	if err != nil || len(conds) != 0 {
+		t.Fatalf("expected no conditions for blank text, got %+v (%v)", conds, err)
+	}
+}
+
+func TestHighlightHTMLEscapesSource(t *testing.T) {
+	title := "<script>alert(1)</script> \x02Crop\x03 & \x02yield\x03"
+	got := highlightHTML(title)
+	want := "&lt;script&gt;alert(1)&lt;/script&gt; <mark>Crop</mark> &amp; <mark>yield</mark>"
+	if got != want {
+		t.Fatalf("highlightHTML = %q, want %q", got, want)
+	}
+}
@@ -9,15 +9,17 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type HackathonService struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Events events.Publisher
 }
 
-func NewHackathonService(db *sql.DB) *HackathonService {
-	return &HackathonService{DB: db}
+func NewHackathonService(db *sql.DB, publisher events.Publisher) *HackathonService {
+	return &HackathonService{DB: db, Events: publisher}
 }
 
 func (s *HackathonService) Create(ctx context.Context, input models.Hackathon, actorID string) (*models.Hackathon, error) {
@@ -33,29 +35,35 @@ func (s *HackathonService) Create(ctx context.Context, input models.Hackathon, a
 	h.CreatedAt = now
 	h.UpdatedAt = now
 	if h.Visibility == "" {
-		h.Visibility = "public"
+		h.Visibility = models.HackathonVisibilityPublic
 	}
 	if h.Metadata == nil {
 		h.Metadata = json.RawMessage(`{}`)
 	}
 
-	_, err := s.DB.ExecContext(ctx, `
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO hackathons (
 			id, title, description, state, visibility,
 			starts_at, ends_at, allows_teams, requires_teams,
-			min_team_size, max_team_size, active_rule_version_id,
+			min_team_size, max_team_size, max_participants, active_rule_version_id,
 			leaderboard_frozen, leaderboard_published,
-			created_by, metadata, created_at, updated_at
+			created_by, metadata, created_at, updated_at, search_vector
 		) VALUES (
 			$1,$2,$3,$4,$5,
 			$6,$7,$8,$9,
-			$10,$11,$12,
-			$13,$14,
-			$15,$16,$17,$18
+			$10,$11,$12,$13,
+			$14,$15,
+			$16,$17,$18,$19, `+hackathonSearchVector("$2::text", "$3::text")+`
 		)`,
 		h.ID, h.Title, h.Description, h.State, h.Visibility,
 		h.StartsAt, h.EndsAt, h.AllowsTeams, h.RequiresTeams,
-		h.MinTeamSize, h.MaxTeamSize, h.ActiveRuleVersionID,
+		h.MinTeamSize, h.MaxTeamSize, h.MaxParticipants, h.ActiveRuleVersionID,
 		h.LeaderboardFrozen, h.LeaderboardPublished,
 		h.CreatedBy, h.Metadata, h.CreatedAt, h.UpdatedAt,
 	)
@@ -63,61 +71,94 @@ func (s *HackathonService) Create(ctx context.Context, input models.Hackathon, a
 		return nil, mapSQLError(err)
 	}
 
+	// The creator owns the hackathon and manages its staff.
+	if actorID != "" {
+		if _, err := insertHackathonStaff(ctx, tx, h.ID, actorID, models.StaffRoleOwner, actorID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"hackathon.created", map[string]any{
+		"hackathon_id": h.ID,
+		"state":        h.State,
+	}}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return &h, nil
 }
 
-func (s *HackathonService) List(ctx context.Context, limit, offset int) ([]models.Hackathon, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, title, description, state, visibility, starts_at, ends_at,
-		       allows_teams, requires_teams, min_team_size, max_team_size,
-		       active_rule_version_id, leaderboard_frozen, leaderboard_published,
-		       created_by, metadata, created_at, updated_at, published_at, completed_at, archived_at
-		FROM hackathons
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2`, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.Hackathon
-	for rows.Next() {
-		var h models.Hackathon
-		var metadata []byte
-		if err := rows.Scan(
-			&h.ID, &h.Title, &h.Description, &h.State, &h.Visibility, &h.StartsAt, &h.EndsAt,
-			&h.AllowsTeams, &h.RequiresTeams, &h.MinTeamSize, &h.MaxTeamSize,
-			&h.ActiveRuleVersionID, &h.LeaderboardFrozen, &h.LeaderboardPublished,
-			&h.CreatedBy, &metadata, &h.CreatedAt, &h.UpdatedAt, &h.PublishedAt, &h.CompletedAt, &h.ArchivedAt,
-		); err != nil {
-			return nil, mapSQLError(err)
+// HackathonFilter narrows List; empty fields are ignored. The starts_at and
+// ends_at bounds are inclusive and exclude hackathons without that date.
+type HackathonFilter struct {
+	// Viewer, when set, hides the hackathons it may not see.
+	Viewer       *Viewer
+	State        string
+	Visibility   string
+	StartsAfter  *time.Time
+	StartsBefore *time.Time
+	EndsAfter    *time.Time
+	EndsBefore   *time.Time
+}
+
+func (s *HackathonService) List(ctx context.Context, filter HackathonFilter, page PageRequest) (*models.Page[models.Hackathon], error) {
+	var q listQuery
+	if filter.Viewer != nil {
+		if format, args, ok := hackathonVisibilityCond(*filter.Viewer); ok {
+			q.where(format, args...)
 		}
-		h.Metadata = metadata
-		items = append(items, h)
 	}
-	return items, nil
+	if filter.State != "" {
+		q.where("state = %s", filter.State)
+	}
+	if filter.Visibility != "" {
+		q.where("visibility = %s", filter.Visibility)
+	}
+	if filter.StartsAfter != nil {
+		q.where("starts_at >= %s", *filter.StartsAfter)
+	}
+	if filter.StartsBefore != nil {
+		q.where("starts_at <= %s", *filter.StartsBefore)
+	}
+	if filter.EndsAfter != nil {
+		q.where("ends_at >= %s", *filter.EndsAfter)
+	}
+	if filter.EndsBefore != nil {
+		q.where("ends_at <= %s", *filter.EndsBefore)
+	}
+	return queryPage(ctx, s.DB, keyset{columns: hackathonColumns, from: "hackathons", at: "created_at"}, q, page,
+		scanHackathon, func(h *models.Hackathon) (time.Time, string) { return h.CreatedAt, h.ID })
 }
 
 func (s *HackathonService) GetByID(ctx context.Context, id string) (*models.Hackathon, error) {
-	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, title, description, state, visibility, starts_at, ends_at,
-		       allows_teams, requires_teams, min_team_size, max_team_size,
+	h, err := scanHackathon(s.DB.QueryRowContext(ctx, `SELECT `+hackathonColumns+` FROM hackathons WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return h, nil
+}
+
+const hackathonColumns = `id, title, description, state, visibility, starts_at, ends_at,
+		       allows_teams, requires_teams, min_team_size, max_team_size, max_participants,
 		       active_rule_version_id, leaderboard_frozen, leaderboard_published,
-		       created_by, metadata, created_at, updated_at, published_at, completed_at, archived_at
-		FROM hackathons WHERE id = $1`, id)
+		       created_by, metadata, created_at, updated_at, published_at, completed_at, archived_at`
 
+func scanHackathon(row rowScanner) (*models.Hackathon, error) {
 	var h models.Hackathon
 	var metadata []byte
 	if err := row.Scan(
 		&h.ID, &h.Title, &h.Description, &h.State, &h.Visibility, &h.StartsAt, &h.EndsAt,
-		&h.AllowsTeams, &h.RequiresTeams, &h.MinTeamSize, &h.MaxTeamSize,
+		&h.AllowsTeams, &h.RequiresTeams, &h.MinTeamSize, &h.MaxTeamSize, &h.MaxParticipants,
 		&h.ActiveRuleVersionID, &h.LeaderboardFrozen, &h.LeaderboardPublished,
 		&h.CreatedBy, &metadata, &h.CreatedAt, &h.UpdatedAt, &h.PublishedAt, &h.CompletedAt, &h.ArchivedAt,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	h.Metadata = metadata
 	return &h, nil
@@ -131,13 +172,14 @@ func (s *HackathonService) Update(ctx context.Context, id string, input models.H
 	now := time.Now().UTC()
 	res, err := s.DB.ExecContext(ctx, `
 		UPDATE hackathons
-		SET title = $1, description = $2, visibility = $3, starts_at = $4, ends_at = $5,
+		SET title = $1, description = $2, visibility = COALESCE(NULLIF($3, ''), visibility), starts_at = $4, ends_at = $5,
 		    allows_teams = $6, requires_teams = $7, min_team_size = $8, max_team_size = $9,
-		    metadata = $10, updated_at = $11
-		WHERE id = $12`,
+		    max_participants = $10, metadata = $11, updated_at = $12,
+		    search_vector = `+hackathonSearchVector("$1::text", "$2::text")+`
+		WHERE id = $13`,
 		input.Title, input.Description, input.Visibility, input.StartsAt, input.EndsAt,
 		input.AllowsTeams, input.RequiresTeams, input.MinTeamSize, input.MaxTeamSize,
-		normalizeMetadata(input.Metadata), now, id,
+		input.MaxParticipants, normalizeMetadata(input.Metadata), now, id,
 	)
 	if err != nil {
 		return nil, mapSQLError(err)
@@ -180,11 +222,11 @@ func (s *HackathonService) Delete(ctx context.Context, id string) error {
 }
 
 func (s *HackathonService) Publish(ctx context.Context, id string) (*models.Hackathon, error) {
-	return s.transition(ctx, id, models.HackathonStatePublished)
+	return s.transition(ctx, id, models.HackathonStatePublished, "hackathon.published")
 }
 
 func (s *HackathonService) Transition(ctx context.Context, id, target string) (*models.Hackathon, error) {
-	return s.transition(ctx, id, target)
+	return s.transition(ctx, id, target, "hackathon.phase.changed")
 }
 
 func (s *HackathonService) GetState(ctx context.Context, id string) (string, error) {
@@ -199,23 +241,6 @@ func (s *HackathonService) GetState(ctx context.Context, id string) (string, err
 	return state, nil
 }
 
-func (s *HackathonService) SetActiveRuleVersion(ctx context.Context, hackathonID, ruleVersionID string) error {
-	res, err := s.DB.ExecContext(ctx, `
-		UPDATE hackathons SET active_rule_version_id = $1, updated_at = NOW()
-		WHERE id = $2`, ruleVersionID, hackathonID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return fmt.Errorf("hackathon not found: %w", ErrNotFound)
-	}
-	return nil
-}
-
 func (s *HackathonService) GetTeamPolicy(ctx context.Context, id string) (*models.TeamPolicy, error) {
 	var policy models.TeamPolicy
 	err := s.DB.QueryRowContext(ctx, `
@@ -258,19 +283,7 @@ func (s *HackathonService) FreezeLeaderboard(ctx context.Context, id string) (*m
 		return nil, fmt.Errorf("hackathon must be submission_frozen or later to freeze leaderboard: %w", ErrInvalid)
 	}
 
-	res, err := s.DB.ExecContext(ctx, `
-		UPDATE hackathons SET leaderboard_frozen = true, updated_at = NOW() WHERE id = $1`, id)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
-	}
-	return s.GetByID(ctx, id)
+	return s.setLeaderboardFlag(ctx, id, "leaderboard_frozen", true, "leaderboard.freeze.requested")
 }
 
 func (s *HackathonService) PublishLeaderboard(ctx context.Context, id string) (*models.Hackathon, error) {
@@ -285,19 +298,7 @@ func (s *HackathonService) PublishLeaderboard(ctx context.Context, id string) (*
 		return nil, fmt.Errorf("hackathon must be completed to publish leaderboard: %w", ErrInvalid)
 	}
 
-	res, err := s.DB.ExecContext(ctx, `
-		UPDATE hackathons SET leaderboard_published = true, updated_at = NOW() WHERE id = $1`, id)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return nil, err
-	}
-	if affected == 0 {
-		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
-	}
-	return s.GetByID(ctx, id)
+	return s.setLeaderboardFlag(ctx, id, "leaderboard_published", true, "leaderboard.publish.requested")
 }
 
 func (s *HackathonService) UnfreezeLeaderboard(ctx context.Context, id string) (*models.Hackathon, error) {
@@ -312,8 +313,18 @@ func (s *HackathonService) UnfreezeLeaderboard(ctx context.Context, id string) (
 		return nil, fmt.Errorf("hackathon must be submission_frozen or later to unfreeze leaderboard: %w", ErrInvalid)
 	}
 
-	res, err := s.DB.ExecContext(ctx, `
-		UPDATE hackathons SET leaderboard_frozen = false, updated_at = NOW() WHERE id = $1`, id)
+	return s.setLeaderboardFlag(ctx, id, "leaderboard_frozen", false, "leaderboard.unfreeze.requested")
+}
+
+func (s *HackathonService) setLeaderboardFlag(ctx context.Context, id, column string, value bool, subject string) (*models.Hackathon, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE hackathons SET `+column+` = $1, updated_at = NOW() WHERE id = $2`, value, id)
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
@@ -324,10 +335,26 @@ func (s *HackathonService) UnfreezeLeaderboard(ctx context.Context, id string) (
 	if affected == 0 {
 		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
 	}
+	if column == "leaderboard_frozen" {
+		if value {
+			err = snapshotLeaderboard(ctx, tx, id)
+		} else {
+			err = clearLeaderboardSnapshot(ctx, tx, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{subject, map[string]any{"hackathon_id": id}}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return s.GetByID(ctx, id)
 }
 
-func (s *HackathonService) transition(ctx context.Context, id, target string) (*models.Hackathon, error) {
+func (s *HackathonService) transition(ctx context.Context, id, target, subject string) (*models.Hackathon, error) {
 	h, err := s.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -360,22 +387,81 @@ func (s *HackathonService) transition(ctx context.Context, id, target string) (*
 		archivedAt = &now
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
 		UPDATE hackathons
 		SET state = $1, published_at = COALESCE($2, published_at),
 		    completed_at = COALESCE($3, completed_at),
 		    archived_at = COALESCE($4, archived_at),
 		    updated_at = NOW()
-		WHERE id = $5`,
-		target, publishedAt, completedAt, archivedAt, h.ID,
+		WHERE id = $5 AND state = $6`,
+		target, publishedAt, completedAt, archivedAt, h.ID, h.State,
 	)
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("hackathon state changed concurrently: %w", ErrConflict)
+	}
+
+	evts := transitionEvents(h, target, subject)
+	if target == models.HackathonStatePublished || target == models.HackathonStateLive {
+		// The dataset can still change while published, so it is captured
+		// again when the hackathon goes live and data edits stop.
+		reason := models.DatasetVersionReasonPublished
+		if target == models.HackathonStateLive {
+			reason = models.DatasetVersionReasonLive
+		}
+		version, created, err := snapshotDataset(ctx, tx, h.ID, reason, "")
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			evts = append(evts, datasetVersionEvent(version))
+		}
+	}
+	if target == models.HackathonStateSubmissionFrozen {
+		selected, err := autoSelectFinalSubmissions(ctx, tx, h.ID)
+		if err != nil {
+			return nil, err
+		}
+		if selected > 0 {
+			evts = append(evts, domainEvent{"submission.final.auto_selected", map[string]any{"hackathon_id": h.ID, "count": selected}})
+		}
+	}
+	if err := publishInTx(ctx, tx, s.Events, evts...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
 	return s.GetByID(ctx, id)
 }
 
+func transitionEvents(h *models.Hackathon, target, subject string) []domainEvent {
+	evts := []domainEvent{{subject, map[string]any{"hackathon_id": h.ID, "state": target}}}
+	if target == models.HackathonStateLive && h.RequiresTeams {
+		evts = append(evts, domainEvent{"hackathon.team.required", map[string]any{"hackathon_id": h.ID}})
+	}
+	if target == models.HackathonStateSubmissionFrozen {
+		evts = append(evts, domainEvent{"hackathon.team.locked", map[string]any{"hackathon_id": h.ID}})
+	}
+	if target == models.HackathonStateCompleted {
+		evts = append(evts, domainEvent{"hackathon.completed", map[string]any{"hackathon_id": h.ID}})
+	}
+	return evts
+}
+
 func validateHackathonInput(h models.Hackathon) error {
 	if h.Title == "" {
 		return fmt.Errorf("title is required: %w", ErrInvalid)
@@ -392,6 +478,15 @@ func validateHackathonInput(h models.Hackathon) error {
 	if h.MaxTeamSize > 0 && h.MinTeamSize > h.MaxTeamSize {
 		return fmt.Errorf("min_team_size cannot exceed max_team_size: %w", ErrInvalid)
 	}
+	if h.MaxParticipants < 0 {
+		return fmt.Errorf("max_participants must be >= 0: %w", ErrInvalid)
+	}
+	switch h.Visibility {
+	case "", models.HackathonVisibilityPublic, models.HackathonVisibilityPrivate, models.HackathonVisibilityInviteOnly:
+	default:
+		return fmt.Errorf("visibility must be one of %s, %s, %s: %w",
+			models.HackathonVisibilityPublic, models.HackathonVisibilityPrivate, models.HackathonVisibilityInviteOnly, ErrInvalid)
+	}
 	return nil
 }
 
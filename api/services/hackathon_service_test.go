@@ -36,6 +36,10 @@ func TestValidateHackathonInput(t *testing.T) {
 			name: "min greater than max",
 			input: models.Hackathon{Title: "Test", MinTeamSize: 5, MaxTeamSize: 3},
 		},
+		{
+			name: "unknown visibility",
+			input: models.Hackathon{Title: "Test", Visibility: "secret"},
+		},
 		{
 			name: "valid",
 			input: models.Hackathon{Title: "Test", StartsAt: &start, EndsAt: &end, AllowsTeams: true, RequiresTeams: true, MinTeamSize: 2, MaxTeamSize: 5},
@@ -1,8 +1,19 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/jsonschema"
 )
 
 func ensureValidJSON(raw json.RawMessage, field string) error {
@@ -17,3 +28,139 @@ func ensureValidJSON(raw json.RawMessage, field string) error {
 	}
 	return nil
 }
+
+// compileResponseSchema checks that a dataset response_schema is a usable
+// JSON Schema document.
+func compileResponseSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	schema, err := jsonschema.Compile(raw)
+	if err != nil {
+		var errs jsonschema.Errors
+		if errors.As(err, &errs) {
+			return nil, &FieldErrors{Message: "invalid response_schema", Errors: errs}
+		}
+		return nil, fmt.Errorf("invalid response_schema: %w", ErrInvalid)
+	}
+	return schema, nil
+}
+
+// validateSubmissionMetadata checks metadata against the hackathon's dataset
+// response schema. Hackathons without a dataset accept any metadata.
+func validateSubmissionMetadata(ctx context.Context, q rowQuerier, hackathonID string, metadata json.RawMessage) error {
+	var raw []byte
+	err := q.QueryRowContext(ctx, `SELECT response_schema FROM hackathon_datasets WHERE hackathon_id = $1`, hackathonID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return mapSQLError(err)
+	}
+	schema, err := jsonschema.Compile(raw)
+	if err != nil {
+		// Schemas are validated on save; one that no longer compiles is a server-side problem.
+		return fmt.Errorf("stored response_schema is invalid: %w", err)
+	}
+	if errs := schema.Validate(metadata); len(errs) > 0 {
+		return &FieldErrors{Message: "metadata does not match response_schema", Errors: errs}
+	}
+	return nil
+}
+
+// diffJSON compares two JSON documents and returns the changes needed to turn
+// from into to. Objects are compared key by key and arrays index by index.
+func diffJSON(from, to json.RawMessage) ([]models.JSONChange, error) {
+	a, err := decodeJSONValue(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json: %w", ErrInvalid)
+	}
+	b, err := decodeJSONValue(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json: %w", ErrInvalid)
+	}
+	changes := []models.JSONChange{}
+	diffJSONValue("", a, b, &changes)
+	return changes, nil
+}
+
+func decodeJSONValue(raw json.RawMessage) (any, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func diffJSONValue(path string, a, b any, changes *[]models.JSONChange) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, seen := av[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "/" + escapeJSONPointer(k)
+			old, inA := av[k]
+			val, inB := bv[k]
+			switch {
+			case !inA:
+				*changes = append(*changes, models.JSONChange{Op: models.JSONChangeAdd, Path: child, Value: rawJSONValue(val)})
+			case !inB:
+				*changes = append(*changes, models.JSONChange{Op: models.JSONChangeRemove, Path: child, OldValue: rawJSONValue(old)})
+			default:
+				diffJSONValue(child, old, val, changes)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		common := len(av)
+		if len(bv) < common {
+			common = len(bv)
+		}
+		for i := 0; i < common; i++ {
+			diffJSONValue(path+"/"+strconv.Itoa(i), av[i], bv[i], changes)
+		}
+		// Remove from the end so earlier indexes stay valid when applied in order.
+		for i := len(av) - 1; i >= common; i-- {
+			*changes = append(*changes, models.JSONChange{Op: models.JSONChangeRemove, Path: path + "/" + strconv.Itoa(i), OldValue: rawJSONValue(av[i])})
+		}
+		for i := common; i < len(bv); i++ {
+			*changes = append(*changes, models.JSONChange{Op: models.JSONChangeAdd, Path: path + "/" + strconv.Itoa(i), Value: rawJSONValue(bv[i])})
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, models.JSONChange{Op: models.JSONChangeReplace, Path: path, OldValue: rawJSONValue(a), Value: rawJSONValue(b)})
+	}
+}
+
+// rawJSONValue encodes a value decoded by decodeJSONValue; nil becomes null.
+func rawJSONValue(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return raw
+}
+
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
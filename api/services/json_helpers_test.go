@@ -20,3 +20,74 @@ func TestEnsureValidJSON(t *testing.T) {
 		t.Fatalf("expected ErrInvalid for malformed json, got=%v", err)
 	}
 }
+
+func TestDiffJSON(t *testing.T) {
+	from := json.RawMessage(`{"title":"Rules","limits":{"per_day":5,"total":20},"banned":["a","b","c"],"a/b":1}`)
+	to := json.RawMessage(`{"title":"Rules","limits":{"per_day":3},"banned":["a","x"],"extra":true,"a/b":1}`)
+
+	changes, err := diffJSON(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, c := range changes {
+		got[c.Path] = c.Op
+	}
+	want := map[string]string{
+		"/banned/1":       "replace",
+		"/banned/2":       "remove",
+		"/extra":          "add",
+		"/limits/per_day": "replace",
+		"/limits/total":   "remove",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), changes)
+	}
+	for path, op := range want {
+		if got[path] != op {
+			t.Fatalf("expected %s %s, got %v", op, path, changes)
+		}
+	}
+}
+
+func TestDiffJSON_TypeChangeAndEscaping(t *testing.T) {
+	changes, err := diffJSON(json.RawMessage(`{"a/b":{"x":1}}`), json.RawMessage(`{"a/b":[1]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Op != "replace" || changes[0].Path != "/a~1b" {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+
+	if _, err := diffJSON(json.RawMessage(`{broken`), json.RawMessage(`{}`)); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid, got=%v", err)
+	}
+}
+
+func TestDiffJSON_KeepsNullValues(t *testing.T) {
+	changes, err := diffJSON(json.RawMessage(`{"a":null,"b":1}`), json.RawMessage(`{"b":null,"c":null}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, _ := json.Marshal(changes)
+	want := `[{"op":"remove","path":"/a","old_value":null},{"op":"replace","path":"/b","old_value":1,"value":null},{"op":"add","path":"/c","value":null}]`
+	if string(raw) != want {
+		t.Fatalf("unexpected changes:\n got %s\nwant %s", raw, want)
+	}
+}
+
+func TestCompileResponseSchema(t *testing.T) {
+	if _, err := compileResponseSchema(json.RawMessage(`{"type":"object"}`)); err != nil {
+		t.Fatalf("expected valid schema, got %v", err)
+	}
+
+	_, err := compileResponseSchema(json.RawMessage(`{"type":"objekt"}`))
+	var fieldErrs *FieldErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs.Errors) == 0 || fieldErrs.Errors[0].Path != "/type" {
+		t.Fatalf("expected path-level error at /type, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("FieldErrors should match ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,476 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
+	"github.com/lib/pq"
+)
+
+type LeaderboardService struct {
+	DB     *sql.DB
+	Events events.Publisher
+}
+
+func NewLeaderboardService(db *sql.DB, publisher events.Publisher) *LeaderboardService {
+	return &LeaderboardService{DB: db, Events: publisher}
+}
+
+type LeaderboardQuery struct {
+	Board string
+	// Live asks for the live projection while the leaderboard is frozen.
+	// Only honoured for privileged callers.
+	Live       bool
+	Privileged bool
+	Limit      int
+	Offset     int
+}
+
+// Get returns one board of the leaderboard. While the leaderboard is frozen
+// the snapshot taken at freeze time is served. The private board is only
+// visible to privileged callers until the leaderboard is published.
+func (s *LeaderboardService) Get(ctx context.Context, hackathonID string, q LeaderboardQuery) (*models.Leaderboard, error) {
+	board := q.Board
+	if board == "" {
+		board = models.LeaderboardBoardPublic
+	}
+	if board != models.LeaderboardBoardPublic && board != models.LeaderboardBoardPrivate {
+		return nil, fmt.Errorf("board must be public or private: %w", ErrInvalid)
+	}
+
+	lb := models.Leaderboard{HackathonID: hackathonID, Board: board, Direction: models.MetricDirectionMaximize}
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT leaderboard_frozen, leaderboard_published FROM hackathons WHERE id = $1`, hackathonID).
+		Scan(&lb.Frozen, &lb.Published)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	if board == models.LeaderboardBoardPrivate && !lb.Published && !q.Privileged {
+		return nil, fmt.Errorf("private leaderboard is not published: %w", ErrForbidden)
+	}
+
+	metrics, err := loadLeaderboardMetrics(ctx, s.DB, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if primary := primaryLeaderboardMetric(metrics); primary != nil {
+		lb.PrimaryMetric = primary.Name
+		lb.Direction = primary.Direction
+	}
+
+	snapshot := lb.Frozen && !(q.Live && q.Privileged)
+	var updatedAt sql.NullTime
+	if err := s.DB.QueryRowContext(ctx, `
+		SELECT COUNT(*), MAX(updated_at) FROM leaderboard_entries
+		WHERE hackathon_id = $1 AND board = $2 AND snapshot = $3`, hackathonID, board, snapshot).
+		Scan(&lb.Total, &updatedAt); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if updatedAt.Valid {
+		lb.UpdatedAt = &updatedAt.Time
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT rank, participant_key, user_id, team_id, submission_id, score, composite_score, submission_count, scored_at
+		FROM leaderboard_entries
+		WHERE hackathon_id = $1 AND board = $2 AND snapshot = $3
+		ORDER BY rank, scored_at, participant_key
+		LIMIT $4 OFFSET $5`, hackathonID, board, snapshot, q.Limit, q.Offset)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	lb.Entries = []models.LeaderboardEntry{}
+	for rows.Next() {
+		var e models.LeaderboardEntry
+		if err := rows.Scan(&e.Rank, &e.ParticipantKey, &e.UserID, &e.TeamID, &e.SubmissionID, &e.Score, &e.CompositeScore, &e.SubmissionCount, &e.ScoredAt); err != nil {
+			return nil, mapSQLError(err)
+		}
+		lb.Entries = append(lb.Entries, e)
+	}
+	return &lb, nil
+}
+
+// Rebuild recomputes the live projection from scored submissions, e.g. after
+// the primary metric or its direction changed.
+func (s *LeaderboardService) Rebuild(ctx context.Context, hackathonID string) error {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := refreshLeaderboard(ctx, tx, hackathonID); err != nil {
+		return err
+	}
+	if err := publishInTx(ctx, tx, s.Events, leaderboardUpdatedEvent(hackathonID, "")); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type leaderboardSubmission struct {
+	ID             string
+	UserID         string
+	TeamID         *string
+	Metadata       json.RawMessage
+	PublicScore    *float64
+	PrivateScore   *float64
+	FinalSelection string
+	ScoredAt       time.Time
+}
+
+// refreshLeaderboard recomputes the live rows of both boards inside tx and
+// writes only the rows that changed. A transaction-scoped advisory lock
+// serializes concurrent refreshes of the same hackathon. When final selection
+// is enabled only selected submissions count on the private board.
+func refreshLeaderboard(ctx context.Context, tx *sql.Tx, hackathonID string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "leaderboard:"+hackathonID); err != nil {
+		return mapSQLError(err)
+	}
+
+	metrics, err := loadLeaderboardMetrics(ctx, tx, hackathonID)
+	if err != nil {
+		return err
+	}
+	maxFinal, err := loadMaxFinalSelections(ctx, tx, hackathonID)
+	if err != nil {
+		return err
+	}
+	subs, err := loadScoredSubmissions(ctx, tx, hackathonID)
+	if err != nil {
+		return err
+	}
+	boards := map[string][]leaderboardSubmission{
+		models.LeaderboardBoardPublic:  subs,
+		models.LeaderboardBoardPrivate: subs,
+	}
+	if maxFinal > 0 {
+		boards[models.LeaderboardBoardPrivate] = finalSubmissions(subs)
+	}
+
+	now := time.Now().UTC()
+	for _, board := range []string{models.LeaderboardBoardPublic, models.LeaderboardBoardPrivate} {
+		if err := writeLeaderboardBoard(ctx, tx, hackathonID, board, rankLeaderboard(board, boards[board], metrics), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLeaderboardBoard makes the live rows of board match entries: rows of
+// participants that dropped off are deleted and the rest are upserted in one
+// statement that leaves unchanged rows (and their updated_at) alone.
+func writeLeaderboardBoard(ctx context.Context, tx *sql.Tx, hackathonID, board string, entries []models.LeaderboardEntry, now time.Time) error {
+	keys := make([]string, len(entries))
+	userIDs := make([]string, len(entries))
+	teamIDs := make([]string, len(entries))
+	submissionIDs := make([]string, len(entries))
+	ranks := make([]int64, len(entries))
+	scores := make([]float64, len(entries))
+	composites := make([]sql.NullFloat64, len(entries))
+	counts := make([]int64, len(entries))
+	scoredAt := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.ParticipantKey
+		userIDs[i] = e.UserID
+		if e.TeamID != nil {
+			teamIDs[i] = *e.TeamID
+		}
+		submissionIDs[i] = e.SubmissionID
+		ranks[i] = int64(e.Rank)
+		scores[i] = e.Score
+		if e.CompositeScore != nil {
+			composites[i] = sql.NullFloat64{Float64: *e.CompositeScore, Valid: true}
+		}
+		counts[i] = int64(e.SubmissionCount)
+		scoredAt[i] = e.ScoredAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM leaderboard_entries
+		WHERE hackathon_id = $1 AND board = $2 AND snapshot = false
+		  AND NOT (participant_key = ANY($3))`, hackathonID, board, pq.Array(keys)); err != nil {
+		return mapSQLError(err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO leaderboard_entries (
+			hackathon_id, board, snapshot, participant_key, user_id, team_id,
+			submission_id, rank, score, composite_score, submission_count, scored_at, updated_at
+		)
+		SELECT $1, $2, false, e.participant_key, e.user_id, NULLIF(e.team_id, ''),
+		       e.submission_id::uuid, e.rank, e.score, e.composite_score, e.submission_count, e.scored_at::timestamptz, $12
+		FROM unnest($3::text[], $4::text[], $5::text[], $6::text[], $7::int[], $8::float8[], $9::float8[], $10::int[], $11::text[])
+		     AS e(participant_key, user_id, team_id, submission_id, rank, score, composite_score, submission_count, scored_at)
+		ON CONFLICT (hackathon_id, board, snapshot, participant_key) DO UPDATE
+		SET user_id = EXCLUDED.user_id, team_id = EXCLUDED.team_id, submission_id = EXCLUDED.submission_id,
+		    rank = EXCLUDED.rank, score = EXCLUDED.score, composite_score = EXCLUDED.composite_score,
+		    submission_count = EXCLUDED.submission_count, scored_at = EXCLUDED.scored_at, updated_at = EXCLUDED.updated_at
+		WHERE (leaderboard_entries.user_id, leaderboard_entries.team_id, leaderboard_entries.submission_id,
+		       leaderboard_entries.rank, leaderboard_entries.score, leaderboard_entries.composite_score,
+		       leaderboard_entries.submission_count, leaderboard_entries.scored_at)
+		  IS DISTINCT FROM
+		      (EXCLUDED.user_id, EXCLUDED.team_id, EXCLUDED.submission_id, EXCLUDED.rank, EXCLUDED.score,
+		       EXCLUDED.composite_score, EXCLUDED.submission_count, EXCLUDED.scored_at)`,
+		hackathonID, board, pq.Array(keys), pq.Array(userIDs), pq.Array(teamIDs), pq.Array(submissionIDs),
+		pq.Array(ranks), pq.Array(scores), pq.Array(composites), pq.Array(counts), pq.Array(scoredAt), now)
+	return mapSQLError(err)
+}
+
+// snapshotLeaderboard refreshes the live projection and copies it into the
+// snapshot served while the leaderboard is frozen.
+func snapshotLeaderboard(ctx context.Context, tx *sql.Tx, hackathonID string) error {
+	if err := refreshLeaderboard(ctx, tx, hackathonID); err != nil {
+		return err
+	}
+	if err := clearLeaderboardSnapshot(ctx, tx, hackathonID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO leaderboard_entries (
+			hackathon_id, board, snapshot, participant_key, user_id, team_id,
+			submission_id, rank, score, composite_score, submission_count, scored_at, updated_at
+		)
+		SELECT hackathon_id, board, true, participant_key, user_id, team_id,
+		       submission_id, rank, score, composite_score, submission_count, scored_at, NOW()
+		FROM leaderboard_entries
+		WHERE hackathon_id = $1 AND snapshot = false`, hackathonID)
+	return mapSQLError(err)
+}
+
+func clearLeaderboardSnapshot(ctx context.Context, tx *sql.Tx, hackathonID string) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM leaderboard_entries WHERE hackathon_id = $1 AND snapshot = true`, hackathonID)
+	return mapSQLError(err)
+}
+
+// loadScoredSubmissions returns the scored submissions of a hackathon. The
+// scoring time is when the current evaluation attempt finished; submissions
+// scored without an attempt row fall back to their last update.
+func loadScoredSubmissions(ctx context.Context, q rowsQuerier, hackathonID string) ([]leaderboardSubmission, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT s.id, s.submitted_by, s.team_id, s.metadata, s.public_score, s.private_score,
+		       COALESCE(s.final_selection, ''), COALESCE(ev.finished_at, s.updated_at)
+		FROM submissions s
+		LEFT JOIN LATERAL (
+			SELECT finished_at FROM submission_evaluations e
+			WHERE e.submission_id = s.id AND e.status = $2 AND e.superseded_at IS NULL
+			ORDER BY e.attempt DESC
+			LIMIT 1
+		) ev ON true
+		WHERE s.hackathon_id = $1 AND s.status = $2`, hackathonID, models.SubmissionStatusScored)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	var subs []leaderboardSubmission
+	for rows.Next() {
+		var sub leaderboardSubmission
+		var metadata []byte
+		if err := rows.Scan(&sub.ID, &sub.UserID, &sub.TeamID, &metadata, &sub.PublicScore, &sub.PrivateScore, &sub.FinalSelection, &sub.ScoredAt); err != nil {
+			return nil, mapSQLError(err)
+		}
+		sub.Metadata = metadata
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func finalSubmissions(subs []leaderboardSubmission) []leaderboardSubmission {
+	var selected []leaderboardSubmission
+	for _, sub := range subs {
+		if sub.FinalSelection != "" {
+			selected = append(selected, sub)
+		}
+	}
+	return selected
+}
+
+func loadLeaderboardMetrics(ctx context.Context, q rowsQuerier, hackathonID string) ([]models.EvaluationMetric, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, metric_type, direction, scope, COALESCE(target_variable, ''), weight, is_primary
+		FROM evaluation_metrics
+		WHERE hackathon_id = $1
+		ORDER BY created_at`, hackathonID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	var metrics []models.EvaluationMetric
+	for rows.Next() {
+		m := models.EvaluationMetric{HackathonID: hackathonID}
+		if err := rows.Scan(&m.ID, &m.Name, &m.MetricType, &m.Direction, &m.Scope, &m.TargetVariable, &m.Weight, &m.IsPrimary); err != nil {
+			return nil, mapSQLError(err)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, rows.Err()
+}
+
+func primaryLeaderboardMetric(metrics []models.EvaluationMetric) *models.EvaluationMetric {
+	for i := range metrics {
+		if metrics[i].IsPrimary {
+			return &metrics[i]
+		}
+	}
+	return nil
+}
+
+type leaderboardCandidate struct {
+	entry models.LeaderboardEntry
+	// key orders by the primary score with higher always better; it breaks
+	// ties between equal composites.
+	key float64
+}
+
+// rankLeaderboard keeps the best scored submission per participant for board
+// and ranks participants by their composite score.
+// Submissions without a composite rank after those with one; equal
+// composites are broken by the primary metric direction, and participants
+// that are still equal share a rank.
+func rankLeaderboard(board string, subs []leaderboardSubmission, metrics []models.EvaluationMetric) []models.LeaderboardEntry {
+	best := map[string]*leaderboardCandidate{}
+	counts := map[string]int{}
+	for _, c := range leaderboardCandidates(board, subs, metrics) {
+		counts[c.entry.ParticipantKey]++
+		if current, ok := best[c.entry.ParticipantKey]; !ok || leaderboardBetter(c, current) {
+			best[c.entry.ParticipantKey] = c
+		}
+	}
+
+	ranked := make([]*leaderboardCandidate, 0, len(best))
+	for _, c := range best {
+		c.entry.SubmissionCount = counts[c.entry.ParticipantKey]
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return leaderboardBetter(ranked[i], ranked[j]) })
+
+	entries := make([]models.LeaderboardEntry, len(ranked))
+	for i, c := range ranked {
+		c.entry.Rank = i + 1
+		if i > 0 && leaderboardTied(c, ranked[i-1]) {
+			c.entry.Rank = entries[i-1].Rank
+		}
+		entries[i] = c.entry
+	}
+	return entries
+}
+
+// leaderboardCandidates scores every submission that has a score for board.
+func leaderboardCandidates(board string, subs []leaderboardSubmission, metrics []models.EvaluationMetric) []*leaderboardCandidate {
+	primary := primaryLeaderboardMetric(metrics)
+	minimize := primary != nil && primary.Direction == models.MetricDirectionMinimize
+
+	candidates := make([]*leaderboardCandidate, 0, len(subs))
+	for i := range subs {
+		sub := &subs[i]
+		payload := metadataToMap(sub.Metadata)
+		score, ok := leaderboardScore(sub, payload, board)
+		if !ok {
+			continue
+		}
+		key := score
+		if minimize {
+			key = -score
+		}
+		c := &leaderboardCandidate{
+			entry: models.LeaderboardEntry{
+				ParticipantKey: leaderboardParticipantKey(sub.UserID, sub.TeamID),
+				UserID:         sub.UserID,
+				TeamID:         sub.TeamID,
+				SubmissionID:   sub.ID,
+				Score:          score,
+				ScoredAt:       sub.ScoredAt,
+			},
+			key: key,
+		}
+		if v, ok := compositeScore(boardMetricValues(payload, board), metrics); ok {
+			c.entry.CompositeScore = &v
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+func leaderboardBetter(a, b *leaderboardCandidate) bool {
+	ac, bc := a.entry.CompositeScore, b.entry.CompositeScore
+	if (ac == nil) != (bc == nil) {
+		return ac != nil
+	}
+	if ac != nil && *ac != *bc {
+		return *ac > *bc
+	}
+	if a.key != b.key {
+		return a.key > b.key
+	}
+	if !a.entry.ScoredAt.Equal(b.entry.ScoredAt) {
+		return a.entry.ScoredAt.Before(b.entry.ScoredAt)
+	}
+	return a.entry.SubmissionID < b.entry.SubmissionID
+}
+
+func leaderboardTied(a, b *leaderboardCandidate) bool {
+	ac, bc := a.entry.CompositeScore, b.entry.CompositeScore
+	if (ac == nil) != (bc == nil) || (ac != nil && *ac != *bc) {
+		return false
+	}
+	return a.key == b.key
+}
+
+func leaderboardParticipantKey(userID string, teamID *string) string {
+	if teamID != nil && *teamID != "" {
+		return "team:" + *teamID
+	}
+	return "user:" + userID
+}
+
+// leaderboardScore reads the score of a submission for board, preferring the
+// scores recorded by MarkScored over metadata. The public board falls back to
+// the generic score; the private board requires an explicit one.
+func leaderboardScore(sub *leaderboardSubmission, payload map[string]any, board string) (float64, bool) {
+	if board == models.LeaderboardBoardPublic && sub.PublicScore != nil {
+		return *sub.PublicScore, true
+	}
+	if board == models.LeaderboardBoardPrivate && sub.PrivateScore != nil {
+		return *sub.PrivateScore, true
+	}
+	scores, _ := payload["scores"].(map[string]any)
+	if score, ok := numericFrom(payload[board+"_score"]); ok {
+		return score, true
+	}
+	if score, ok := numericFrom(scores[board]); ok {
+		return score, true
+	}
+	if board == models.LeaderboardBoardPublic {
+		return extractScoreFromMetadata(sub.Metadata)
+	}
+	return 0, false
+}
+
+func leaderboardUpdatedEvent(hackathonID, submissionID string) domainEvent {
+	payload := map[string]any{"hackathon_id": hackathonID}
+	if submissionID != "" {
+		payload["submission_id"] = submissionID
+	}
+	return domainEvent{"leaderboard.updated", payload}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestRankLeaderboard_BestPerParticipant(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	team := "team-a"
+	subs := []leaderboardSubmission{
+		{ID: "s1", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.70}`), ScoredAt: base},
+		{ID: "s2", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.80}`), ScoredAt: base.Add(time.Hour)},
+		{ID: "s3", UserID: "u2", TeamID: &team, Metadata: json.RawMessage(`{"score": 0.90}`), ScoredAt: base},
+		{ID: "s4", UserID: "u3", TeamID: &team, Metadata: json.RawMessage(`{"score": 0.60}`), ScoredAt: base},
+		{ID: "s5", UserID: "u4", Metadata: json.RawMessage(`{"note": "no score"}`), ScoredAt: base},
+	}
+	metrics := []models.EvaluationMetric{{ID: "m1", Name: "accuracy", Direction: models.MetricDirectionMaximize, IsPrimary: true}}
+
+	entries := rankLeaderboard(models.LeaderboardBoardPublic, subs, metrics)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].ParticipantKey != "team:team-a" || entries[0].SubmissionID != "s3" || entries[0].SubmissionCount != 2 {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ParticipantKey != "user:u1" || entries[1].SubmissionID != "s2" || entries[1].Rank != 2 {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestRankLeaderboard_MinimizeAndTies(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subs := []leaderboardSubmission{
+		{ID: "s1", UserID: "u1", Metadata: json.RawMessage(`{"score": 2.5}`), ScoredAt: base},
+		{ID: "s2", UserID: "u2", Metadata: json.RawMessage(`{"score": 1.5}`), ScoredAt: base},
+		{ID: "s3", UserID: "u3", Metadata: json.RawMessage(`{"score": 1.0}`), ScoredAt: base.Add(time.Hour)},
+		{ID: "s4", UserID: "u4", Metadata: json.RawMessage(`{"score": 2.5}`), ScoredAt: base.Add(time.Hour)},
+	}
+	metrics := []models.EvaluationMetric{{ID: "m1", Name: "rmse", Direction: models.MetricDirectionMinimize, IsPrimary: true}}
+
+	entries := rankLeaderboard(models.LeaderboardBoardPublic, subs, metrics)
+	assertLeaderboardOrder(t, entries, []string{"u3", "u2", "u1", "u4"}, []int{1, 2, 3, 3})
+}
+
+func TestRankLeaderboard_Composite(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subs := []leaderboardSubmission{
+		// u1 has the best accuracy but the slowest latency, in seconds.
+		{ID: "s1", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.95, "metrics": {"accuracy": 0.95, "latency": 0.4}}`), ScoredAt: base},
+		{ID: "s2", UserID: "u2", Metadata: json.RawMessage(`{"score": 0.90, "metrics": {"accuracy": 0.90, "latency": 0.1}}`), ScoredAt: base},
+		{ID: "s3", UserID: "u3", Metadata: json.RawMessage(`{"score": 0.85, "metrics": {"accuracy": 0.85, "latency": 0.25}}`), ScoredAt: base},
+		{ID: "s4", UserID: "u4", Metadata: json.RawMessage(`{"score": 0.99}`), ScoredAt: base},
+	}
+	metrics := []models.EvaluationMetric{
+		{ID: "m1", Name: "accuracy", MetricType: "accuracy", Direction: models.MetricDirectionMaximize, IsPrimary: true, Weight: 1},
+		{ID: "m2", Name: "latency", MetricType: "custom", Direction: models.MetricDirectionMinimize, Weight: 1},
+	}
+
+	entries := rankLeaderboard(models.LeaderboardBoardPublic, subs, metrics)
+	// Latency normalizes to 1/(1+v): u2 (0.9 + 1/1.1) / 2, u1 (0.95 + 1/1.4) / 2,
+	// u3 (0.85 + 1/1.25) / 2; u4 has no composite and ranks last despite its
+	// primary score.
+	assertLeaderboardOrder(t, entries, []string{"u2", "u1", "u3", "u4"}, []int{1, 2, 3, 4})
+	if entries[0].CompositeScore == nil || math.Abs(*entries[0].CompositeScore-(0.9+1/1.1)/2) > 1e-9 || entries[0].Score != 0.90 {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	// A composite does not depend on the other submissions.
+	alone := rankLeaderboard(models.LeaderboardBoardPublic, subs[1:2], metrics)
+	if *alone[0].CompositeScore != *entries[0].CompositeScore {
+		t.Fatalf("composite moved with the population: %v vs %v", *alone[0].CompositeScore, *entries[0].CompositeScore)
+	}
+	if entries[3].CompositeScore != nil {
+		t.Fatalf("expected no composite without metrics: %+v", entries[3])
+	}
+}
+
+func assertLeaderboardOrder(t *testing.T, entries []models.LeaderboardEntry, wantUsers []string, wantRanks []int) {
+	t.Helper()
+	got := make([]string, len(entries))
+	ranks := make([]int, len(entries))
+	for i, e := range entries {
+		got[i] = e.UserID
+		ranks[i] = e.Rank
+	}
+	if len(entries) != len(wantUsers) {
+		t.Fatalf("want users %v ranks %v, got %v %v", wantUsers, wantRanks, got, ranks)
+	}
+	for i := range wantUsers {
+		if got[i] != wantUsers[i] || ranks[i] != wantRanks[i] {
+			t.Fatalf("want users %v ranks %v, got %v %v", wantUsers, wantRanks, got, ranks)
+		}
+	}
+}
+
+func TestRankLeaderboard_PrivateBoard(t *testing.T) {
+	subs := []leaderboardSubmission{
+		{ID: "s1", UserID: "u1", Metadata: json.RawMessage(`{"score": 0.9, "private_score": 0.5}`)},
+		{ID: "s2", UserID: "u2", Metadata: json.RawMessage(`{"scores": {"public": 0.7, "private": 0.8}}`)},
+		{ID: "s3", UserID: "u3", Metadata: json.RawMessage(`{"score": 0.95}`)},
+	}
+
+	public := rankLeaderboard(models.LeaderboardBoardPublic, subs, nil)
+	if len(public) != 3 || public[0].UserID != "u3" || public[2].Score != 0.7 {
+		t.Fatalf("unexpected public board: %+v", public)
+	}
+	private := rankLeaderboard(models.LeaderboardBoardPrivate, subs, nil)
+	if len(private) != 2 || private[0].UserID != "u2" || private[1].Score != 0.5 {
+		t.Fatalf("unexpected private board: %+v", private)
+	}
+}
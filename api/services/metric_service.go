@@ -10,15 +10,17 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type MetricService struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Events events.Publisher
 }
 
-func NewMetricService(db *sql.DB) *MetricService {
-	return &MetricService{DB: db}
+func NewMetricService(db *sql.DB, publisher events.Publisher) *MetricService {
+	return &MetricService{DB: db, Events: publisher}
 }
 
 func (s *MetricService) Create(ctx context.Context, hackathonID string, input models.EvaluationMetric) (*models.EvaluationMetric, error) {
@@ -35,49 +37,46 @@ func (s *MetricService) Create(ctx context.Context, hackathonID string, input mo
 		}
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		INSERT INTO evaluation_metrics (id, hackathon_id, name, metric_type, direction, scope, target_variable, weight, description, params, is_primary, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
-		metric.ID, metric.HackathonID, metric.Name, metric.MetricType, metric.Direction, metric.Scope, nullableString(metric.TargetVariable), metric.Weight, metric.Description, metric.Params, metric.IsPrimary, metric.CreatedAt, metric.UpdatedAt,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO evaluation_metrics (id, hackathon_id, name, metric_type, direction, scope, target_variable, weight, description, params, is_primary, created_at, updated_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
+			metric.ID, metric.HackathonID, metric.Name, metric.MetricType, metric.Direction, metric.Scope, nullableString(metric.TargetVariable), metric.Weight, metric.Description, metric.Params, metric.IsPrimary, metric.CreatedAt, metric.UpdatedAt,
+		); err != nil {
+			return mapSQLError(err)
+		}
+		if metric.IsPrimary {
+			return clearPrimaryMetric(ctx, tx, metric.HackathonID, metric.ID)
+		}
+		return nil
+	}, metricEvent("hackathon.metric.created", hackathonID, metric.ID))
 	if err != nil {
-		return nil, mapSQLError(err)
-	}
-
-	if metric.IsPrimary {
-		_ = s.clearPrimary(ctx, metric.HackathonID, metric.ID)
+		return nil, err
 	}
-
 	return &metric, nil
 }
 
-func (s *MetricService) List(ctx context.Context, hackathonID string, limit, offset int) ([]models.EvaluationMetric, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, name, metric_type, direction, scope, target_variable, weight, description, params, is_primary, created_at, updated_at
-		FROM evaluation_metrics
-		WHERE hackathon_id = $1
-		ORDER BY created_at
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.EvaluationMetric
-	for rows.Next() {
+func (s *MetricService) List(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.EvaluationMetric], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, name, metric_type, direction, scope, target_variable, weight, description, params, is_primary, created_at, updated_at`,
+		from:    "evaluation_metrics",
+		at:      "created_at",
+		asc:     true,
+	}, q, page, func(row rowScanner) (*models.EvaluationMetric, error) {
 		var m models.EvaluationMetric
 		var params []byte
 		var target sql.NullString
-		if err := rows.Scan(&m.ID, &m.HackathonID, &m.Name, &m.MetricType, &m.Direction, &m.Scope, &target, &m.Weight, &m.Description, &params, &m.IsPrimary, &m.CreatedAt, &m.UpdatedAt); err != nil {
-			return nil, mapSQLError(err)
+		if err := row.Scan(&m.ID, &m.HackathonID, &m.Name, &m.MetricType, &m.Direction, &m.Scope, &target, &m.Weight, &m.Description, &params, &m.IsPrimary, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
 		}
 		if target.Valid {
 			m.TargetVariable = target.String
 		}
 		m.Params = params
-		items = append(items, m)
-	}
-	return items, nil
+		return &m, nil
+	}, func(m *models.EvaluationMetric) (time.Time, string) { return m.CreatedAt, m.ID })
 }
 
 func (s *MetricService) GetByID(ctx context.Context, hackathonID, metricID string) (*models.EvaluationMetric, error) {
@@ -199,10 +198,14 @@ func (s *MetricService) Update(ctx context.Context, hackathonID, metricID string
 	}
 	params := existing.Params
 	if input.Params != nil {
-		if err := ensureValidJSON(*input.Params, "params"); err != nil {
+		params = *input.Params
+	}
+	if input.Params != nil || metricType != existing.MetricType {
+		validated, err := validateMetricParams(metricType, params)
+		if err != nil {
 			return nil, err
 		}
-		params = normalizeMetadata(*input.Params)
+		params = validated
 	}
 	isPrimary := existing.IsPrimary
 	if input.IsPrimary != nil {
@@ -212,17 +215,22 @@ func (s *MetricService) Update(ctx context.Context, hackathonID, metricID string
 		return nil, fmt.Errorf("primary metric weight must be > 0: %w", ErrInvalid)
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE evaluation_metrics
-		SET name = $1, metric_type = $2, direction = $3, scope = $4, target_variable = $5, weight = $6, description = $7, params = $8, is_primary = $9, updated_at = NOW()
-		WHERE id = $10 AND hackathon_id = $11`,
-		name, metricType, direction, scope, nullableString(targetVariable), weight, description, params, isPrimary, metricID, hackathonID,
-	)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE evaluation_metrics
+			SET name = $1, metric_type = $2, direction = $3, scope = $4, target_variable = $5, weight = $6, description = $7, params = $8, is_primary = $9, updated_at = NOW()
+			WHERE id = $10 AND hackathon_id = $11`,
+			name, metricType, direction, scope, nullableString(targetVariable), weight, description, params, isPrimary, metricID, hackathonID,
+		); err != nil {
+			return mapSQLError(err)
+		}
+		if isPrimary {
+			return clearPrimaryMetric(ctx, tx, hackathonID, metricID)
+		}
+		return nil
+	}, metricEvent("hackathon.metric.updated", hackathonID, metricID))
 	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	if isPrimary {
-		_ = s.clearPrimary(ctx, hackathonID, metricID)
+		return nil, err
 	}
 	return s.GetByID(ctx, hackathonID, metricID)
 }
@@ -231,28 +239,34 @@ func (s *MetricService) Delete(ctx context.Context, hackathonID, metricID string
 	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
 		return err
 	}
-	res, err := s.DB.ExecContext(ctx, `DELETE FROM evaluation_metrics WHERE id = $1 AND hackathon_id = $2`, metricID, hackathonID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return fmt.Errorf("metric not found: %w", ErrNotFound)
-	}
-	return nil
+	return execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM evaluation_metrics WHERE id = $1 AND hackathon_id = $2`, metricID, hackathonID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("metric not found: %w", ErrNotFound)
+		}
+		return nil
+	}, metricEvent("hackathon.metric.deleted", hackathonID, metricID))
 }
 
-func (s *MetricService) clearPrimary(ctx context.Context, hackathonID, keepID string) error {
-	_, err := s.DB.ExecContext(ctx, `
+func clearPrimaryMetric(ctx context.Context, q execer, hackathonID, keepID string) error {
+	_, err := q.ExecContext(ctx, `
 		UPDATE evaluation_metrics
 		SET is_primary = false
 		WHERE hackathon_id = $1 AND id <> $2`, hackathonID, keepID)
 	return mapSQLError(err)
 }
 
+func metricEvent(subject, hackathonID, metricID string) domainEvent {
+	return domainEvent{subject, map[string]any{"hackathon_id": hackathonID, "metric_id": metricID}}
+}
+
 func buildMetric(hackathonID string, input models.EvaluationMetric) (models.EvaluationMetric, error) {
 	name := strings.TrimSpace(input.Name)
 	metricType := normalizeMetricType(input.MetricType)
@@ -293,7 +307,8 @@ func buildMetric(hackathonID string, input models.EvaluationMetric) (models.Eval
 	if input.IsPrimary && weight <= 0 {
 		return models.EvaluationMetric{}, fmt.Errorf("primary metric weight must be > 0: %w", ErrInvalid)
 	}
-	if err := ensureValidJSON(input.Params, "params"); err != nil {
+	params, err := validateMetricParams(metricType, input.Params)
+	if err != nil {
 		return models.EvaluationMetric{}, err
 	}
 
@@ -308,7 +323,7 @@ func buildMetric(hackathonID string, input models.EvaluationMetric) (models.Eval
 		TargetVariable: targetVariable,
 		Weight:         weight,
 		Description:    input.Description,
-		Params:         normalizeMetadata(input.Params),
+		Params:         params,
 		IsPrimary:      input.IsPrimary,
 		CreatedAt:      now,
 		UpdatedAt:      now,
@@ -346,49 +361,8 @@ func isAllowedScope(value string) bool {
 	}
 }
 
-var allowedMetricTypes = map[string]struct{}{
-	"mae":               {},
-	"mse":               {},
-	"rmse":              {},
-	"rmsle":             {},
-	"mape":              {},
-	"smape":             {},
-	"r2":                {},
-	"accuracy":          {},
-	"balanced_accuracy": {},
-	"precision":         {},
-	"recall":            {},
-	"f1":                {},
-	"f_beta":            {},
-	"roc_auc":           {},
-	"pr_auc":            {},
-	"log_loss":          {},
-	"mcc":               {},
-	"kappa":             {},
-	"top_k_accuracy":    {},
-	"map":               {},
-	"map50":             {},
-	"map50_95":          {},
-	"ndcg":              {},
-	"mrr":               {},
-	"iou":               {},
-	"dice":              {},
-	"pixel_accuracy":    {},
-	"bleu":              {},
-	"rouge":             {},
-	"wer":               {},
-	"cer":               {},
-	"perplexity":        {},
-	"psnr":              {},
-	"ssim":              {},
-	"fid":               {},
-	"lpips":             {},
-	"clip_score":        {},
-	"custom":            {},
-}
-
 func isAllowedMetricType(value string) bool {
-	_, ok := allowedMetricTypes[value]
+	_, ok := metricTypeRegistry[value]
 	return ok
 }
 
@@ -53,7 +53,7 @@ func TestBuildMetricDefaults(t *testing.T) {
 		Name:       "  RMSE ",
 		MetricType: " RMSE ",
 		Direction:  " MINIMIZE ",
-		Params:     json.RawMessage(`{"alpha":0.5}`),
+		Params:     json.RawMessage(`{}`),
 	})
 	if err != nil {
 		t.Fatalf("unexpected buildMetric error: %v", err)
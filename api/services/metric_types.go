@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/jsonschema"
+)
+
+func bound(v float64) *float64 { return &v }
+
+// Ranges of bounded metric types. r2 is unbounded below; lower values are
+// clamped to 0 in the composite score.
+var (
+	unitRange   = []float64{0, 1}
+	signedRange = []float64{-1, 1}
+)
+
+var (
+	averageParam = func(def string, values ...string) models.MetricParamSpec {
+		return models.MetricParamSpec{Name: "average", Type: models.MetricParamString, Default: def, Enum: values, Description: "How per-class scores are averaged"}
+	}
+	posLabelParam    = models.MetricParamSpec{Name: "pos_label", Type: models.MetricParamString, Description: "Positive class of binary labels; required unless they are 0/1 or true/false"}
+	cutoffParam      = models.MetricParamSpec{Name: "k", Type: models.MetricParamInteger, Minimum: bound(1), Description: "Cutoff rank; all results when unset"}
+	thresholdParam   = models.MetricParamSpec{Name: "threshold", Type: models.MetricParamNumber, Default: 0.5, Minimum: bound(0), Maximum: bound(1), Description: "Probability threshold applied to predicted masks"}
+	ignoreIndexParam = models.MetricParamSpec{Name: "ignore_index", Type: models.MetricParamInteger, Description: "Label excluded from the score"}
+	dataRangeParam   = models.MetricParamSpec{Name: "data_range", Type: models.MetricParamNumber, Default: 255.0, Minimum: bound(0), Description: "Value range of the images"}
+	lowercaseParam   = models.MetricParamSpec{Name: "lowercase", Type: models.MetricParamBoolean, Default: false, Description: "Lowercase text before scoring"}
+)
+
+// metricTypeRegistry holds the params schema of every supported metric_type.
+var metricTypeRegistry = map[string]models.MetricTypeSpec{
+	"mae":               {Direction: models.MetricDirectionMinimize},
+	"mse":               {Direction: models.MetricDirectionMinimize},
+	"rmse":              {Direction: models.MetricDirectionMinimize},
+	"rmsle":             {Direction: models.MetricDirectionMinimize},
+	"mape":              {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{{Name: "epsilon", Type: models.MetricParamNumber, Default: 1e-8, Minimum: bound(0), Description: "Floor for the denominator"}}},
+	"smape":             {Direction: models.MetricDirectionMinimize},
+	"r2":                {Direction: models.MetricDirectionMaximize, Range: unitRange},
+	"accuracy":          {Direction: models.MetricDirectionMaximize, Range: unitRange},
+	"balanced_accuracy": {Direction: models.MetricDirectionMaximize, Range: unitRange},
+	"precision":         {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{averageParam("binary", "binary", "micro", "macro", "weighted"), posLabelParam}},
+	"recall":            {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{averageParam("binary", "binary", "micro", "macro", "weighted"), posLabelParam}},
+	"f1":                {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{averageParam("binary", "binary", "micro", "macro", "weighted"), posLabelParam}},
+	"f_beta": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		{Name: "beta", Type: models.MetricParamNumber, Required: true, Minimum: bound(0), Description: "Weight of recall relative to precision"},
+		averageParam("binary", "binary", "micro", "macro", "weighted"),
+		posLabelParam,
+	}},
+	"roc_auc": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		averageParam("macro", "micro", "macro", "weighted"),
+		{Name: "multi_class", Type: models.MetricParamString, Default: "ovr", Enum: []string{"ovr", "ovo"}, Description: "Multiclass strategy"},
+		posLabelParam,
+	}},
+	"pr_auc": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{averageParam("macro", "micro", "macro", "weighted"), posLabelParam}},
+	"log_loss": {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{
+		{Name: "eps", Type: models.MetricParamNumber, Default: 1e-15, Minimum: bound(0), Maximum: bound(0.5), Description: "Probability clipping"},
+		posLabelParam,
+	}},
+	"mcc": {Direction: models.MetricDirectionMaximize, Range: signedRange},
+	"kappa": {Direction: models.MetricDirectionMaximize, Range: signedRange, Params: []models.MetricParamSpec{
+		{Name: "weights", Type: models.MetricParamString, Default: "none", Enum: []string{"none", "linear", "quadratic"}, Description: "Disagreement weighting"},
+	}},
+	"top_k_accuracy": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		{Name: "k", Type: models.MetricParamInteger, Required: true, Minimum: bound(1), Description: "Number of top predictions considered"},
+	}},
+	"map":   {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{cutoffParam}},
+	"map50": {Direction: models.MetricDirectionMaximize, Range: unitRange},
+	"map50_95": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		{Name: "iou_range", Type: models.MetricParamNumberRange, Default: []float64{0.5, 0.95}, Minimum: bound(0), Maximum: bound(1), Description: "Lowest and highest IoU threshold"},
+		{Name: "iou_step", Type: models.MetricParamNumber, Default: 0.05, Minimum: bound(0.01), Maximum: bound(0.5), Description: "Step between IoU thresholds"},
+	}},
+	"ndcg":           {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{cutoffParam}},
+	"mrr":            {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{cutoffParam}},
+	"iou":            {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{thresholdParam, ignoreIndexParam}},
+	"dice":           {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{thresholdParam, ignoreIndexParam}},
+	"pixel_accuracy": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{ignoreIndexParam}},
+	"bleu": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		{Name: "max_ngram", Type: models.MetricParamInteger, Default: 4, Minimum: bound(1), Maximum: bound(4), Description: "Largest n-gram order"},
+		{Name: "smoothing", Type: models.MetricParamBoolean, Default: false, Description: "Apply add-one smoothing"},
+	}},
+	"rouge": {Direction: models.MetricDirectionMaximize, Range: unitRange, Params: []models.MetricParamSpec{
+		{Name: "variant", Type: models.MetricParamString, Default: "rougeL", Enum: []string{"rouge1", "rouge2", "rougeL", "rougeLsum"}, Description: "ROUGE variant"},
+	}},
+	"wer":        {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{lowercaseParam}},
+	"cer":        {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{lowercaseParam}},
+	"perplexity": {Direction: models.MetricDirectionMinimize},
+	"psnr":       {Direction: models.MetricDirectionMaximize, Params: []models.MetricParamSpec{dataRangeParam}},
+	"ssim": {Direction: models.MetricDirectionMaximize, Range: signedRange, Params: []models.MetricParamSpec{
+		dataRangeParam,
+		{Name: "win_size", Type: models.MetricParamInteger, Default: 7, Minimum: bound(3), Description: "Side of the sliding window"},
+	}},
+	"fid": {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{
+		{Name: "feature_dim", Type: models.MetricParamString, Default: "2048", Enum: []string{"64", "192", "768", "2048"}, Description: "Inception feature layer"},
+	}},
+	"lpips": {Direction: models.MetricDirectionMinimize, Params: []models.MetricParamSpec{
+		{Name: "net", Type: models.MetricParamString, Default: "alex", Enum: []string{"alex", "vgg", "squeeze"}, Description: "Backbone network"},
+	}},
+	"clip_score": {Direction: models.MetricDirectionMaximize, Range: []float64{0, 100}, Params: []models.MetricParamSpec{
+		{Name: "model", Type: models.MetricParamString, Default: "ViT-B/32", Description: "CLIP model name"},
+	}},
+	"custom": {FreeForm: true},
+}
+
+// MetricTypes lists the supported metric types with their params schema,
+// sorted by type.
+func (s *MetricService) MetricTypes() []models.MetricTypeSpec {
+	out := make([]models.MetricTypeSpec, 0, len(metricTypeRegistry))
+	for name, spec := range metricTypeRegistry {
+		spec.Type = name
+		if spec.Params == nil {
+			spec.Params = []models.MetricParamSpec{}
+		}
+		out = append(out, spec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out
+}
+
+// validateMetricParams checks params against the schema of metricType and
+// returns them with defaults filled in. Violations are reported per key.
+func validateMetricParams(metricType string, raw json.RawMessage) (json.RawMessage, error) {
+	spec, ok := metricTypeRegistry[metricType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric_type: %w", ErrInvalid)
+	}
+	params := map[string]any{}
+	if len(bytes.TrimSpace(raw)) > 0 && string(bytes.TrimSpace(raw)) != "null" {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&params); err != nil {
+			return nil, &FieldErrors{Message: "invalid params", Errors: []jsonschema.Error{{Path: "/params", Message: "must be a JSON object"}}}
+		}
+	}
+	if spec.FreeForm {
+		return normalizeMetadata(raw), nil
+	}
+
+	var errs []jsonschema.Error
+	known := make(map[string]bool, len(spec.Params))
+	for _, p := range spec.Params {
+		known[p.Name] = true
+		path := "/params/" + escapeJSONPointer(p.Name)
+		value, present := params[p.Name]
+		if !present || value == nil {
+			if p.Required {
+				errs = append(errs, jsonschema.Error{Path: path, Message: "is required for " + metricType})
+			} else if p.Default != nil {
+				params[p.Name] = p.Default
+			} else {
+				delete(params, p.Name)
+			}
+			continue
+		}
+		if msg := checkMetricParam(p, value); msg != "" {
+			errs = append(errs, jsonschema.Error{Path: path, Message: msg})
+		}
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			errs = append(errs, jsonschema.Error{Path: "/params/" + escapeJSONPointer(name), Message: "is not a parameter of " + metricType})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &FieldErrors{Message: "invalid params for metric_type " + metricType, Errors: errs}
+	}
+
+	out, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params: %w", ErrInvalid)
+	}
+	return out, nil
+}
+
+// checkMetricParam returns a message describing why value does not match p,
+// or "" when it does.
+func checkMetricParam(p models.MetricParamSpec, value any) string {
+	switch p.Type {
+	case models.MetricParamNumber, models.MetricParamInteger:
+		n, ok := paramNumber(value)
+		if !ok {
+			return "must be a number"
+		}
+		if p.Type == models.MetricParamInteger && n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		return checkParamBounds(p, n)
+	case models.MetricParamBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case models.MetricParamString:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(p.Enum) > 0 {
+			for _, allowed := range p.Enum {
+				if s == allowed {
+					return ""
+				}
+			}
+			return fmt.Sprintf("must be one of %v", p.Enum)
+		}
+	case models.MetricParamNumberRange:
+		items, ok := value.([]any)
+		if !ok || len(items) != 2 {
+			return "must be an array of two numbers"
+		}
+		lo, okLo := paramNumber(items[0])
+		hi, okHi := paramNumber(items[1])
+		if !okLo || !okHi {
+			return "must be an array of two numbers"
+		}
+		if lo > hi {
+			return "lower bound must not exceed upper bound"
+		}
+		if msg := checkParamBounds(p, lo); msg != "" {
+			return msg
+		}
+		return checkParamBounds(p, hi)
+	}
+	return ""
+}
+
+func checkParamBounds(p models.MetricParamSpec, n float64) string {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return "must be finite"
+	}
+	if p.Minimum != nil && n < *p.Minimum {
+		return fmt.Sprintf("must be >= %v", *p.Minimum)
+	}
+	if p.Maximum != nil && n > *p.Maximum {
+		return fmt.Sprintf("must be <= %v", *p.Maximum)
+	}
+	return ""
+}
+
+func paramNumber(value any) (float64, bool) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return numericFrom(value)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidateMetricParamsAppliesDefaults(t *testing.T) {
+	raw, err := validateMetricParams("map50_95", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var params map[string]any
+	if err := json.Unmarshal(raw, &params); err != nil {
+		t.Fatalf("decode params: %v", err)
+	}
+	if r, ok := params["iou_range"].([]any); !ok || len(r) != 2 || r[0] != 0.5 || r[1] != 0.95 {
+		t.Fatalf("expected default iou_range, got=%v", params["iou_range"])
+	}
+	if params["iou_step"] != 0.05 {
+		t.Fatalf("expected default iou_step, got=%v", params["iou_step"])
+	}
+
+	raw, err = validateMetricParams("f_beta", json.RawMessage(`{"beta":2}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != `{"average":"binary","beta":2}` {
+		t.Fatalf("unexpected params: %s", raw)
+	}
+}
+
+func TestValidateMetricParamsRejectsInvalid(t *testing.T) {
+	cases := []struct {
+		metricType string
+		params     string
+		path       string
+	}{
+		{"f_beta", `{}`, "/params/beta"},
+		{"f_beta", `{"beta":-1}`, "/params/beta"},
+		{"top_k_accuracy", `null`, "/params/k"},
+		{"top_k_accuracy", `{"k":2.5}`, "/params/k"},
+		{"map50_95", `{"iou_range":"0.5:0.95"}`, "/params/iou_range"},
+		{"map50_95", `{"iou_range":[0.9,0.5]}`, "/params/iou_range"},
+		{"map50_95", `{"iou_range":[0.5,1.5]}`, "/params/iou_range"},
+		{"kappa", `{"weights":"cubic"}`, "/params/weights"},
+		{"wer", `{"lowercase":"yes"}`, "/params/lowercase"},
+		{"rmse", `{"alpha":0.5}`, "/params/alpha"},
+		{"rmse", `[1,2]`, "/params"},
+	}
+	for _, tc := range cases {
+		_, err := validateMetricParams(tc.metricType, json.RawMessage(tc.params))
+		var fe *FieldErrors
+		if !errors.As(err, &fe) || !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%s %s: expected field errors, got=%v", tc.metricType, tc.params, err)
+		}
+		if len(fe.Errors) != 1 || fe.Errors[0].Path != tc.path {
+			t.Fatalf("%s %s: expected error at %s, got=%+v", tc.metricType, tc.params, tc.path, fe.Errors)
+		}
+	}
+}
+
+func TestValidateMetricParamsCustomIsFreeForm(t *testing.T) {
+	raw, err := validateMetricParams("custom", json.RawMessage(`{"script":"score.py","n":3}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(raw) != `{"script":"score.py","n":3}` {
+		t.Fatalf("custom params should pass through, got=%s", raw)
+	}
+	if _, err := validateMetricParams("nope", nil); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for unknown type, got=%v", err)
+	}
+}
+
+func TestMetricTypesSortedWithParams(t *testing.T) {
+	types := (&MetricService{}).MetricTypes()
+	if len(types) != len(metricTypeRegistry) {
+		t.Fatalf("expected %d types, got=%d", len(metricTypeRegistry), len(types))
+	}
+	for i := 1; i < len(types); i++ {
+		if types[i-1].Type >= types[i].Type {
+			t.Fatalf("types not sorted at %d: %s >= %s", i, types[i-1].Type, types[i].Type)
+		}
+	}
+	for _, spec := range types {
+		if spec.Params == nil {
+			t.Fatalf("%s: params should be an empty list, not nil", spec.Type)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// PageRequest asks for one page of a list. Cursor is the next_cursor of the
+// previous page, empty for the first one. Offset is the deprecated way to
+// skip items and is only used without a cursor.
+type PageRequest struct {
+	Limit  int
+	Cursor string
+	Offset int
+}
+
+// pageCursor is the keyset position after the last item of a page. It is
+// handed out base64-encoded so clients treat it as opaque.
+type pageCursor struct {
+	At time.Time `json:"t"`
+	ID string    `json:"id"`
+}
+
+func encodeCursor(at time.Time, id string) string {
+	raw, _ := json.Marshal(pageCursor{At: at.UTC(), ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(raw string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", ErrInvalid)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.At.IsZero() {
+		return nil, fmt.Errorf("invalid cursor: %w", ErrInvalid)
+	}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", ErrInvalid)
+	}
+	return &c, nil
+}
+
+// listQuery collects the WHERE conditions of a list query with numbered
+// parameters.
+type listQuery struct {
+	conds []string
+	args  []any
+}
+
+// where adds a condition; every %s in format is replaced by a placeholder for
+// the matching arg.
+func (q *listQuery) where(format string, args ...any) {
+	q.conds = append(q.conds, q.expr(format, args...))
+}
+
+// expr adds args without a condition and returns format with their
+// placeholders, for expressions used outside the WHERE clause.
+func (q *listQuery) expr(format string, args ...any) string {
+	placeholders := make([]any, len(args))
+	for i, arg := range args {
+		q.args = append(q.args, arg)
+		placeholders[i] = "$" + strconv.Itoa(len(q.args))
+	}
+	return fmt.Sprintf(format, placeholders...)
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// keyset describes a list sorted by a timestamp column with the id as tie
+// breaker, newest first unless asc is set.
+type keyset struct {
+	columns string
+	from    string
+	at      string
+	id      string
+	asc     bool
+}
+
+// queryPage runs a keyset-paginated list query: the total over the filters
+// in q, then up to page.Limit items after page.Cursor, or after skipping
+// page.Offset items when there is no cursor.
+func queryPage[T any](ctx context.Context, db *sql.DB, ks keyset, q listQuery, page PageRequest, scan func(rowScanner) (*T, error), key func(*T) (time.Time, string)) (*models.Page[T], error) {
+	out := &models.Page[T]{Items: []T{}}
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+ks.from+q.whereClause(), q.args...).Scan(&out.Total); err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	idCol := ks.id
+	if idCol == "" {
+		idCol = "id"
+	}
+	order, cmp := "DESC", "<"
+	if ks.asc {
+		order, cmp = "ASC", ">"
+	}
+	if page.Cursor != "" {
+		cursor, err := decodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		q.where("("+ks.at+", "+idCol+") "+cmp+" (%s, %s)", cursor.At, cursor.ID)
+	}
+	// One extra row tells whether there is a next page.
+	q.args = append(q.args, page.Limit+1)
+	limit := ` LIMIT $` + strconv.Itoa(len(q.args))
+	if page.Cursor == "" && page.Offset > 0 {
+		q.args = append(q.args, page.Offset)
+		limit += ` OFFSET $` + strconv.Itoa(len(q.args))
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+ks.columns+`
+		FROM `+ks.from+q.whereClause()+`
+		ORDER BY `+ks.at+` `+order+`, `+idCol+` `+order+limit, q.args...)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		out.Items = append(out.Items, *item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if len(out.Items) > page.Limit {
+		out.Items = out.Items[:page.Limit]
+		at, id := key(&out.Items[page.Limit-1])
+		next := encodeCursor(at, id)
+		out.NextCursor = &next
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 123456789, time.FixedZone("CET", 3600))
+	id := "5f0c2b6e-8d1a-4c3e-9b7a-2e4f6a8c0d1e"
+	c, err := decodeCursor(encodeCursor(at, id))
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+	if !c.At.Equal(at) || c.ID != id {
+		t.Fatalf("unexpected cursor: %+v", c)
+	}
+
+	for _, raw := range []string{"!!", "bm90IGpzb24", encodeCursor(time.Time{}, id), encodeCursor(at, ""), encodeCursor(at, "abc")} {
+		if _, err := decodeCursor(raw); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("decodeCursor(%q): expected ErrInvalid, got %v", raw, err)
+		}
+	}
+}
+
+func TestListQueryNumbersPlaceholders(t *testing.T) {
+	var q listQuery
+	if q.whereClause() != "" {
+		t.Fatalf("expected empty where clause, got %q", q.whereClause())
+	}
+	q.where("hackathon_id = %s", "h1")
+	q.where("created_at BETWEEN %s AND %s", 1, 2)
+	if got, want := q.whereClause(), " WHERE hackathon_id = $1 AND created_at BETWEEN $2 AND $3"; got != want {
+		t.Fatalf("whereClause = %q, want %q", got, want)
+	}
+	if len(q.args) != 3 || q.args[0] != "h1" || q.args[2] != 2 {
+		t.Fatalf("unexpected args: %v", q.args)
+	}
+}
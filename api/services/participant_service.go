@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
+	"github.com/google/uuid"
+)
+
+type ParticipantService struct {
+	DB     *sql.DB
+	Events events.Publisher
+}
+
+func NewParticipantService(db *sql.DB, publisher events.Publisher) *ParticipantService {
+	return &ParticipantService{DB: db, Events: publisher}
+}
+
+type RegistrationInput struct {
+	RuleVersionID string `json:"rule_version_id"`
+	AcceptRules   bool   `json:"accept_rules"`
+	ContentHash   string `json:"content_hash,omitempty"`
+}
+
+// Register enrolls userID in a hackathon. The hackathon row is locked for the
+// duration of the transaction so the capacity check cannot be raced.
+func (s *ParticipantService) Register(ctx context.Context, hackathonID, userID string, input RegistrationInput) (*models.Participant, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("authenticated user required: %w", ErrForbidden)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var state, visibility string
+	var activeRuleVersionID sql.NullString
+	var maxParticipants int
+	err = tx.QueryRowContext(ctx, `
+		SELECT state, visibility, active_rule_version_id, max_participants
+		FROM hackathons WHERE id = $1
+		FOR UPDATE`, hackathonID).Scan(&state, &visibility, &activeRuleVersionID, &maxParticipants)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	if !isRegistrationOpen(state) {
+		return nil, fmt.Errorf("registration closed in state %s: %w", state, ErrInvalid)
+	}
+	if !activeRuleVersionID.Valid {
+		return nil, fmt.Errorf("active rule version required before registration: %w", ErrInvalid)
+	}
+	if visibility != models.HackathonVisibilityPublic {
+		member, err := isHackathonMember(ctx, tx, hackathonID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !member {
+			return nil, fmt.Errorf("an invite is required to register: %w", ErrForbidden)
+		}
+	}
+	if !input.AcceptRules || input.RuleVersionID != activeRuleVersionID.String {
+		return nil, fmt.Errorf("acceptance of active rule version %s is required: %w", activeRuleVersionID.String, ErrInvalid)
+	}
+
+	existing, err := loadParticipant(ctx, tx, hackathonID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == models.ParticipantStatusRegistered {
+		return nil, fmt.Errorf("already registered: %w", ErrConflict)
+	}
+
+	if maxParticipants > 0 {
+		var registered int
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM hackathon_participants
+			WHERE hackathon_id = $1 AND status = $2`, hackathonID, models.ParticipantStatusRegistered).Scan(&registered); err != nil {
+			return nil, mapSQLError(err)
+		}
+		if registered >= maxParticipants {
+			return nil, fmt.Errorf("hackathon is full (%d participants): %w", maxParticipants, ErrConflict)
+		}
+	}
+
+	acceptance, accepted, err := recordRuleAcceptance(ctx, tx, hackathonID, activeRuleVersionID.String, userID, input.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	p := models.Participant{
+		ID:              uuid.NewString(),
+		HackathonID:     hackathonID,
+		UserID:          userID,
+		Status:          models.ParticipantStatusRegistered,
+		RuleVersionID:   activeRuleVersionID.String,
+		RulesAcceptedAt: acceptance.AcceptedAt,
+		RegisteredAt:    now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if existing != nil {
+		p.ID = existing.ID
+		p.CreatedAt = existing.CreatedAt
+		_, err = tx.ExecContext(ctx, `
+			UPDATE hackathon_participants
+			SET status = $1, rule_version_id = $2, rules_accepted_at = $3, registered_at = $4,
+			    withdrawn_at = NULL, updated_at = $4
+			WHERE id = $5`, p.Status, p.RuleVersionID, p.RulesAcceptedAt, now, p.ID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO hackathon_participants (
+				id, hackathon_id, user_id, status, rule_version_id,
+				rules_accepted_at, registered_at, created_at, updated_at
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+			p.ID, p.HackathonID, p.UserID, p.Status, p.RuleVersionID,
+			p.RulesAcceptedAt, p.RegisteredAt, p.CreatedAt, p.UpdatedAt,
+		)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+
+	evts := []domainEvent{{"hackathon.participant.registered", participantEventPayload(&p)}}
+	if accepted {
+		evts = append(evts, domainEvent{"hackathon.rule.accepted", ruleAcceptanceEventPayload(acceptance)})
+	}
+	if err := publishInTx(ctx, tx, s.Events, evts...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *ParticipantService) Withdraw(ctx context.Context, hackathonID, userID string) (*models.Participant, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var state string
+	err = tx.QueryRowContext(ctx, `SELECT state FROM hackathons WHERE id = $1`, hackathonID).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	if isStateAtLeast(state, models.HackathonStateSubmissionFrozen) {
+		return nil, fmt.Errorf("cannot withdraw in state %s: %w", state, ErrInvalid)
+	}
+
+	p, err := loadParticipant(ctx, tx, hackathonID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.Status != models.ParticipantStatusRegistered {
+		return nil, fmt.Errorf("participant not registered: %w", ErrNotFound)
+	}
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE hackathon_participants
+		SET status = $1, withdrawn_at = $2, updated_at = $2
+		WHERE id = $3`, models.ParticipantStatusWithdrawn, now, p.ID); err != nil {
+		return nil, mapSQLError(err)
+	}
+	p.Status = models.ParticipantStatusWithdrawn
+	p.WithdrawnAt = &now
+	p.UpdatedAt = now
+
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"hackathon.participant.withdrawn", participantEventPayload(p)}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *ParticipantService) Get(ctx context.Context, hackathonID, userID string) (*models.Participant, error) {
+	return loadParticipant(ctx, s.DB, hackathonID, userID, false)
+}
+
+func (s *ParticipantService) List(ctx context.Context, hackathonID, status string, page PageRequest) (*models.Page[models.Participant], error) {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	if status != "" {
+		q.where("status = %s", status)
+	}
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, user_id, status, rule_version_id,
+		       rules_accepted_at, registered_at, withdrawn_at, created_at, updated_at`,
+		from: "hackathon_participants",
+		at:   "registered_at",
+		asc:  true,
+	}, q, page, func(row rowScanner) (*models.Participant, error) {
+		var p models.Participant
+		if err := row.Scan(
+			&p.ID, &p.HackathonID, &p.UserID, &p.Status, &p.RuleVersionID,
+			&p.RulesAcceptedAt, &p.RegisteredAt, &p.WithdrawnAt, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		return &p, nil
+	}, func(p *models.Participant) (time.Time, string) { return p.RegisteredAt, p.ID })
+}
+
+func loadParticipant(ctx context.Context, q rowQuerier, hackathonID, userID string, lock bool) (*models.Participant, error) {
+	query := `
+		SELECT id, hackathon_id, user_id, status, rule_version_id,
+		       rules_accepted_at, registered_at, withdrawn_at, created_at, updated_at
+		FROM hackathon_participants
+		WHERE hackathon_id = $1 AND user_id = $2`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var p models.Participant
+	err := q.QueryRowContext(ctx, query, hackathonID, userID).Scan(
+		&p.ID, &p.HackathonID, &p.UserID, &p.Status, &p.RuleVersionID,
+		&p.RulesAcceptedAt, &p.RegisteredAt, &p.WithdrawnAt, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	return &p, nil
+}
+
+// ensureRegisteredParticipant rejects users that are not currently registered
+// for the hackathon.
+func ensureRegisteredParticipant(ctx context.Context, q rowQuerier, hackathonID, userID string) error {
+	p, err := loadParticipant(ctx, q, hackathonID, userID, false)
+	if err != nil {
+		return err
+	}
+	if p == nil || p.Status != models.ParticipantStatusRegistered {
+		return fmt.Errorf("user is not registered for this hackathon: %w", ErrForbidden)
+	}
+	return nil
+}
+
+func isRegistrationOpen(state string) bool {
+	return state == models.HackathonStatePublished || state == models.HackathonStateWarmup
+}
+
+func participantEventPayload(p *models.Participant) map[string]any {
+	return map[string]any{
+		"hackathon_id":    p.HackathonID,
+		"participant_id":  p.ID,
+		"user_id":         p.UserID,
+		"status":          p.Status,
+		"rule_version_id": p.RuleVersionID,
+	}
+}
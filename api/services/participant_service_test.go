@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestIsRegistrationOpen(t *testing.T) {
+	cases := map[string]bool{
+		models.HackathonStateDraft:            false,
+		models.HackathonStatePublished:        true,
+		models.HackathonStateWarmup:           true,
+		models.HackathonStateLive:             false,
+		models.HackathonStateSubmissionFrozen: false,
+		models.HackathonStateCompleted:        false,
+	}
+	for state, want := range cases {
+		if got := isRegistrationOpen(state); got != want {
+			t.Fatalf("state %s: want %v got %v", state, want, got)
+		}
+	}
+}
+
+func TestParticipantEventPayload(t *testing.T) {
+	p := &models.Participant{
+		ID:            "p-1",
+		HackathonID:   "h-1",
+		UserID:        "u-1",
+		Status:        models.ParticipantStatusRegistered,
+		RuleVersionID: "rv-1",
+	}
+	payload := participantEventPayload(p)
+	if payload["hackathon_id"] != "h-1" || payload["user_id"] != "u-1" || payload["rule_version_id"] != "rv-1" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
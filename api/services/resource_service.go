@@ -50,29 +50,22 @@ func (s *ResourceService) Create(ctx context.Context, hackathonID string, input
 	return &res, nil
 }
 
-func (s *ResourceService) List(ctx context.Context, hackathonID string, limit, offset int) ([]models.Resource, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, type, title, url, metadata, created_at
-		FROM resources
-		WHERE hackathon_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.Resource
-	for rows.Next() {
+func (s *ResourceService) List(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.Resource], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, type, title, url, metadata, created_at`,
+		from:    "resources",
+		at:      "created_at",
+	}, q, page, func(row rowScanner) (*models.Resource, error) {
 		var r models.Resource
 		var metadata []byte
-		if err := rows.Scan(&r.ID, &r.HackathonID, &r.Type, &r.Title, &r.URL, &metadata, &r.CreatedAt); err != nil {
-			return nil, mapSQLError(err)
+		if err := row.Scan(&r.ID, &r.HackathonID, &r.Type, &r.Title, &r.URL, &metadata, &r.CreatedAt); err != nil {
+			return nil, err
 		}
 		r.Metadata = metadata
-		items = append(items, r)
-	}
-	return items, nil
+		return &r, nil
+	}, func(r *models.Resource) (time.Time, string) { return r.CreatedAt, r.ID })
 }
 
 func (s *ResourceService) GetByID(ctx context.Context, hackathonID, resourceID string) (*models.Resource, error) {
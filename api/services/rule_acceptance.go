@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type RuleAcceptanceInput struct {
+	RuleVersionID string `json:"rule_version_id,omitempty"`
+	ContentHash   string `json:"content_hash,omitempty"`
+}
+
+// AcceptRules records that userID agreed to the hackathon's active rule
+// version. Accepting the same version twice returns the original entry.
+func (s *RuleService) AcceptRules(ctx context.Context, hackathonID, userID string, input RuleAcceptanceInput) (*models.RuleAcceptance, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("authenticated user required: %w", ErrForbidden)
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	activeRuleVersionID, err := loadActiveRuleVersionID(ctx, tx, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+	if activeRuleVersionID == "" {
+		return nil, fmt.Errorf("hackathon has no active rule version: %w", ErrInvalid)
+	}
+	if input.RuleVersionID != "" && input.RuleVersionID != activeRuleVersionID {
+		return nil, fmt.Errorf("only the active rule version %s can be accepted: %w", activeRuleVersionID, ErrInvalid)
+	}
+
+	acceptance, created, err := recordRuleAcceptance(ctx, tx, hackathonID, activeRuleVersionID, userID, input.ContentHash)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		if err := publishInTx(ctx, tx, s.Events, domainEvent{"hackathon.rule.accepted", ruleAcceptanceEventPayload(acceptance)}); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return acceptance, nil
+}
+
+// AcceptanceReport lists, for each locked rule version of the hackathon (or
+// only ruleVersionID when set), who accepted it and which registered
+// participants have not.
+func (s *RuleService) AcceptanceReport(ctx context.Context, hackathonID, ruleVersionID string) ([]models.RuleAcceptanceReport, error) {
+	activeRuleVersionID, err := loadActiveRuleVersionID(ctx, s.DB, hackathonID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT rv.id, rv.rule_id, rv.version, rv.content
+		FROM rule_versions rv
+		JOIN rules r ON rv.rule_id = r.id
+		WHERE r.hackathon_id = $1 AND rv.status = $2 AND ($3 = '' OR rv.id::text = $3)
+		ORDER BY r.created_at, rv.version`, hackathonID, models.RuleStatusLocked, ruleVersionID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	var reports []models.RuleAcceptanceReport
+	for rows.Next() {
+		var r models.RuleAcceptanceReport
+		var content []byte
+		if err := rows.Scan(&r.RuleVersionID, &r.RuleID, &r.Version, &content); err != nil {
+			rows.Close()
+			return nil, mapSQLError(err)
+		}
+		r.ContentHash = ruleContentHash(content)
+		r.Active = r.RuleVersionID == activeRuleVersionID
+		reports = append(reports, r)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if ruleVersionID != "" && len(reports) == 0 {
+		return nil, fmt.Errorf("locked rule version not found for hackathon: %w", ErrNotFound)
+	}
+
+	for i := range reports {
+		accepted, err := s.listRuleAcceptances(ctx, hackathonID, reports[i].RuleVersionID)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := s.listPendingAcceptances(ctx, hackathonID, reports[i].RuleVersionID)
+		if err != nil {
+			return nil, err
+		}
+		reports[i].Accepted = accepted
+		reports[i].Pending = pending
+	}
+	return reports, nil
+}
+
+func (s *RuleService) listRuleAcceptances(ctx context.Context, hackathonID, ruleVersionID string) ([]models.RuleAcceptance, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT id, hackathon_id, rule_version_id, user_id, content_hash, accepted_at
+		FROM rule_acceptances
+		WHERE hackathon_id = $1 AND rule_version_id = $2
+		ORDER BY accepted_at`, hackathonID, ruleVersionID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	items := []models.RuleAcceptance{}
+	for rows.Next() {
+		var a models.RuleAcceptance
+		if err := rows.Scan(&a.ID, &a.HackathonID, &a.RuleVersionID, &a.UserID, &a.ContentHash, &a.AcceptedAt); err != nil {
+			return nil, mapSQLError(err)
+		}
+		items = append(items, a)
+	}
+	return items, nil
+}
+
+func (s *RuleService) listPendingAcceptances(ctx context.Context, hackathonID, ruleVersionID string) ([]string, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT p.user_id
+		FROM hackathon_participants p
+		WHERE p.hackathon_id = $1 AND p.status = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM rule_acceptances a
+			WHERE a.hackathon_id = p.hackathon_id AND a.user_id = p.user_id AND a.rule_version_id = $3
+		  )
+		ORDER BY p.user_id`, hackathonID, models.ParticipantStatusRegistered, ruleVersionID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	users := []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, mapSQLError(err)
+		}
+		users = append(users, userID)
+	}
+	return users, nil
+}
+
+// recordRuleAcceptance writes a ledger entry inside tx. When expectedHash is
+// set it must match the stored content, so clients prove which text they saw.
+// The returned bool is false when the user had already accepted this version.
+func recordRuleAcceptance(ctx context.Context, tx *sql.Tx, hackathonID, ruleVersionID, userID, expectedHash string) (*models.RuleAcceptance, bool, error) {
+	var status string
+	var content []byte
+	err := tx.QueryRowContext(ctx, `
+		SELECT status, content FROM rule_versions WHERE id = $1`, ruleVersionID).Scan(&status, &content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("rule version not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	if status != models.RuleStatusLocked {
+		return nil, false, fmt.Errorf("only locked rule versions can be accepted: %w", ErrInvalid)
+	}
+
+	hash := ruleContentHash(content)
+	if expectedHash != "" && !strings.EqualFold(expectedHash, hash) {
+		return nil, false, fmt.Errorf("content_hash does not match rule version %s: %w", ruleVersionID, ErrConflict)
+	}
+
+	a := models.RuleAcceptance{
+		ID:            uuid.NewString(),
+		HackathonID:   hackathonID,
+		RuleVersionID: ruleVersionID,
+		UserID:        userID,
+		ContentHash:   hash,
+		AcceptedAt:    time.Now().UTC(),
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO rule_acceptances (id, hackathon_id, rule_version_id, user_id, content_hash, accepted_at)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (hackathon_id, rule_version_id, user_id) DO NOTHING`,
+		a.ID, a.HackathonID, a.RuleVersionID, a.UserID, a.ContentHash, a.AcceptedAt,
+	)
+	if err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, err
+	}
+	if affected > 0 {
+		return &a, true, nil
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, hackathon_id, rule_version_id, user_id, content_hash, accepted_at
+		FROM rule_acceptances
+		WHERE hackathon_id = $1 AND rule_version_id = $2 AND user_id = $3`, hackathonID, ruleVersionID, userID).
+		Scan(&a.ID, &a.HackathonID, &a.RuleVersionID, &a.UserID, &a.ContentHash, &a.AcceptedAt)
+	if err != nil {
+		return nil, false, mapSQLError(err)
+	}
+	return &a, false, nil
+}
+
+// ensureRuleAccepted rejects users that have not accepted ruleVersionID, e.g.
+// after an organizer activated a new version mid-competition.
+func ensureRuleAccepted(ctx context.Context, q rowQuerier, hackathonID, ruleVersionID, userID string) error {
+	var exists bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM rule_acceptances
+			WHERE hackathon_id = $1 AND rule_version_id = $2 AND user_id = $3
+		)`, hackathonID, ruleVersionID, userID).Scan(&exists)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	if !exists {
+		return fmt.Errorf("active rule version %s must be accepted before submitting: %w", ruleVersionID, ErrForbidden)
+	}
+	return nil
+}
+
+func loadActiveRuleVersionID(ctx context.Context, q rowQuerier, hackathonID string) (string, error) {
+	var active sql.NullString
+	err := q.QueryRowContext(ctx, `SELECT active_rule_version_id FROM hackathons WHERE id = $1`, hackathonID).Scan(&active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return "", mapSQLError(err)
+	}
+	return active.String, nil
+}
+
+func ruleContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func ruleAcceptanceEventPayload(a *models.RuleAcceptance) map[string]any {
+	return map[string]any{
+		"hackathon_id":    a.HackathonID,
+		"rule_version_id": a.RuleVersionID,
+		"user_id":         a.UserID,
+		"content_hash":    a.ContentHash,
+	}
+}
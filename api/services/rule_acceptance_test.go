@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestRuleContentHash(t *testing.T) {
+	// sha256("{}")
+	const want = "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	if got := ruleContentHash([]byte(`{}`)); got != want {
+		t.Fatalf("unexpected hash: %s", got)
+	}
+	if ruleContentHash([]byte(`{"a":1}`)) == ruleContentHash([]byte(`{"a":2}`)) {
+		t.Fatal("different content must hash differently")
+	}
+}
+
+func TestRuleAcceptanceEventPayload(t *testing.T) {
+	payload := ruleAcceptanceEventPayload(&models.RuleAcceptance{
+		HackathonID:   "h-1",
+		RuleVersionID: "rv-1",
+		UserID:        "u-1",
+		ContentHash:   "abc",
+	})
+	if payload["rule_version_id"] != "rv-1" || payload["user_id"] != "u-1" || payload["content_hash"] != "abc" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+// Diff compares the content of two versions of the same rule, identified by
+// their version numbers.
+func (s *RuleService) Diff(ctx context.Context, ruleID string, from, to int) (*models.RuleVersionDiff, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("from and to must be positive version numbers: %w", ErrInvalid)
+	}
+	fromVersion, err := s.getVersionByNumber(ctx, ruleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.getVersionByNumber(ctx, ruleID, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffRuleVersions(fromVersion, toVersion)
+}
+
+// DiffVersionIDs compares two rule versions by id. The versions may belong to
+// different rules, e.g. when a hackathon switches its active rule.
+func (s *RuleService) DiffVersionIDs(ctx context.Context, fromID, toID string) (*models.RuleVersionDiff, error) {
+	return diffRuleVersionIDs(ctx, s.DB, fromID, toID)
+}
+
+func diffRuleVersionIDs(ctx context.Context, db rowQuerier, fromID, toID string) (*models.RuleVersionDiff, error) {
+	fromVersion, err := loadRuleVersion(ctx, db, fromID)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := loadRuleVersion(ctx, db, toID)
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion == nil || toVersion == nil {
+		return nil, fmt.Errorf("rule version not found: %w", ErrNotFound)
+	}
+	return diffRuleVersions(fromVersion, toVersion)
+}
+
+func (s *RuleService) getVersionByNumber(ctx context.Context, ruleID string, version int) (*models.RuleVersion, error) {
+	row := s.DB.QueryRowContext(ctx, `
+		SELECT id, rule_id, version, status, content, created_by, created_at, locked_at, dataset_version_id
+		FROM rule_versions WHERE rule_id = $1 AND version = $2`, ruleID, version)
+
+	var v models.RuleVersion
+	var content []byte
+	if err := row.Scan(&v.ID, &v.RuleID, &v.Version, &v.Status, &content, &v.CreatedBy, &v.CreatedAt, &v.LockedAt, &v.DatasetVersionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("rule version %d not found: %w", version, ErrNotFound)
+		}
+		return nil, mapSQLError(err)
+	}
+	v.Content = content
+	return &v, nil
+}
+
+func diffRuleVersions(from, to *models.RuleVersion) (*models.RuleVersionDiff, error) {
+	changes, err := diffJSON(from.Content, to.Content)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RuleVersionDiff{
+		RuleID:        to.RuleID,
+		FromVersion:   from.Version,
+		ToVersion:     to.Version,
+		FromVersionID: from.ID,
+		ToVersionID:   to.ID,
+		Changes:       changes,
+	}, nil
+}
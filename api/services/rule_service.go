@@ -9,15 +9,17 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type RuleService struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Events events.Publisher
 }
 
-func NewRuleService(db *sql.DB) *RuleService {
-	return &RuleService{DB: db}
+func NewRuleService(db *sql.DB, publisher events.Publisher) *RuleService {
+	return &RuleService{DB: db, Events: publisher}
 }
 
 func (s *RuleService) CreateRule(ctx context.Context, hackathonID string, input models.Rule, content json.RawMessage, actorID string) (*models.Rule, *models.RuleVersion, error) {
@@ -80,6 +82,13 @@ func (s *RuleService) CreateRule(ctx context.Context, hackathonID string, input
 	); err != nil {
 		return nil, nil, mapSQLError(err)
 	}
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"hackathon.rule.created", map[string]any{
+		"hackathon_id": hackathonID,
+		"rule_id":      rule.ID,
+		"version_id":   version.ID,
+	}}); err != nil {
+		return nil, nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, nil, err
@@ -104,13 +113,18 @@ func (s *RuleService) GetByID(ctx context.Context, ruleID string) (*models.Rule,
 }
 
 func (s *RuleService) GetVersionByID(ctx context.Context, versionID string) (*models.RuleVersion, error) {
-	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, rule_id, version, status, content, created_by, created_at, locked_at
+	return loadRuleVersion(ctx, s.DB, versionID)
+}
+
+// loadRuleVersion reads a rule version by id, or nil when there is none.
+func loadRuleVersion(ctx context.Context, db rowQuerier, versionID string) (*models.RuleVersion, error) {
+	row := db.QueryRowContext(ctx, `
+		SELECT id, rule_id, version, status, content, created_by, created_at, locked_at, dataset_version_id
 		FROM rule_versions WHERE id = $1`, versionID)
 
 	var v models.RuleVersion
 	var content []byte
-	if err := row.Scan(&v.ID, &v.RuleID, &v.Version, &v.Status, &content, &v.CreatedBy, &v.CreatedAt, &v.LockedAt); err != nil {
+	if err := row.Scan(&v.ID, &v.RuleID, &v.Version, &v.Status, &content, &v.CreatedBy, &v.CreatedAt, &v.LockedAt, &v.DatasetVersionID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -132,38 +146,114 @@ func (s *RuleService) LockVersion(ctx context.Context, versionID string) (*model
 		return nil, fmt.Errorf("rule version already locked: %w", ErrConflict)
 	}
 
-	now := time.Now().UTC()
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE rule_versions
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var hackathonID string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE rule_versions rv
 		SET status = $1, locked_at = $2
-		WHERE id = $3`, models.RuleStatusLocked, now, versionID)
+		FROM rules r
+		WHERE rv.id = $3 AND rv.status = $4 AND r.id = rv.rule_id
+		RETURNING r.hackathon_id`, models.RuleStatusLocked, time.Now().UTC(), versionID, models.RuleStatusDraft).Scan(&hackathonID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("rule version already locked: %w", ErrConflict)
+	}
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
+
+	// Bind the version to the data it was locked against.
+	dataset, created, err := snapshotDataset(ctx, tx, hackathonID, models.DatasetVersionReasonRuleVersionLocked, "")
+	if err != nil {
+		return nil, err
+	}
+	var evts []domainEvent
+	var datasetVersionID *string
+	if dataset != nil {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE rule_versions SET dataset_version_id = $1 WHERE id = $2`, dataset.ID, versionID); err != nil {
+			return nil, mapSQLError(err)
+		}
+		if created {
+			evts = append(evts, datasetVersionEvent(dataset))
+		}
+		datasetVersionID = &dataset.ID
+	}
+	evts = append(evts, domainEvent{"hackathon.rule.version.locked", map[string]any{
+		"rule_id":            v.RuleID,
+		"rule_version_id":    versionID,
+		"dataset_version_id": datasetVersionID,
+	}})
+	if err := publishInTx(ctx, tx, s.Events, evts...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return s.GetVersionByID(ctx, versionID)
 }
 
-func (s *RuleService) ListByHackathon(ctx context.Context, hackathonID string, limit, offset int) ([]models.Rule, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, track_id, name, description, created_at, updated_at
-		FROM rules
-		WHERE hackathon_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
+// ActivateVersion makes ruleVersionID the active rule version of the
+// hackathon. The hackathon.rule.activated event carries the changes since the
+// previously active version, computed in the same transaction.
+func (s *RuleService) ActivateVersion(ctx context.Context, hackathonID, ruleVersionID string) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, mapSQLError(err)
+		return err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var items []models.Rule
-	for rows.Next() {
-		var r models.Rule
-		if err := rows.Scan(&r.ID, &r.HackathonID, &r.TrackID, &r.Name, &r.Description, &r.CreatedAt, &r.UpdatedAt); err != nil {
-			return nil, mapSQLError(err)
+	var previous sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT active_rule_version_id FROM hackathons WHERE id = $1 FOR UPDATE`, hackathonID).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return mapSQLError(err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE hackathons SET active_rule_version_id = $1, updated_at = NOW()
+		WHERE id = $2`, ruleVersionID, hackathonID); err != nil {
+		return mapSQLError(err)
+	}
+
+	payload := map[string]any{
+		"hackathon_id":    hackathonID,
+		"rule_version_id": ruleVersionID,
+	}
+	if previous.Valid && previous.String != ruleVersionID {
+		payload["previous_rule_version_id"] = previous.String
+		diff, err := diffRuleVersionIDs(ctx, tx, previous.String, ruleVersionID)
+		if err != nil {
+			return err
 		}
-		items = append(items, r)
+		payload["changes"] = diff.Changes
+	}
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"hackathon.rule.activated", payload}); err != nil {
+		return err
 	}
-	return items, nil
+	return tx.Commit()
+}
+
+func (s *RuleService) ListByHackathon(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.Rule], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, track_id, name, description, created_at, updated_at`,
+		from:    "rules",
+		at:      "created_at",
+	}, q, page, func(row rowScanner) (*models.Rule, error) {
+		var r models.Rule
+		if err := row.Scan(&r.ID, &r.HackathonID, &r.TrackID, &r.Name, &r.Description, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		return &r, nil
+	}, func(r *models.Rule) (time.Time, string) { return r.CreatedAt, r.ID })
 }
 
 type RuleUpdateInput struct {
@@ -300,29 +390,22 @@ func (s *RuleService) CreateVersion(ctx context.Context, ruleID string, content
 	return &version, nil
 }
 
-func (s *RuleService) History(ctx context.Context, ruleID string, limit, offset int) ([]models.RuleVersion, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, rule_id, version, status, content, created_by, created_at, locked_at
-		FROM rule_versions
-		WHERE rule_id = $1
-		ORDER BY version DESC
-		LIMIT $2 OFFSET $3`, ruleID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.RuleVersion
-	for rows.Next() {
+func (s *RuleService) History(ctx context.Context, ruleID string, page PageRequest) (*models.Page[models.RuleVersion], error) {
+	var q listQuery
+	q.where("rule_id = %s", ruleID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, rule_id, version, status, content, created_by, created_at, locked_at, dataset_version_id`,
+		from:    "rule_versions",
+		at:      "created_at",
+	}, q, page, func(row rowScanner) (*models.RuleVersion, error) {
 		var v models.RuleVersion
 		var content []byte
-		if err := rows.Scan(&v.ID, &v.RuleID, &v.Version, &v.Status, &content, &v.CreatedBy, &v.CreatedAt, &v.LockedAt); err != nil {
-			return nil, mapSQLError(err)
+		if err := row.Scan(&v.ID, &v.RuleID, &v.Version, &v.Status, &content, &v.CreatedBy, &v.CreatedAt, &v.LockedAt, &v.DatasetVersionID); err != nil {
+			return nil, err
 		}
 		v.Content = content
-		items = append(items, v)
-	}
-	return items, nil
+		return &v, nil
+	}, func(v *models.RuleVersion) (time.Time, string) { return v.CreatedAt, v.ID })
 }
 
 func (s *RuleService) RuleVersionBelongsToHackathon(ctx context.Context, ruleVersionID, hackathonID string) (bool, error) {
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestActivateVersionFailsWhenDiffFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer db.Close()
+	svc := NewRuleService(db, nil)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT active_rule_version_id FROM hackathons WHERE id = \\$1 FOR UPDATE").
+		WithArgs("h1").
+		WillReturnRows(sqlmock.NewRows([]string{"active_rule_version_id"}).AddRow("v1"))
+	mock.ExpectExec("UPDATE hackathons SET active_rule_version_id").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("FROM rule_versions WHERE id = \\$1").WithArgs("v1").WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	if err := svc.ActivateVersion(context.Background(), "h1", "v2"); err == nil {
+		t.Fatal("expected the diff error to fail the activation")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
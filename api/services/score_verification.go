@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/scoring"
+)
+
+// ScoreVerificationInput carries the uploaded CSV files. SubmissionID is
+// optional and selects the submission whose reported score is compared.
+type ScoreVerificationInput struct {
+	GroundTruth  io.Reader
+	Predictions  io.Reader
+	SubmissionID string
+}
+
+// VerifyScore recomputes a metric with the reference implementation in
+// pkg/scoring. Rows are matched on the dataset's identifier variables (by
+// position when there are none) and the metric's target variable is read from
+// both files. With several identifiers the first one groups rows for ranking
+// metrics.
+func (s *MetricService) VerifyScore(ctx context.Context, hackathonID, metricID string, input ScoreVerificationInput) (*models.ScoreVerification, error) {
+	metric, err := s.GetByID(ctx, hackathonID, metricID)
+	if err != nil {
+		return nil, err
+	}
+	if metric == nil {
+		return nil, fmt.Errorf("metric not found: %w", ErrNotFound)
+	}
+	if !scoring.Supported(metric.MetricType) {
+		return nil, fmt.Errorf("no reference implementation for metric_type %s: %w", metric.MetricType, ErrInvalid)
+	}
+
+	keys, target, err := s.scoringColumns(ctx, hackathonID, metric)
+	if err != nil {
+		return nil, err
+	}
+	truth, err := readScoringCSV(input.GroundTruth, "ground_truth", keys, target)
+	if err != nil {
+		return nil, err
+	}
+	pred, err := readScoringCSV(input.Predictions, "predictions", keys, target)
+	if err != nil {
+		return nil, err
+	}
+	sample, err := alignScoringRows(truth, pred, len(keys))
+	if err != nil {
+		return nil, err
+	}
+
+	params := scoring.Params{}
+	if len(metric.Params) > 0 {
+		if err := json.Unmarshal(metric.Params, &params); err != nil {
+			return nil, fmt.Errorf("invalid metric params: %w", ErrInvalid)
+		}
+	}
+	score, err := scoring.Score(metric.MetricType, sample, params)
+	if errors.Is(err, scoring.ErrInvalidInput) || errors.Is(err, scoring.ErrUnsupported) {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrInvalid)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ScoreVerification{
+		MetricID:       metric.ID,
+		MetricName:     metric.Name,
+		MetricType:     metric.MetricType,
+		TargetVariable: target,
+		KeyColumns:     keys,
+		Rows:           len(sample.Truth),
+		Score:          score,
+	}
+	if id := strings.TrimSpace(input.SubmissionID); id != "" {
+		if err := s.compareReportedScore(ctx, hackathonID, id, metric, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// scoringColumns returns the identifier columns used to match rows and the
+// target column scored by the metric.
+func (s *MetricService) scoringColumns(ctx context.Context, hackathonID string, metric *models.EvaluationMetric) ([]string, string, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT dv.name, dv.role
+		FROM dataset_variables dv
+		JOIN hackathon_datasets hd ON dv.dataset_id = hd.id
+		WHERE hd.hackathon_id = $1 AND dv.role IN ($2, $3)
+		ORDER BY dv.created_at, dv.name`,
+		hackathonID, models.DatasetVariableRoleIdentifier, models.DatasetVariableRoleTarget)
+	if err != nil {
+		return nil, "", mapSQLError(err)
+	}
+	defer rows.Close()
+
+	var keys, targets []string
+	for rows.Next() {
+		var name, role string
+		if err := rows.Scan(&name, &role); err != nil {
+			return nil, "", mapSQLError(err)
+		}
+		if role == models.DatasetVariableRoleIdentifier {
+			keys = append(keys, name)
+		} else {
+			targets = append(targets, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", mapSQLError(err)
+	}
+
+	if metric.Scope == models.MetricScopePerTarget {
+		return uniqueStrings(keys), metric.TargetVariable, nil
+	}
+	targets = uniqueStrings(targets)
+	switch len(targets) {
+	case 0:
+		return nil, "", fmt.Errorf("dataset has no target variable to score: %w", ErrInvalid)
+	case 1:
+		return uniqueStrings(keys), targets[0], nil
+	default:
+		return nil, "", fmt.Errorf("dataset has several target variables; verify a per_target metric instead: %w", ErrInvalid)
+	}
+}
+
+// compareReportedScore fills Reported and Difference from the metric value
+// stored with the submission's evaluation result, falling back to the public
+// score for the primary metric.
+func (s *MetricService) compareReportedScore(ctx context.Context, hackathonID, submissionID string, metric *models.EvaluationMetric, result *models.ScoreVerification) error {
+	sub, err := loadSubmission(ctx, s.DB, submissionID, false)
+	if err != nil {
+		return err
+	}
+	if sub == nil || sub.HackathonID != hackathonID {
+		return fmt.Errorf("submission not found: %w", ErrNotFound)
+	}
+	result.SubmissionID = sub.ID
+	reported, ok := secondaryScores(sub.Metadata)[metric.Name]
+	if !ok && metric.IsPrimary && sub.PublicScore != nil {
+		reported, ok = *sub.PublicScore, true
+	}
+	if ok {
+		diff := result.Score - reported
+		result.Reported = &reported
+		result.Difference = &diff
+	}
+	return nil
+}
+
+type scoringRow struct {
+	key   []string
+	value string
+}
+
+// readScoringCSV reads the key and target columns of a CSV file with a
+// header row.
+func readScoringCSV(r io.Reader, field string, keys []string, target string) ([]scoringRow, error) {
+	if r == nil {
+		return nil, fmt.Errorf("%s file is required: %w", field, ErrInvalid)
+	}
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: cannot read CSV header: %w", field, ErrInvalid)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	columns := make([]int, 0, len(keys)+1)
+	for _, name := range append(append([]string{}, keys...), target) {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: missing column %q: %w", field, name, ErrInvalid)
+		}
+		columns = append(columns, i)
+	}
+
+	var out []scoringRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: line %d: %v: %w", field, line, err, ErrInvalid)
+		}
+		row := scoringRow{key: make([]string, len(keys))}
+		for i := range keys {
+			row.key[i] = strings.TrimSpace(record[columns[i]])
+		}
+		row.value = record[columns[len(keys)]]
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+// alignScoringRows pairs every ground-truth row with its prediction. Each key
+// must appear once in each file and both files must cover the same keys.
+func alignScoringRows(truth, pred []scoringRow, keyCount int) (scoring.Sample, error) {
+	var sample scoring.Sample
+	if keyCount == 0 {
+		if len(truth) != len(pred) {
+			return sample, fmt.Errorf("predictions has %d rows, ground_truth has %d: %w", len(pred), len(truth), ErrInvalid)
+		}
+		for i := range truth {
+			sample.Truth = append(sample.Truth, truth[i].value)
+			sample.Pred = append(sample.Pred, pred[i].value)
+		}
+		return sample, nil
+	}
+
+	predictions := make(map[string]string, len(pred))
+	for _, row := range pred {
+		key := strings.Join(row.key, "\x1f")
+		if _, dup := predictions[key]; dup {
+			return sample, fmt.Errorf("predictions: duplicate key %v: %w", row.key, ErrInvalid)
+		}
+		predictions[key] = row.value
+	}
+	seen := make(map[string]bool, len(truth))
+	var missing int
+	for _, row := range truth {
+		key := strings.Join(row.key, "\x1f")
+		if seen[key] {
+			return sample, fmt.Errorf("ground_truth: duplicate key %v: %w", row.key, ErrInvalid)
+		}
+		seen[key] = true
+		value, ok := predictions[key]
+		if !ok {
+			missing++
+			continue
+		}
+		sample.Truth = append(sample.Truth, row.value)
+		sample.Pred = append(sample.Pred, value)
+		if keyCount > 1 {
+			sample.Group = append(sample.Group, row.key[0])
+		}
+	}
+	if missing > 0 {
+		return sample, fmt.Errorf("predictions missing for %d ground_truth rows: %w", missing, ErrInvalid)
+	}
+	if extra := len(predictions) - len(sample.Truth); extra > 0 {
+		return sample, fmt.Errorf("predictions has %d rows not in ground_truth: %w", extra, ErrInvalid)
+	}
+	return sample, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadScoringCSVSelectsColumns(t *testing.T) {
+	rows, err := readScoringCSV(strings.NewReader("\ufeffquery, doc ,label,extra\nq1,d1,1,x\nq1,d2,0,y\n"), "ground_truth", []string{"query", "doc"}, "label")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 || !reflect.DeepEqual(rows[1].key, []string{"q1", "d2"}) || rows[1].value != "0" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	_, err = readScoringCSV(strings.NewReader("id,score\n1,0.5\n"), "predictions", []string{"id"}, "label")
+	if !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), `"label"`) {
+		t.Fatalf("expected missing column error, got=%v", err)
+	}
+	if _, err := readScoringCSV(nil, "predictions", nil, "label"); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected ErrInvalid for missing file, got=%v", err)
+	}
+}
+
+func TestAlignScoringRows(t *testing.T) {
+	truth := []scoringRow{{key: []string{"q1", "a"}, value: "1"}, {key: []string{"q2", "b"}, value: "0"}}
+	pred := []scoringRow{{key: []string{"q2", "b"}, value: "0.2"}, {key: []string{"q1", "a"}, value: "0.9"}}
+
+	sample, err := alignScoringRows(truth, pred, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sample.Pred, []string{"0.9", "0.2"}) || !reflect.DeepEqual(sample.Group, []string{"q1", "q2"}) {
+		t.Fatalf("rows not aligned: %+v", sample)
+	}
+
+	sample, err = alignScoringRows([]scoringRow{{value: "3"}}, []scoringRow{{value: "2"}}, 0)
+	if err != nil || sample.Truth[0] != "3" || sample.Pred[0] != "2" || sample.Group != nil {
+		t.Fatalf("positional alignment failed: %+v err=%v", sample, err)
+	}
+
+	cases := []struct {
+		name        string
+		truth, pred []scoringRow
+	}{
+		{"missing", truth, pred[:1]},
+		{"extra", truth[:1], pred},
+		{"duplicate prediction", truth, append(pred, pred[0])},
+		{"duplicate truth", append(truth, truth[0]), pred},
+	}
+	for _, tc := range cases {
+		if _, err := alignScoringRows(tc.truth, tc.pred, 2); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("%s: expected ErrInvalid, got=%v", tc.name, err)
+		}
+	}
+	if _, err := alignScoringRows(truth, pred[:1], 0); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("expected row count mismatch error, got=%v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/google/uuid"
+)
+
+// ListEvaluations returns every evaluation attempt of a submission, oldest
+// first, including attempts superseded by a rescore.
+func (s *SubmissionService) ListEvaluations(ctx context.Context, submissionID string) ([]models.SubmissionEvaluation, error) {
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+evaluationColumns+`
+		FROM submission_evaluations
+		WHERE submission_id = $1
+		ORDER BY attempt`, submissionID)
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	items := []models.SubmissionEvaluation{}
+	for rows.Next() {
+		ev, err := scanEvaluation(rows)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+		items = append(items, *ev)
+	}
+	return items, rows.Err()
+}
+
+// evaluationAttempt is the part of an evaluator callback stored on the
+// attempt row.
+type evaluationAttempt struct {
+	Status          string
+	JobID           string
+	Executor        string
+	Error           string
+	PublicScore     *float64
+	PrivateScore    *float64
+	CompositeScore  *float64
+	SecondaryScores map[string]float64
+	Metadata        json.RawMessage
+}
+
+// recordEvaluationAttempt opens a new attempt when evaluation starts and
+// closes the open attempt when it fails or is scored. A failure or score
+// reported without a start opens and closes an attempt at once.
+func recordEvaluationAttempt(ctx context.Context, tx *sql.Tx, sub *models.Submission, a evaluationAttempt) error {
+	now := time.Now().UTC()
+	secondary, err := json.Marshal(a.SecondaryScores)
+	if err != nil {
+		return err
+	}
+	if a.SecondaryScores == nil {
+		secondary = []byte(`{}`)
+	}
+
+	if a.Status != models.SubmissionStatusEvaluationRunning {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE submission_evaluations
+			SET status = $1, job_id = COALESCE(NULLIF($2, ''), job_id), executor = COALESCE(NULLIF($3, ''), executor),
+			    error = $4, public_score = $5, private_score = $6, composite_score = $7, secondary_scores = $8,
+			    metadata = $9, finished_at = $10, updated_at = $10
+			WHERE id = (
+				SELECT id FROM submission_evaluations
+				WHERE submission_id = $11 AND finished_at IS NULL AND superseded_at IS NULL
+				ORDER BY attempt DESC
+				LIMIT 1
+			)`,
+			a.Status, a.JobID, a.Executor, a.Error, a.PublicScore, a.PrivateScore, a.CompositeScore, secondary,
+			a.Metadata, now, sub.ID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		if n, err := res.RowsAffected(); err != nil || n > 0 {
+			return err
+		}
+	}
+
+	var startedAt, finishedAt *time.Time
+	if a.Status == models.SubmissionStatusEvaluationRunning {
+		startedAt = &now
+	} else {
+		finishedAt = &now
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO submission_evaluations (
+			id, submission_id, hackathon_id, attempt, job_id, executor, status, error,
+			public_score, private_score, composite_score, secondary_scores, metadata, started_at, finished_at,
+			created_at, updated_at
+		) VALUES (
+			$1, $2, $3,
+			(SELECT COALESCE(MAX(attempt), 0) + 1 FROM submission_evaluations WHERE submission_id = $2),
+			NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $15
+		)`,
+		uuid.NewString(), sub.ID, sub.HackathonID, a.JobID, a.Executor, a.Status, a.Error,
+		a.PublicScore, a.PrivateScore, a.CompositeScore, secondary, a.Metadata, startedAt, finishedAt, now)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	return nil
+}
+
+// supersedeEvaluations marks the current attempts of a submission as replaced
+// by a rescore; they stay listed in its evaluation history.
+func supersedeEvaluations(ctx context.Context, tx *sql.Tx, submissionID, reason, actorID string, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE submission_evaluations
+		SET superseded_at = $1, rescore_reason = $2, requested_by = NULLIF($3, ''), updated_at = $1
+		WHERE submission_id = $4 AND superseded_at IS NULL`,
+		now, reason, actorID, submissionID)
+	if err != nil {
+		return mapSQLError(err)
+	}
+	return nil
+}
+
+// evaluationError returns the explicit callback error, falling back to an
+// "error" string in the callback metadata.
+func evaluationError(explicit string, metadata json.RawMessage) string {
+	if msg := strings.TrimSpace(explicit); msg != "" {
+		return msg
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(metadata, &payload); err != nil {
+		return ""
+	}
+	for _, key := range []string{"error", "error_message"} {
+		if msg, ok := payload[key].(string); ok && strings.TrimSpace(msg) != "" {
+			return strings.TrimSpace(msg)
+		}
+	}
+	return ""
+}
+
+// secondaryScores collects the numeric metrics reported next to the primary
+// score, from "metrics" and the secondary metric blocks.
+func secondaryScores(metadata json.RawMessage) map[string]float64 {
+	out := map[string]float64{}
+	var payload map[string]any
+	if err := json.Unmarshal(metadata, &payload); err == nil {
+		if reported, ok := payload["metrics"].(map[string]any); ok {
+			for name, v := range reported {
+				if value, ok := numericFrom(v); ok {
+					out[name] = value
+				}
+			}
+		}
+	}
+	for name, v := range extractSecondaryMetricsFromMetadata(metadata) {
+		if value, ok := numericFrom(v); ok {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+const evaluationColumns = `id, submission_id, hackathon_id, attempt, COALESCE(job_id, ''), COALESCE(executor, ''),
+		       status, error, public_score, private_score, composite_score, secondary_scores, metadata,
+		       started_at, finished_at, superseded_at, rescore_reason, COALESCE(requested_by, ''),
+		       created_at, updated_at`
+
+func scanEvaluation(row rowScanner) (*models.SubmissionEvaluation, error) {
+	var ev models.SubmissionEvaluation
+	var secondary []byte
+	if err := row.Scan(
+		&ev.ID, &ev.SubmissionID, &ev.HackathonID, &ev.Attempt, &ev.JobID, &ev.Executor,
+		&ev.Status, &ev.Error, &ev.PublicScore, &ev.PrivateScore, &ev.CompositeScore, &secondary, &ev.Metadata,
+		&ev.StartedAt, &ev.FinishedAt, &ev.SupersededAt, &ev.RescoreReason, &ev.RequestedBy,
+		&ev.CreatedAt, &ev.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(secondary) > 0 {
+		if err := json.Unmarshal(secondary, &ev.SecondaryScores); err != nil {
+			return nil, fmt.Errorf("decode secondary_scores: %w", err)
+		}
+	}
+	return &ev, nil
+}
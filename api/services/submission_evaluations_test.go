@@ -0,0 +1,26 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEvaluationError(t *testing.T) {
+	if got := evaluationError(" oom killed ", json.RawMessage(`{"error":"ignored"}`)); got != "oom killed" {
+		t.Fatalf("expected explicit error, got %q", got)
+	}
+	if got := evaluationError("", json.RawMessage(`{"error_message":"timeout"}`)); got != "timeout" {
+		t.Fatalf("expected metadata error, got %q", got)
+	}
+	if got := evaluationError("", json.RawMessage(`{"score":1}`)); got != "" {
+		t.Fatalf("expected no error, got %q", got)
+	}
+}
+
+func TestSecondaryScores(t *testing.T) {
+	raw := json.RawMessage(`{"score":0.81,"metrics":{"f1":0.7,"label":"x"},"scores":{"secondary":{"latency_ms":120}}}`)
+	got := secondaryScores(raw)
+	if len(got) != 2 || got["f1"] != 0.7 || got["latency_ms"] != 120 {
+		t.Fatalf("unexpected secondary scores %v", got)
+	}
+}
@@ -8,56 +8,70 @@ import (
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type SubmissionLimitService struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Events events.Publisher
 }
 
-func NewSubmissionLimitService(db *sql.DB) *SubmissionLimitService {
-	return &SubmissionLimitService{DB: db}
+func NewSubmissionLimitService(db *sql.DB, publisher events.Publisher) *SubmissionLimitService {
+	return &SubmissionLimitService{DB: db, Events: publisher}
 }
 
 func (s *SubmissionLimitService) Create(ctx context.Context, hackathonID string, input models.SubmissionLimit) (*models.SubmissionLimit, error) {
 	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
 		return nil, err
 	}
-	if err := validateSubmissionLimits(input.PerDay, input.Total, input.PerTeam); err != nil {
+	if err := validateSubmissionLimits(input); err != nil {
 		return nil, err
 	}
 
 	now := time.Now().UTC()
 	limit := models.SubmissionLimit{
-		ID:          uuid.NewString(),
-		HackathonID: hackathonID,
-		PerDay:      input.PerDay,
-		Total:       input.Total,
-		PerTeam:     input.PerTeam,
-		Notes:       input.Notes,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	_, err := s.DB.ExecContext(ctx, `
-		INSERT INTO submission_limits (id, hackathon_id, per_day, total, per_team, notes, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
-		limit.ID, limit.HackathonID, limit.PerDay, limit.Total, limit.PerTeam, limit.Notes, limit.CreatedAt, limit.UpdatedAt,
-	)
+		ID:                       uuid.NewString(),
+		HackathonID:              hackathonID,
+		PerDay:                   input.PerDay,
+		Total:                    input.Total,
+		PerTeam:                  input.PerTeam,
+		MaxFinalSelections:       input.MaxFinalSelections,
+		EvaluationTimeoutSeconds: input.EvaluationTimeoutSeconds,
+		MaxEvaluationAttempts:    input.MaxEvaluationAttempts,
+		Notes:                    input.Notes,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+	}
+
+	err := execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO submission_limits (
+				id, hackathon_id, per_day, total, per_team, max_final_selections,
+				evaluation_timeout_seconds, max_evaluation_attempts, notes, created_at, updated_at
+			)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+			limit.ID, limit.HackathonID, limit.PerDay, limit.Total, limit.PerTeam, limit.MaxFinalSelections,
+			limit.EvaluationTimeoutSeconds, limit.MaxEvaluationAttempts, limit.Notes, limit.CreatedAt, limit.UpdatedAt,
+		)
+		return mapSQLError(err)
+	}, submissionLimitsEvent("hackathon.submission_limits.created", hackathonID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return &limit, nil
 }
 
 func (s *SubmissionLimitService) Get(ctx context.Context, hackathonID string) (*models.SubmissionLimit, error) {
 	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, hackathon_id, per_day, total, per_team, notes, created_at, updated_at
+		SELECT id, hackathon_id, per_day, total, per_team, max_final_selections,
+		       evaluation_timeout_seconds, max_evaluation_attempts, notes, created_at, updated_at
 		FROM submission_limits
 		WHERE hackathon_id = $1`, hackathonID)
 
 	var limit models.SubmissionLimit
-	if err := row.Scan(&limit.ID, &limit.HackathonID, &limit.PerDay, &limit.Total, &limit.PerTeam, &limit.Notes, &limit.CreatedAt, &limit.UpdatedAt); err != nil {
+	if err := row.Scan(&limit.ID, &limit.HackathonID, &limit.PerDay, &limit.Total, &limit.PerTeam, &limit.MaxFinalSelections,
+		&limit.EvaluationTimeoutSeconds, &limit.MaxEvaluationAttempts, &limit.Notes, &limit.CreatedAt, &limit.UpdatedAt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -67,10 +81,13 @@ func (s *SubmissionLimitService) Get(ctx context.Context, hackathonID string) (*
 }
 
 type SubmissionLimitUpdateInput struct {
-	PerDay  *int    `json:"per_day,omitempty"`
-	Total   *int    `json:"total,omitempty"`
-	PerTeam *int    `json:"per_team,omitempty"`
-	Notes   *string `json:"notes,omitempty"`
+	PerDay                   *int    `json:"per_day,omitempty"`
+	Total                    *int    `json:"total,omitempty"`
+	PerTeam                  *int    `json:"per_team,omitempty"`
+	MaxFinalSelections       *int    `json:"max_final_selections,omitempty"`
+	EvaluationTimeoutSeconds *int    `json:"evaluation_timeout_seconds,omitempty"`
+	MaxEvaluationAttempts    *int    `json:"max_evaluation_attempts,omitempty"`
+	Notes                    *string `json:"notes,omitempty"`
 }
 
 func (s *SubmissionLimitService) Update(ctx context.Context, hackathonID string, input SubmissionLimitUpdateInput) (*models.SubmissionLimit, error) {
@@ -85,32 +102,44 @@ func (s *SubmissionLimitService) Update(ctx context.Context, hackathonID string,
 		return nil, fmt.Errorf("submission limits not found: %w", ErrNotFound)
 	}
 
-	perDay := existing.PerDay
+	next := *existing
 	if input.PerDay != nil {
-		perDay = *input.PerDay
+		next.PerDay = *input.PerDay
 	}
-	total := existing.Total
 	if input.Total != nil {
-		total = *input.Total
+		next.Total = *input.Total
 	}
-	perTeam := existing.PerTeam
 	if input.PerTeam != nil {
-		perTeam = *input.PerTeam
+		next.PerTeam = *input.PerTeam
+	}
+	if input.MaxFinalSelections != nil {
+		next.MaxFinalSelections = *input.MaxFinalSelections
 	}
-	if err := validateSubmissionLimits(perDay, total, perTeam); err != nil {
+	if input.EvaluationTimeoutSeconds != nil {
+		next.EvaluationTimeoutSeconds = *input.EvaluationTimeoutSeconds
+	}
+	if input.MaxEvaluationAttempts != nil {
+		next.MaxEvaluationAttempts = *input.MaxEvaluationAttempts
+	}
+	if err := validateSubmissionLimits(next); err != nil {
 		return nil, err
 	}
-	notes := existing.Notes
 	if input.Notes != nil {
-		notes = *input.Notes
+		next.Notes = *input.Notes
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
-		UPDATE submission_limits
-		SET per_day = $1, total = $2, per_team = $3, notes = $4, updated_at = NOW()
-		WHERE hackathon_id = $5`, perDay, total, perTeam, notes, hackathonID)
+	err = execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE submission_limits
+			SET per_day = $1, total = $2, per_team = $3, max_final_selections = $4,
+			    evaluation_timeout_seconds = $5, max_evaluation_attempts = $6, notes = $7, updated_at = NOW()
+			WHERE hackathon_id = $8`,
+			next.PerDay, next.Total, next.PerTeam, next.MaxFinalSelections,
+			next.EvaluationTimeoutSeconds, next.MaxEvaluationAttempts, next.Notes, hackathonID)
+		return mapSQLError(err)
+	}, submissionLimitsEvent("hackathon.submission_limits.updated", hackathonID))
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	return s.Get(ctx, hackathonID)
 }
@@ -119,22 +148,29 @@ func (s *SubmissionLimitService) Delete(ctx context.Context, hackathonID string)
 	if err := ensureEditableHackathon(ctx, s.DB, hackathonID); err != nil {
 		return err
 	}
-	res, err := s.DB.ExecContext(ctx, `DELETE FROM submission_limits WHERE hackathon_id = $1`, hackathonID)
-	if err != nil {
-		return mapSQLError(err)
-	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if affected == 0 {
-		return fmt.Errorf("submission limits not found: %w", ErrNotFound)
-	}
-	return nil
+	return execWithEvents(ctx, s.DB, s.Events, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM submission_limits WHERE hackathon_id = $1`, hackathonID)
+		if err != nil {
+			return mapSQLError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return fmt.Errorf("submission limits not found: %w", ErrNotFound)
+		}
+		return nil
+	}, submissionLimitsEvent("hackathon.submission_limits.deleted", hackathonID))
+}
+
+func submissionLimitsEvent(subject, hackathonID string) domainEvent {
+	return domainEvent{subject, map[string]any{"hackathon_id": hackathonID}}
 }
 
-func validateSubmissionLimits(perDay, total, perTeam int) error {
-	if perDay < 0 || total < 0 || perTeam < 0 {
+func validateSubmissionLimits(l models.SubmissionLimit) error {
+	if l.PerDay < 0 || l.Total < 0 || l.PerTeam < 0 || l.MaxFinalSelections < 0 ||
+		l.EvaluationTimeoutSeconds < 0 || l.MaxEvaluationAttempts < 0 {
 		return fmt.Errorf("submission limits must be >= 0: %w", ErrInvalid)
 	}
 	return nil
@@ -3,20 +3,26 @@ package services
 import (
 	"errors"
 	"testing"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
 )
 
 func TestValidateSubmissionLimits(t *testing.T) {
-	if err := validateSubmissionLimits(1, 10, 3); err != nil {
+	valid := models.SubmissionLimit{PerDay: 1, Total: 10, PerTeam: 3, MaxFinalSelections: 2, EvaluationTimeoutSeconds: 600, MaxEvaluationAttempts: 3}
+	if err := validateSubmissionLimits(valid); err != nil {
 		t.Fatalf("expected valid submission limits, got=%v", err)
 	}
 
-	cases := [][3]int{
-		{-1, 10, 3},
-		{1, -10, 3},
-		{1, 10, -3},
+	cases := []models.SubmissionLimit{
+		{PerDay: -1, Total: 10, PerTeam: 3},
+		{PerDay: 1, Total: -10, PerTeam: 3},
+		{PerDay: 1, Total: 10, PerTeam: -3},
+		{PerDay: 1, Total: 10, PerTeam: 3, MaxFinalSelections: -1},
+		{EvaluationTimeoutSeconds: -1},
+		{MaxEvaluationAttempts: -1},
 	}
 	for i, tc := range cases {
-		err := validateSubmissionLimits(tc[0], tc[1], tc[2])
+		err := validateSubmissionLimits(tc)
 		if !errors.Is(err, ErrInvalid) {
 			t.Fatalf("case %d: expected ErrInvalid, got=%v", i, err)
 		}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type rowsQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (s *SubmissionService) Quota(ctx context.Context, hackathonID, userID string, teamID *string) (*models.SubmissionQuota, error) {
+	if _, err := loadHackathonState(ctx, s.DB, hackathonID); err != nil {
+		return nil, err
+	}
+	return loadSubmissionQuota(ctx, s.DB, hackathonID, userID, teamID, time.Now().UTC(), false)
+}
+
+// loadSubmissionQuota counts the caller's existing submissions against the
+// hackathon limits. When lock is set the limits row is taken FOR UPDATE so
+// concurrent creates for the same hackathon serialize on the quota check.
+func loadSubmissionQuota(ctx context.Context, q rowQuerier, hackathonID, userID string, teamID *string, now time.Time, lock bool) (*models.SubmissionQuota, error) {
+	query := `SELECT per_day, total, per_team FROM submission_limits WHERE hackathon_id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var limit models.SubmissionLimit
+	err := q.QueryRowContext(ctx, query, hackathonID).Scan(&limit.PerDay, &limit.Total, &limit.PerTeam)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, mapSQLError(err)
+	}
+
+	dayStart := startOfUTCDay(now)
+	var usedToday, usedTotal, usedByTeam int
+	if err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE created_at >= $3)
+		FROM submissions
+		WHERE hackathon_id = $1 AND submitted_by = $2`, hackathonID, userID, dayStart).
+		Scan(&usedTotal, &usedToday); err != nil {
+		return nil, mapSQLError(err)
+	}
+	if teamID != nil && *teamID != "" {
+		if err := q.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM submissions
+			WHERE hackathon_id = $1 AND team_id = $2`, hackathonID, *teamID).Scan(&usedByTeam); err != nil {
+			return nil, mapSQLError(err)
+		}
+	}
+
+	quota := buildSubmissionQuota(limit, usedToday, usedTotal, usedByTeam, now)
+	quota.HackathonID = hackathonID
+	quota.UserID = userID
+	if teamID != nil && *teamID != "" {
+		quota.TeamID = teamID
+	}
+	return &quota, nil
+}
+
+func buildSubmissionQuota(limit models.SubmissionLimit, usedToday, usedTotal, usedByTeam int, now time.Time) models.SubmissionQuota {
+	return models.SubmissionQuota{
+		PerDay:         limit.PerDay,
+		Total:          limit.Total,
+		PerTeam:        limit.PerTeam,
+		UsedToday:      usedToday,
+		UsedTotal:      usedTotal,
+		UsedByTeam:     usedByTeam,
+		RemainingToday: remainingQuota(limit.PerDay, usedToday),
+		RemainingTotal: remainingQuota(limit.Total, usedTotal),
+		RemainingTeam:  remainingQuota(limit.PerTeam, usedByTeam),
+		ResetsAt:       startOfUTCDay(now).Add(24 * time.Hour),
+	}
+}
+
+// remainingQuota returns nil when the limit is 0, which means unlimited.
+func remainingQuota(limit, used int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+func checkSubmissionQuota(quota *models.SubmissionQuota) error {
+	if quota.RemainingToday != nil && *quota.RemainingToday == 0 {
+		return fmt.Errorf("daily limit of %d submissions reached: %w", quota.PerDay, ErrQuotaExceeded)
+	}
+	if quota.RemainingTotal != nil && *quota.RemainingTotal == 0 {
+		return fmt.Errorf("total limit of %d submissions reached: %w", quota.Total, ErrQuotaExceeded)
+	}
+	if quota.TeamID != nil && quota.RemainingTeam != nil && *quota.RemainingTeam == 0 {
+		return fmt.Errorf("team limit of %d submissions reached: %w", quota.PerTeam, ErrQuotaExceeded)
+	}
+	return nil
+}
+
+func consumeSubmissionQuota(quota *models.SubmissionQuota) {
+	quota.UsedToday++
+	quota.UsedTotal++
+	if quota.TeamID != nil {
+		quota.UsedByTeam++
+	}
+	quota.RemainingToday = remainingQuota(quota.PerDay, quota.UsedToday)
+	quota.RemainingTotal = remainingQuota(quota.Total, quota.UsedTotal)
+	quota.RemainingTeam = remainingQuota(quota.PerTeam, quota.UsedByTeam)
+}
+
+func startOfUTCDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
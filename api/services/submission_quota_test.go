@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+)
+
+func TestBuildSubmissionQuota(t *testing.T) {
+	now := time.Date(2026, 3, 14, 15, 30, 0, 0, time.UTC)
+	quota := buildSubmissionQuota(models.SubmissionLimit{PerDay: 5, Total: 20, PerTeam: 0}, 2, 7, 4, now)
+
+	if quota.RemainingToday == nil || *quota.RemainingToday != 3 {
+		t.Fatalf("expected 3 remaining today, got=%v", quota.RemainingToday)
+	}
+	if quota.RemainingTotal == nil || *quota.RemainingTotal != 13 {
+		t.Fatalf("expected 13 remaining total, got=%v", quota.RemainingTotal)
+	}
+	if quota.RemainingTeam != nil {
+		t.Fatalf("per_team=0 should be unlimited, got=%v", *quota.RemainingTeam)
+	}
+	wantReset := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	if !quota.ResetsAt.Equal(wantReset) {
+		t.Fatalf("expected reset at next UTC midnight, got=%v", quota.ResetsAt)
+	}
+}
+
+func TestCheckSubmissionQuota(t *testing.T) {
+	now := time.Now().UTC()
+	team := "team-1"
+
+	cases := []struct {
+		name     string
+		limit    models.SubmissionLimit
+		used     [3]int
+		teamID   *string
+		exceeded bool
+	}{
+		{name: "unlimited", limit: models.SubmissionLimit{}, used: [3]int{50, 500, 500}},
+		{name: "under limits", limit: models.SubmissionLimit{PerDay: 3, Total: 10, PerTeam: 5}, used: [3]int{2, 9, 4}, teamID: &team},
+		{name: "daily reached", limit: models.SubmissionLimit{PerDay: 3}, used: [3]int{3, 3, 0}, exceeded: true},
+		{name: "total reached", limit: models.SubmissionLimit{Total: 10}, used: [3]int{0, 10, 0}, exceeded: true},
+		{name: "team reached", limit: models.SubmissionLimit{PerTeam: 5}, used: [3]int{0, 1, 5}, teamID: &team, exceeded: true},
+		{name: "team limit ignored without team", limit: models.SubmissionLimit{PerTeam: 5}, used: [3]int{0, 1, 5}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			quota := buildSubmissionQuota(tc.limit, tc.used[0], tc.used[1], tc.used[2], now)
+			quota.TeamID = tc.teamID
+			err := checkSubmissionQuota(&quota)
+			if tc.exceeded && !errors.Is(err, ErrQuotaExceeded) {
+				t.Fatalf("expected ErrQuotaExceeded, got=%v", err)
+			}
+			if !tc.exceeded && err != nil {
+				t.Fatalf("expected no error, got=%v", err)
+			}
+		})
+	}
+}
+
+func TestConsumeSubmissionQuota(t *testing.T) {
+	team := "team-1"
+	quota := buildSubmissionQuota(models.SubmissionLimit{PerDay: 2, Total: 5, PerTeam: 3}, 1, 4, 2, time.Now().UTC())
+	quota.TeamID = &team
+
+	consumeSubmissionQuota(&quota)
+
+	if *quota.RemainingToday != 0 || *quota.RemainingTotal != 0 || *quota.RemainingTeam != 0 {
+		t.Fatalf("expected all quotas exhausted, got day=%d total=%d team=%d",
+			*quota.RemainingToday, *quota.RemainingTotal, *quota.RemainingTeam)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// RescoreInput selects the submissions of a hackathon to send back for
+// evaluation. Without SubmissionIDs every submission matching Statuses (scored
+// and evaluation_failed by default) and TrackID is requeued.
+type RescoreInput struct {
+	SubmissionIDs []string `json:"submission_ids,omitempty"`
+	Statuses      []string `json:"statuses,omitempty"`
+	TrackID       string   `json:"track_id,omitempty"`
+	Reason        string   `json:"reason,omitempty"`
+}
+
+// Rescore requeues the selected submissions in one transaction, superseding
+// their evaluation attempts in submission_evaluations.
+func (s *SubmissionService) Rescore(ctx context.Context, hackathonID string, input RescoreInput, actorID string) (*models.RescoreResult, error) {
+	statuses, err := rescoreStatuses(input.Statuses)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range input.SubmissionIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid submission id %q: %w", id, ErrInvalid)
+		}
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureRescoreAllowed(ctx, tx, hackathonID); err != nil {
+		return nil, err
+	}
+	ids, err := selectRescoreCandidates(ctx, tx, hackathonID, input, statuses)
+	if err != nil {
+		return nil, err
+	}
+	if len(input.SubmissionIDs) > 0 && len(ids) != len(uniqueStrings(input.SubmissionIDs)) {
+		return nil, fmt.Errorf("some submissions do not belong to this hackathon or cannot be rescored: %w", ErrInvalid)
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if _, err := rescoreSubmissions(ctx, tx, s.Events, ids, reason, actorID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &models.RescoreResult{
+		HackathonID:   hackathonID,
+		Reason:        reason,
+		Requeued:      len(ids),
+		SubmissionIDs: ids,
+	}, nil
+}
+
+// Requeue sends a single scored, failed or invalidated submission back for
+// evaluation.
+func (s *SubmissionService) Requeue(ctx context.Context, id, reason, actorID string) (*models.Submission, error) {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sub, err := loadSubmission(ctx, tx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("submission not found: %w", ErrNotFound)
+	}
+	if err := ensureRescoreAllowed(ctx, tx, sub.HackathonID); err != nil {
+		return nil, err
+	}
+	if _, err := requeueSubmission(ctx, tx, s.Events, id, strings.TrimSpace(reason), actorID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// requeueSubmission sends one finished submission back to the evaluation
+// queue inside tx so callers such as appeal resolution can combine it with
+// their own changes.
+func requeueSubmission(ctx context.Context, tx *sql.Tx, publisher events.Publisher, id, reason, actorID string) (*models.Submission, error) {
+	subs, err := rescoreSubmissions(ctx, tx, publisher, []string{id}, reason, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return &subs[0], nil
+}
+
+// rescoreSubmissions supersedes the evaluation attempts of each submission, clears
+// its scores and moves it to queued_for_evaluation. The leaderboard is
+// refreshed once when any of them was scored.
+func rescoreSubmissions(ctx context.Context, tx *sql.Tx, publisher events.Publisher, ids []string, reason, actorID string) ([]models.Submission, error) {
+	out := make([]models.Submission, 0, len(ids))
+	var evts []domainEvent
+	refresh := map[string]string{}
+	now := time.Now().UTC()
+
+	for _, id := range ids {
+		sub, err := loadSubmission(ctx, tx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		if sub == nil {
+			return nil, fmt.Errorf("submission not found: %w", ErrNotFound)
+		}
+		if !isSubmissionTransitionAllowed(sub.Status, models.SubmissionStatusQueuedForEval) {
+			return nil, fmt.Errorf("cannot requeue a submission in status %s: %w", sub.Status, ErrInvalid)
+		}
+
+		if err := supersedeEvaluations(ctx, tx, sub.ID, reason, actorID, now); err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE submissions
+			SET status = $1, public_score = NULL, private_score = NULL, composite_score = NULL, invalidated_at = NULL, updated_at = $2
+			WHERE id = $3`,
+			models.SubmissionStatusQueuedForEval, now, id,
+		)
+		if err != nil {
+			return nil, mapSQLError(err)
+		}
+
+		if sub.Status == models.SubmissionStatusScored {
+			refresh[sub.HackathonID] = sub.ID
+		}
+		sub.Status = models.SubmissionStatusQueuedForEval
+		sub.PublicScore, sub.PrivateScore, sub.CompositeScore, sub.InvalidatedAt = nil, nil, nil, nil
+		sub.UpdatedAt = now
+
+		payload := submissionEventPayload(sub)
+		if reason != "" {
+			payload["reason"] = reason
+		}
+		evts = append(evts, domainEvent{"submission.rescore.requested", payload})
+		out = append(out, *sub)
+	}
+
+	for hackathonID, submissionID := range refresh {
+		if err := refreshLeaderboard(ctx, tx, hackathonID); err != nil {
+			return nil, err
+		}
+		evts = append(evts, leaderboardUpdatedEvent(hackathonID, submissionID))
+	}
+	if err := publishInTx(ctx, tx, publisher, evts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ensureRescoreAllowed rejects rescoring once the hackathon is archived.
+func ensureRescoreAllowed(ctx context.Context, q rowQuerier, hackathonID string) error {
+	var state string
+	err := q.QueryRowContext(ctx, `SELECT state FROM hackathons WHERE id = $1 FOR SHARE`, hackathonID).Scan(&state)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("hackathon not found: %w", ErrNotFound)
+	}
+	if err != nil {
+		return mapSQLError(err)
+	}
+	if state == models.HackathonStateArchived {
+		return fmt.Errorf("submissions cannot be rescored in state %s: %w", state, ErrInvalid)
+	}
+	return nil
+}
+
+func selectRescoreCandidates(ctx context.Context, q rowsQuerier, hackathonID string, input RescoreInput, statuses []string) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id FROM submissions
+		WHERE hackathon_id = $1
+		  AND status = ANY($2)
+		  AND ($3 = '' OR track_id::text = $3)
+		  AND (cardinality($4::text[]) = 0 OR id::text = ANY($4))
+		ORDER BY created_at`,
+		hackathonID, pq.Array(statuses), strings.TrimSpace(input.TrackID), pq.Array(uniqueStrings(input.SubmissionIDs)))
+	if err != nil {
+		return nil, mapSQLError(err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, mapSQLError(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// rescoreStatuses validates the status filter of a bulk rescore.
+func rescoreStatuses(statuses []string) ([]string, error) {
+	if len(statuses) == 0 {
+		return []string{models.SubmissionStatusScored, models.SubmissionStatusEvaluationFailed}, nil
+	}
+	for _, status := range statuses {
+		if !isSubmissionTransitionAllowed(status, models.SubmissionStatusQueuedForEval) {
+			return nil, fmt.Errorf("status %q cannot be rescored: %w", status, ErrInvalid)
+		}
+	}
+	return uniqueStrings(statuses), nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
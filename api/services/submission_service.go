@@ -6,19 +6,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/DataInCube/hackathon-service/pkg/events"
 	"github.com/google/uuid"
 )
 
 type SubmissionService struct {
 	DB          *sql.DB
 	TrackLookup *TrackService
+	Events      events.Publisher
 }
 
-func NewSubmissionService(db *sql.DB, trackLookup *TrackService) *SubmissionService {
-	return &SubmissionService{DB: db, TrackLookup: trackLookup}
+func NewSubmissionService(db *sql.DB, trackLookup *TrackService, publisher events.Publisher) *SubmissionService {
+	return &SubmissionService{DB: db, TrackLookup: trackLookup, Events: publisher}
 }
 
 type SubmissionInput struct {
@@ -34,43 +38,48 @@ type SubmissionUpdateInput struct {
 	Metadata    *json.RawMessage `json:"metadata,omitempty"`
 }
 
-func (s *SubmissionService) Create(ctx context.Context, hackathonID string, input SubmissionInput, actorID string) (*models.Submission, error) {
+func (s *SubmissionService) Create(ctx context.Context, hackathonID string, input SubmissionInput, actorID string) (*models.Submission, *models.SubmissionQuota, error) {
 	state, ruleVersionID, policy, err := s.loadHackathonForSubmission(ctx, hackathonID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if state != models.HackathonStateLive {
-		return nil, fmt.Errorf("hackathon not live: %w", ErrInvalid)
+		return nil, nil, fmt.Errorf("hackathon not live: %w", ErrInvalid)
 	}
 	if ruleVersionID == "" {
-		return nil, fmt.Errorf("active rule version required: %w", ErrInvalid)
+		return nil, nil, fmt.Errorf("active rule version required: %w", ErrInvalid)
 	}
 
 	if policy.RequiresTeams && (input.TeamID == nil || *input.TeamID == "") {
-		return nil, fmt.Errorf("team_id required: %w", ErrInvalid)
+		return nil, nil, fmt.Errorf("team_id required: %w", ErrInvalid)
 	}
 	if !policy.AllowsTeams && input.TeamID != nil && *input.TeamID != "" {
-		return nil, fmt.Errorf("teams not allowed: %w", ErrInvalid)
+		return nil, nil, fmt.Errorf("teams not allowed: %w", ErrInvalid)
 	}
 	if input.MemberCount != nil {
 		if policy.MinTeamSize > 0 && *input.MemberCount < policy.MinTeamSize {
-			return nil, fmt.Errorf("team too small: %w", ErrInvalid)
+			return nil, nil, fmt.Errorf("team too small: %w", ErrInvalid)
 		}
 		if policy.MaxTeamSize > 0 && *input.MemberCount > policy.MaxTeamSize {
-			return nil, fmt.Errorf("team too large: %w", ErrInvalid)
+			return nil, nil, fmt.Errorf("team too large: %w", ErrInvalid)
 		}
 	}
 
 	if input.TrackID != nil && *input.TrackID != "" && s.TrackLookup != nil {
 		ok, err := s.TrackLookup.Exists(ctx, hackathonID, *input.TrackID)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if !ok {
-			return nil, fmt.Errorf("track_id not found: %w", ErrInvalid)
+			return nil, nil, fmt.Errorf("track_id not found: %w", ErrInvalid)
 		}
 	}
 
+	metadata := normalizeMetadata(input.Metadata)
+	if err := validateSubmissionMetadata(ctx, s.DB, hackathonID, metadata); err != nil {
+		return nil, nil, err
+	}
+
 	now := time.Now().UTC()
 	sub := models.Submission{
 		ID:            uuid.NewString(),
@@ -81,74 +90,135 @@ func (s *SubmissionService) Create(ctx context.Context, hackathonID string, inpu
 		TeamID:        input.TeamID,
 		Status:        models.SubmissionStatusCreated,
 		Phase:         state,
-		Metadata:      normalizeMetadata(input.Metadata),
+		Metadata:      metadata,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureRegisteredParticipant(ctx, tx, hackathonID, actorID); err != nil {
+		return nil, nil, err
+	}
+	if err := ensureRuleAccepted(ctx, tx, hackathonID, ruleVersionID, actorID); err != nil {
+		return nil, nil, err
+	}
+
+	quota, err := loadSubmissionQuota(ctx, tx, hackathonID, actorID, input.TeamID, now, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkSubmissionQuota(quota); err != nil {
+		return nil, quota, err
+	}
+	if sub.DatasetVersionID, err = latestDatasetVersionID(ctx, tx, hackathonID); err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO submissions (
-			id, hackathon_id, track_id, rule_version_id, submitted_by,
+			id, hackathon_id, track_id, rule_version_id, dataset_version_id, submitted_by,
 			team_id, status, phase, metadata, created_at, updated_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
-		sub.ID, sub.HackathonID, sub.TrackID, sub.RuleVersionID, sub.SubmittedBy,
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`,
+		sub.ID, sub.HackathonID, sub.TrackID, sub.RuleVersionID, sub.DatasetVersionID, sub.SubmittedBy,
 		sub.TeamID, sub.Status, sub.Phase, sub.Metadata, sub.CreatedAt, sub.UpdatedAt,
 	)
 	if err != nil {
-		return nil, mapSQLError(err)
+		return nil, nil, mapSQLError(err)
 	}
 
-	return &sub, nil
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"submission.created", submissionEventPayload(&sub)}); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	consumeSubmissionQuota(quota)
+
+	return &sub, quota, nil
 }
 
 func (s *SubmissionService) GetByID(ctx context.Context, id string) (*models.Submission, error) {
-	row := s.DB.QueryRowContext(ctx, `
-		SELECT id, hackathon_id, track_id, rule_version_id, submitted_by,
-		       team_id, status, phase, metadata, created_at, updated_at, locked_at, invalidated_at
-		FROM submissions WHERE id = $1`, id)
+	return loadSubmission(ctx, s.DB, id, false)
+}
 
+// SubmissionFilter narrows ListByHackathon; empty fields are ignored and the
+// created_at bounds are inclusive.
+type SubmissionFilter struct {
+	Status        string
+	TrackID       string
+	SubmittedBy   string
+	TeamID        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+func (s *SubmissionService) ListByHackathon(ctx context.Context, hackathonID string, filter SubmissionFilter, page PageRequest) (*models.Page[models.Submission], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	if filter.Status != "" {
+		q.where("status = %s", filter.Status)
+	}
+	if filter.TrackID != "" {
+		q.where("track_id = %s", filter.TrackID)
+	}
+	if filter.SubmittedBy != "" {
+		q.where("submitted_by = %s", filter.SubmittedBy)
+	}
+	if filter.TeamID != "" {
+		q.where("team_id = %s", filter.TeamID)
+	}
+	if filter.CreatedAfter != nil {
+		q.where("created_at >= %s", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		q.where("created_at <= %s", *filter.CreatedBefore)
+	}
+	return queryPage(ctx, s.DB, keyset{columns: submissionColumns, from: "submissions", at: "created_at"}, q, page,
+		scanSubmission, func(sub *models.Submission) (time.Time, string) { return sub.CreatedAt, sub.ID })
+}
+
+const submissionColumns = `id, hackathon_id, track_id, rule_version_id, dataset_version_id, submitted_by,
+		       team_id, status, phase, metadata, public_score, private_score, composite_score,
+		       COALESCE(final_selection, ''), final_selected_at,
+		       created_at, updated_at, locked_at, invalidated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubmission(row rowScanner) (*models.Submission, error) {
 	var sub models.Submission
 	var metadata []byte
 	if err := row.Scan(
-		&sub.ID, &sub.HackathonID, &sub.TrackID, &sub.RuleVersionID, &sub.SubmittedBy,
-		&sub.TeamID, &sub.Status, &sub.Phase, &metadata, &sub.CreatedAt, &sub.UpdatedAt, &sub.LockedAt, &sub.InvalidatedAt,
+		&sub.ID, &sub.HackathonID, &sub.TrackID, &sub.RuleVersionID, &sub.DatasetVersionID, &sub.SubmittedBy,
+		&sub.TeamID, &sub.Status, &sub.Phase, &metadata, &sub.PublicScore, &sub.PrivateScore, &sub.CompositeScore,
+		&sub.FinalSelection, &sub.FinalSelectedAt,
+		&sub.CreatedAt, &sub.UpdatedAt, &sub.LockedAt, &sub.InvalidatedAt,
 	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, mapSQLError(err)
+		return nil, err
 	}
 	sub.Metadata = metadata
 	return &sub, nil
 }
 
-func (s *SubmissionService) ListByHackathon(ctx context.Context, hackathonID string, limit, offset int) ([]models.Submission, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, track_id, rule_version_id, submitted_by,
-		       team_id, status, phase, metadata, created_at, updated_at, locked_at, invalidated_at
-		FROM submissions
-		WHERE hackathon_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
+func loadSubmission(ctx context.Context, q rowQuerier, id string, lock bool) (*models.Submission, error) {
+	query := `SELECT ` + submissionColumns + ` FROM submissions WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	sub, err := scanSubmission(q.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
-	defer rows.Close()
-
-	var items []models.Submission
-	for rows.Next() {
-		var sub models.Submission
-		var metadata []byte
-		if err := rows.Scan(
-			&sub.ID, &sub.HackathonID, &sub.TrackID, &sub.RuleVersionID, &sub.SubmittedBy,
-			&sub.TeamID, &sub.Status, &sub.Phase, &metadata, &sub.CreatedAt, &sub.UpdatedAt, &sub.LockedAt, &sub.InvalidatedAt,
-		); err != nil {
-			return nil, mapSQLError(err)
-		}
-		sub.Metadata = metadata
-		items = append(items, sub)
-	}
-	return items, nil
+	return sub, nil
 }
 
 func (s *SubmissionService) Update(ctx context.Context, id string, input SubmissionUpdateInput) (*models.Submission, error) {
@@ -202,6 +272,9 @@ func (s *SubmissionService) Update(ctx context.Context, id string, input Submiss
 			return nil, fmt.Errorf("invalid metadata: %w", ErrInvalid)
 		}
 		metadata = merged
+		if err := validateSubmissionMetadata(ctx, s.DB, sub.HackathonID, metadata); err != nil {
+			return nil, err
+		}
 	}
 
 	_, err = s.DB.ExecContext(ctx, `
@@ -255,7 +328,13 @@ func (s *SubmissionService) Lock(ctx context.Context, id string) (*models.Submis
 	}
 
 	now := time.Now().UTC()
-	_, err = s.DB.ExecContext(ctx, `
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE submissions
 		SET status = $1, locked_at = $2, updated_at = NOW()
 		WHERE id = $3`,
@@ -264,11 +343,51 @@ func (s *SubmissionService) Lock(ctx context.Context, id string) (*models.Submis
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
+	sub.Status = models.SubmissionStatusQueuedForEval
+	if err := publishInTx(ctx, tx, s.Events, domainEvent{"submission.locked", submissionEventPayload(sub)}); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return s.GetByID(ctx, id)
 }
 
-func (s *SubmissionService) UpdateEvaluationStatus(ctx context.Context, id, target string, metadataPatch *json.RawMessage) (*models.Submission, error) {
-	sub, err := s.GetByID(ctx, id)
+// EvaluationUpdate carries the evaluator callback payload. Scores are only
+// accepted when the submission is marked scored; JobID, Executor and Error
+// describe the attempt recorded in submission_evaluations.
+type EvaluationUpdate struct {
+	Metadata       *json.RawMessage
+	PublicScore    *float64
+	PrivateScore   *float64
+	CompositeScore *float64
+	JobID          string
+	Executor       string
+	Error          string
+}
+
+func (s *SubmissionService) UpdateEvaluationStatus(ctx context.Context, id, target string, update EvaluationUpdate) (*models.Submission, error) {
+	if target != models.SubmissionStatusScored && (update.PublicScore != nil || update.PrivateScore != nil || update.CompositeScore != nil) {
+		return nil, fmt.Errorf("scores are only accepted when marking a submission scored: %w", ErrInvalid)
+	}
+	if err := validateScore("public_score", update.PublicScore); err != nil {
+		return nil, err
+	}
+	if err := validateScore("private_score", update.PrivateScore); err != nil {
+		return nil, err
+	}
+	if err := validateScore("composite_score", update.CompositeScore); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The row lock serializes callbacks so attempts are numbered in order.
+	sub, err := loadSubmission(ctx, tx, id, true)
 	if err != nil {
 		return nil, err
 	}
@@ -280,26 +399,91 @@ func (s *SubmissionService) UpdateEvaluationStatus(ctx context.Context, id, targ
 	}
 
 	metadata := sub.Metadata
-	if metadataPatch != nil {
-		merged, err := mergeMetadata(sub.Metadata, *metadataPatch)
+	patch := json.RawMessage(`{}`)
+	if update.Metadata != nil {
+		merged, err := mergeMetadata(sub.Metadata, *update.Metadata)
 		if err != nil {
 			return nil, fmt.Errorf("invalid metadata: %w", ErrInvalid)
 		}
 		metadata = merged
+		patch = normalizeMetadata(*update.Metadata)
 	}
 
-	_, err = s.DB.ExecContext(ctx, `
+	publicScore, privateScore, compositeScore := sub.PublicScore, sub.PrivateScore, sub.CompositeScore
+	if target == models.SubmissionStatusScored {
+		publicScore, privateScore, compositeScore = update.PublicScore, update.PrivateScore, update.CompositeScore
+		if publicScore == nil {
+			if score, ok := extractScoreFromMetadata(metadata); ok {
+				publicScore = &score
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE submissions
-		SET status = $1, metadata = $2, updated_at = NOW()
-		WHERE id = $3`, target, metadata, id)
+		SET status = $1, metadata = $2, public_score = $3, private_score = $4, composite_score = $5, updated_at = NOW()
+		WHERE id = $6`, target, metadata, publicScore, privateScore, compositeScore, id)
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
+	attempt := evaluationAttempt{
+		Status:         target,
+		JobID:          strings.TrimSpace(update.JobID),
+		Executor:       strings.TrimSpace(update.Executor),
+		Error:          evaluationError(update.Error, patch),
+		PublicScore:    publicScore,
+		PrivateScore:   privateScore,
+		CompositeScore: compositeScore,
+		Metadata:       patch,
+	}
+	if target == models.SubmissionStatusScored {
+		attempt.SecondaryScores = secondaryScores(metadata)
+	}
+	if err := recordEvaluationAttempt(ctx, tx, sub, attempt); err != nil {
+		return nil, err
+	}
+	if target == models.SubmissionStatusScored {
+		sub.Status = target
+		sub.Metadata = metadata
+		sub.PublicScore = publicScore
+		sub.PrivateScore = privateScore
+		sub.CompositeScore = compositeScore
+		if err := refreshLeaderboard(ctx, tx, sub.HackathonID); err != nil {
+			return nil, err
+		}
+		if err := publishInTx(ctx, tx, s.Events,
+			domainEvent{"evaluation.completed", evaluationCompletedPayload(sub)},
+			leaderboardUpdatedEvent(sub.HackathonID, sub.ID),
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return s.GetByID(ctx, id)
 }
 
 func (s *SubmissionService) Invalidate(ctx context.Context, id string) (*models.Submission, error) {
-	sub, err := s.GetByID(ctx, id)
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := invalidateSubmission(ctx, tx, s.Events, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// invalidateSubmission invalidates a submission inside tx so callers such as
+// appeal resolution can combine it with their own changes.
+func invalidateSubmission(ctx context.Context, tx *sql.Tx, publisher events.Publisher, id string) (*models.Submission, error) {
+	sub, err := loadSubmission(ctx, tx, id, true)
 	if err != nil {
 		return nil, err
 	}
@@ -311,7 +495,7 @@ func (s *SubmissionService) Invalidate(ctx context.Context, id string) (*models.
 	}
 
 	now := time.Now().UTC()
-	_, err = s.DB.ExecContext(ctx, `
+	_, err = tx.ExecContext(ctx, `
 		UPDATE submissions
 		SET status = $1, invalidated_at = $2, updated_at = NOW()
 		WHERE id = $3`,
@@ -320,13 +504,29 @@ func (s *SubmissionService) Invalidate(ctx context.Context, id string) (*models.
 	if err != nil {
 		return nil, mapSQLError(err)
 	}
-	return s.GetByID(ctx, id)
+	wasScored := sub.Status == models.SubmissionStatusScored
+	sub.Status = models.SubmissionStatusInvalidated
+	sub.InvalidatedAt = &now
+	evts := []domainEvent{{"submission.invalidated", submissionEventPayload(sub)}}
+	if wasScored {
+		if err := refreshLeaderboard(ctx, tx, sub.HackathonID); err != nil {
+			return nil, err
+		}
+		evts = append(evts, leaderboardUpdatedEvent(sub.HackathonID, sub.ID))
+	}
+	if err := publishInTx(ctx, tx, publisher, evts...); err != nil {
+		return nil, err
+	}
+	return sub, nil
 }
 
 func isSubmissionTransitionAllowed(current, target string) bool {
 	allowed := map[string][]string{
 		models.SubmissionStatusQueuedForEval:     {models.SubmissionStatusEvaluationRunning, models.SubmissionStatusEvaluationFailed},
 		models.SubmissionStatusEvaluationRunning: {models.SubmissionStatusEvaluationFailed, models.SubmissionStatusScored},
+		models.SubmissionStatusEvaluationFailed:  {models.SubmissionStatusQueuedForEval},
+		models.SubmissionStatusScored:            {models.SubmissionStatusQueuedForEval},
+		models.SubmissionStatusInvalidated:       {models.SubmissionStatusQueuedForEval},
 	}
 	for _, next := range allowed[current] {
 		if next == target {
@@ -336,6 +536,13 @@ func isSubmissionTransitionAllowed(current, target string) bool {
 	return false
 }
 
+func validateScore(field string, score *float64) error {
+	if score != nil && (math.IsNaN(*score) || math.IsInf(*score, 0)) {
+		return fmt.Errorf("%s must be a finite number: %w", field, ErrInvalid)
+	}
+	return nil
+}
+
 func (s *SubmissionService) loadHackathonForSubmission(ctx context.Context, hackathonID string) (string, string, models.TeamPolicy, error) {
 	var state string
 	var ruleID sql.NullString
@@ -15,6 +15,12 @@ func TestIsSubmissionTransitionAllowed(t *testing.T) {
 	if isSubmissionTransitionAllowed("created", "evaluation_running") {
 		t.Fatalf("expected created -> evaluation_running disallowed")
 	}
+	if !isSubmissionTransitionAllowed("scored", "queued_for_evaluation") {
+		t.Fatalf("expected scored -> queued_for_evaluation allowed")
+	}
+	if isSubmissionTransitionAllowed("scored", "evaluation_running") {
+		t.Fatalf("expected scored -> evaluation_running disallowed")
+	}
 }
 
 func TestMergeMetadata(t *testing.T) {
@@ -45,3 +51,25 @@ func TestMergeMetadata(t *testing.T) {
 		t.Fatalf("expected error on invalid json")
 	}
 }
+
+func TestRescoreStatuses(t *testing.T) {
+	statuses, err := rescoreStatuses(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0] != "scored" || statuses[1] != "evaluation_failed" {
+		t.Fatalf("unexpected default statuses %v", statuses)
+	}
+
+	statuses, err = rescoreStatuses([]string{"invalidated", "scored", "invalidated"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected duplicates removed, got %v", statuses)
+	}
+
+	if _, err := rescoreStatuses([]string{"evaluation_running"}); err == nil {
+		t.Fatalf("expected error for evaluation_running")
+	}
+}
@@ -62,27 +62,21 @@ func (s *TrackService) GetByID(ctx context.Context, hackathonID, trackID string)
 	return &t, nil
 }
 
-func (s *TrackService) List(ctx context.Context, hackathonID string, limit, offset int) ([]models.Track, error) {
-	rows, err := s.DB.QueryContext(ctx, `
-		SELECT id, hackathon_id, name, description, is_active, created_at, updated_at
-		FROM tracks
-		WHERE hackathon_id = $1
-		ORDER BY created_at
-		LIMIT $2 OFFSET $3`, hackathonID, limit, offset)
-	if err != nil {
-		return nil, mapSQLError(err)
-	}
-	defer rows.Close()
-
-	var items []models.Track
-	for rows.Next() {
+func (s *TrackService) List(ctx context.Context, hackathonID string, page PageRequest) (*models.Page[models.Track], error) {
+	var q listQuery
+	q.where("hackathon_id = %s", hackathonID)
+	return queryPage(ctx, s.DB, keyset{
+		columns: `id, hackathon_id, name, description, is_active, created_at, updated_at`,
+		from:    "tracks",
+		at:      "created_at",
+		asc:     true,
+	}, q, page, func(row rowScanner) (*models.Track, error) {
 		var t models.Track
-		if err := rows.Scan(&t.ID, &t.HackathonID, &t.Name, &t.Description, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, mapSQLError(err)
+		if err := row.Scan(&t.ID, &t.HackathonID, &t.Name, &t.Description, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
 		}
-		items = append(items, t)
-	}
-	return items, nil
+		return &t, nil
+	}, func(t *models.Track) (time.Time, string) { return t.CreatedAt, t.ID })
 }
 
 type TrackUpdateInput struct {
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	dbutils "github.com/DataInCube/go-utils/db"
@@ -10,7 +15,12 @@ import (
 	"github.com/DataInCube/go-utils/stringsx"
 	"github.com/DataInCube/hackathon-service/api/middlewares"
 	"github.com/DataInCube/hackathon-service/api/routes"
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/jobs"
+	"github.com/DataInCube/hackathon-service/migrations"
+	"github.com/DataInCube/hackathon-service/pkg/blobstore"
 	"github.com/DataInCube/hackathon-service/pkg/events"
+	"github.com/DataInCube/hackathon-service/pkg/migrate"
 
 	_ "github.com/DataInCube/hackathon-service/docs"
 	"github.com/joho/godotenv"
@@ -38,6 +48,18 @@ func main() {
 	serviceName := env.GetString("SERVICE_NAME", "hackathon-service")
 	serviceVersion := env.GetString("SERVICE_VERSION", "dev")
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		db, err := connectDB()
+		if err != nil {
+			logger.Fatal("Failed to connect to DB: ", err)
+		}
+		defer db.Close()
+		if err := runMigrate(context.Background(), db, os.Args[2:], os.Stdout); err != nil {
+			logger.Fatal("migrate: ", err)
+		}
+		return
+	}
+
 	foundationMode := env.GetBool("FOUNDATION_MODE", false)
 	if foundationMode {
 		e := echo.New()
@@ -55,33 +77,26 @@ func main() {
 		return
 	}
 
-	// Load DB config
-	driver := env.GetString("DB_DRIVER", "postgres")
-	dsn := env.GetString("DB_DSN", "")
-	if dsn == "" {
-		host := env.GetString("DB_HOST", "localhost")
-		port := env.GetString("DB_PORT", "5432")
-		user := env.GetString("DB_USER", "postgres")
-		password := env.GetString("DB_PASSWORD", "postgres")
-		name := env.GetString("DB_NAME", "hackathondb")
-		sslMode := env.GetString("DB_SSLMODE", "disable")
-		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", host, port, user, password, name, sslMode)
-	}
-
-	// Init DB
-	maxOpenConns := env.GetInt("DB_MAX_OPEN_CONNS", 10)
-	maxIdleConns := env.GetInt("DB_MAX_IDLE_CONNS", 5)
-	connMaxLifetime := time.Duration(env.GetInt("DB_CONN_MAX_LIFETIME_MINUTES", 30)) * time.Minute
-	db, err := dbutils.Connect(driver, dsn, dbutils.PoolConfig{
-		MaxOpenConns:    maxOpenConns,
-		MaxIdleConns:    maxIdleConns,
-		ConnMaxLifetime: connMaxLifetime,
-	})
+	db, err := connectDB()
 	if err != nil {
 		logger.Fatal("Failed to connect to DB: ", err)
 	}
 	logger.Println("✅ Connected to PostgreSQL database")
 
+	if env.GetBool("DB_AUTO_MIGRATE", false) {
+		migrator, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			logger.Fatal("Failed to load migrations: ", err)
+		}
+		applied, err := migrator.Up(context.Background(), false)
+		if err != nil {
+			logger.Fatal("Failed to apply migrations: ", err)
+		}
+		for _, m := range applied {
+			logger.Infof("Applied migration %s", m)
+		}
+	}
+
 	// Echo instance
 	e := echo.New()
 
@@ -103,6 +118,9 @@ func main() {
 		logger.Fatal("Failed to initialize auth middleware: ", err)
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	var publisher events.Publisher
 	if env.GetBool("EVENTS_ENABLED", true) {
 		natsURL := env.GetString("NATS_URL", "nats://nats:4222")
@@ -113,11 +131,112 @@ func main() {
 			logger.Fatal("Failed to connect to NATS: ", err)
 		}
 		defer natsPublisher.Close()
-		publisher = natsPublisher
+
+		// Domain events are written to the outbox and relayed to NATS in the background.
+		outbox, err := events.NewOutboxPublisher(db)
+		if err != nil {
+			logger.Fatal("Failed to initialize event outbox: ", err)
+		}
+		relay, err := events.NewOutboxRelay(db, natsPublisher, events.RelayConfig{
+			BatchSize:    env.GetInt("OUTBOX_BATCH_SIZE", 100),
+			PollInterval: time.Duration(env.GetInt("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+			MaxBackoff:   time.Duration(env.GetInt("OUTBOX_MAX_BACKOFF_SECONDS", 300)) * time.Second,
+			Retention:    time.Duration(env.GetInt("OUTBOX_RETENTION_HOURS", 168)) * time.Hour,
+			Lease:        time.Duration(env.GetInt("OUTBOX_LEASE_SECONDS", 30)) * time.Second,
+		}, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize outbox relay: ", err)
+		}
+		go relay.Run(ctx)
+		publisher = outbox
+	}
+
+	if env.GetBool("SCHEDULER_ENABLED", true) {
+		leader, err := jobs.NewLeader(db, jobs.LockLifecycleScheduler)
+		if err != nil {
+			logger.Fatal("Failed to initialize scheduler leader election: ", err)
+		}
+		scheduler, err := jobs.NewLifecycleScheduler(
+			services.NewHackathonService(db, publisher),
+			services.NewGovernanceService(db, publisher),
+			leader,
+			time.Duration(env.GetInt("SCHEDULER_INTERVAL_SECONDS", 30))*time.Second,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("Failed to initialize lifecycle scheduler: ", err)
+		}
+		go scheduler.Run(ctx)
+	}
+
+	if env.GetBool("EVALUATION_WATCHDOG_ENABLED", true) {
+		leader, err := jobs.NewLeader(db, jobs.LockEvaluationWatchdog)
+		if err != nil {
+			logger.Fatal("Failed to initialize evaluation watchdog leader election: ", err)
+		}
+		watchdog, err := jobs.NewEvaluationWatchdog(
+			services.NewSubmissionService(db, services.NewTrackService(db), publisher),
+			leader,
+			services.EvaluationTimeoutDefaults{
+				Timeout:     time.Duration(env.GetInt("EVALUATION_TIMEOUT_SECONDS", 3600)) * time.Second,
+				MaxAttempts: env.GetInt("EVALUATION_MAX_ATTEMPTS", 1),
+				BatchSize:   env.GetInt("EVALUATION_WATCHDOG_BATCH_SIZE", 100),
+			},
+			time.Duration(env.GetInt("EVALUATION_WATCHDOG_INTERVAL_SECONDS", 60))*time.Second,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("Failed to initialize evaluation watchdog: ", err)
+		}
+		go watchdog.Run(ctx)
+	}
+
+	// Dataset file storage
+	signingKey := env.GetString("BLOB_SIGNING_KEY", "")
+	if signingKey == "" {
+		signingKey = randomHex(32)
+		logger.Warn("BLOB_SIGNING_KEY is not set; signed download links will not survive a restart")
+	}
+	blobs, err := blobstore.New(blobstore.Config{
+		Driver:        env.GetString("BLOB_STORE_DRIVER", "local"),
+		LocalDir:      env.GetString("BLOB_LOCAL_DIR", "./data/blobs"),
+		PublicBaseURL: env.GetString("BLOB_PUBLIC_BASE_URL", "http://localhost:8081/blobs"),
+		SigningKey:    signingKey,
+		S3Endpoint:    env.GetString("BLOB_S3_ENDPOINT", ""),
+		S3Region:      env.GetString("BLOB_S3_REGION", "us-east-1"),
+		S3Bucket:      env.GetString("BLOB_S3_BUCKET", ""),
+		S3AccessKey:   env.GetString("BLOB_S3_ACCESS_KEY", ""),
+		S3SecretKey:   env.GetString("BLOB_S3_SECRET_KEY", ""),
+		S3PathStyle:   env.GetBool("BLOB_S3_PATH_STYLE", true),
+	})
+	if err != nil {
+		logger.Fatal("Failed to initialize blob store: ", err)
+	}
+	storage := services.DatasetStorage{
+		Blobs:          blobs,
+		SignedURLTTL:   time.Duration(env.GetInt("BLOB_SIGNED_URL_TTL_SECONDS", 900)) * time.Second,
+		UploadTTL:      time.Duration(env.GetInt("UPLOAD_SESSION_TTL_HOURS", 24)) * time.Hour,
+		MaxUploadBytes: int64(env.GetInt("UPLOAD_MAX_MB", 5120)) << 20,
+	}
+
+	if env.GetBool("DATASET_PROFILER_ENABLED", true) {
+		profiler, err := jobs.NewDatasetProfiler(
+			services.NewDatasetService(db, storage, publisher),
+			jobs.DatasetProfilerConfig{
+				StaleAfter:  time.Duration(env.GetInt("DATASET_PROFILE_STALE_MINUTES", 60)) * time.Minute,
+				MaxAttempts: env.GetInt("DATASET_PROFILE_MAX_ATTEMPTS", 3),
+			},
+			time.Duration(env.GetInt("DATASET_PROFILER_INTERVAL_SECONDS", 10))*time.Second,
+			logger,
+		)
+		if err != nil {
+			logger.Fatal("Failed to initialize dataset profiler: ", err)
+		}
+		go profiler.Run(ctx)
 	}
 
 	// Register API routes
-	routes.RegisterRoutes(e, db, logger, authMiddleware, serviceName, serviceVersion, publisher)
+	routes.RegisterRoutes(e, db, logger, authMiddleware, serviceName, serviceVersion, publisher, storage)
 
 	// Swagger documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
@@ -137,6 +256,34 @@ func main() {
 
 }
 
+func connectDB() (*sql.DB, error) {
+	driver := env.GetString("DB_DRIVER", "postgres")
+	dsn := env.GetString("DB_DSN", "")
+	if dsn == "" {
+		host := env.GetString("DB_HOST", "localhost")
+		port := env.GetString("DB_PORT", "5432")
+		user := env.GetString("DB_USER", "postgres")
+		password := env.GetString("DB_PASSWORD", "postgres")
+		name := env.GetString("DB_NAME", "hackathondb")
+		sslMode := env.GetString("DB_SSLMODE", "disable")
+		dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", host, port, user, password, name, sslMode)
+	}
+
+	return dbutils.Connect(driver, dsn, dbutils.PoolConfig{
+		MaxOpenConns:    env.GetInt("DB_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    env.GetInt("DB_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: time.Duration(env.GetInt("DB_CONN_MAX_LIFETIME_MINUTES", 30)) * time.Minute,
+	})
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
 func eventSubjectsFromEnv(get func(string, string) string) []string {
 	return []string{
 		get("NATS_SUBJECT_HACKATHON_CREATED", "hackathon.created"),
@@ -152,23 +299,45 @@ func eventSubjectsFromEnv(get func(string, string) string) []string {
 		get("NATS_SUBJECT_HACKATHON_DATA_VARIABLE_CREATED", "hackathon.data.variable.created"),
 		get("NATS_SUBJECT_HACKATHON_DATA_VARIABLE_UPDATED", "hackathon.data.variable.updated"),
 		get("NATS_SUBJECT_HACKATHON_DATA_VARIABLE_DELETED", "hackathon.data.variable.deleted"),
+		get("NATS_SUBJECT_HACKATHON_DATA_VERSION_CREATED", "hackathon.data.version.created"),
 		get("NATS_SUBJECT_HACKATHON_METRIC_CREATED", "hackathon.metric.created"),
 		get("NATS_SUBJECT_HACKATHON_METRIC_UPDATED", "hackathon.metric.updated"),
 		get("NATS_SUBJECT_HACKATHON_METRIC_DELETED", "hackathon.metric.deleted"),
 		get("NATS_SUBJECT_SUBMISSION_LIMITS_CREATED", "hackathon.submission_limits.created"),
 		get("NATS_SUBJECT_SUBMISSION_LIMITS_UPDATED", "hackathon.submission_limits.updated"),
 		get("NATS_SUBJECT_SUBMISSION_LIMITS_DELETED", "hackathon.submission_limits.deleted"),
+		get("NATS_SUBJECT_PARTICIPANT_REGISTERED", "hackathon.participant.registered"),
+		get("NATS_SUBJECT_PARTICIPANT_WITHDRAWN", "hackathon.participant.withdrawn"),
 		get("NATS_SUBJECT_SUBMISSION_CREATED", "submission.created"),
 		get("NATS_SUBJECT_SUBMISSION_LOCKED", "submission.locked"),
 		get("NATS_SUBJECT_SUBMISSION_INVALIDATED", "submission.invalidated"),
+		get("NATS_SUBJECT_SUBMISSION_FINAL_SELECTED", "submission.final.selected"),
+		get("NATS_SUBJECT_SUBMISSION_FINAL_UNSELECTED", "submission.final.unselected"),
+		get("NATS_SUBJECT_SUBMISSION_FINAL_AUTO_SELECTED", "submission.final.auto_selected"),
+		get("NATS_SUBJECT_SUBMISSION_RESCORE_REQUESTED", "submission.rescore.requested"),
+		get("NATS_SUBJECT_SUBMISSION_EVALUATION_TIMED_OUT", "submission.evaluation.timed_out"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_CREATED", "governance.report.created"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_ASSIGNED", "governance.report.assigned"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_UNDER_REVIEW", "governance.report.under_review"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_UPHELD", "governance.report.upheld"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_REJECTED", "governance.report.rejected"),
+		get("NATS_SUBJECT_GOVERNANCE_REPORT_WITHDRAWN", "governance.report.withdrawn"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_CREATED", "governance.appeal.created"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_ASSIGNED", "governance.appeal.assigned"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_UNDER_REVIEW", "governance.appeal.under_review"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_UPHELD", "governance.appeal.upheld"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_REJECTED", "governance.appeal.rejected"),
+		get("NATS_SUBJECT_GOVERNANCE_APPEAL_WITHDRAWN", "governance.appeal.withdrawn"),
 		get("NATS_SUBJECT_EVALUATION_COMPLETED", "evaluation.completed"),
 		get("NATS_SUBJECT_LEADERBOARD_FREEZE", "leaderboard.freeze.requested"),
 		get("NATS_SUBJECT_LEADERBOARD_UNFREEZE", "leaderboard.unfreeze.requested"),
 		get("NATS_SUBJECT_LEADERBOARD_PUBLISH", "leaderboard.publish.requested"),
+		get("NATS_SUBJECT_LEADERBOARD_UPDATED", "leaderboard.updated"),
 		get("NATS_SUBJECT_TEAM_REQUIRED", "hackathon.team.required"),
 		get("NATS_SUBJECT_TEAM_LOCKED", "hackathon.team.locked"),
 		get("NATS_SUBJECT_RULE_CREATED", "hackathon.rule.created"),
 		get("NATS_SUBJECT_RULE_VERSION_LOCKED", "hackathon.rule.version.locked"),
 		get("NATS_SUBJECT_RULE_ACTIVATED", "hackathon.rule.activated"),
+		get("NATS_SUBJECT_RULE_ACCEPTED", "hackathon.rule.accepted"),
 	}
 }
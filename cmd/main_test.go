@@ -41,3 +41,30 @@ func TestEventSubjectsFromEnv_Overrides(t *testing.T) {
 		t.Fatalf("expected first subject override, got %q", subjects[0])
 	}
 }
+
+func TestParseMigrateArgs(t *testing.T) {
+	cmd, err := parseMigrateArgs([]string{"down", "--steps", "2", "--dry-run"})
+	if err != nil || cmd.name != "down" || cmd.steps != 2 || !cmd.dryRun {
+		t.Fatalf("unexpected down command: %+v (%v)", cmd, err)
+	}
+	cmd, err = parseMigrateArgs([]string{"up"})
+	if err != nil || cmd.name != "up" || cmd.dryRun {
+		t.Fatalf("unexpected up command: %+v (%v)", cmd, err)
+	}
+	cmd, err = parseMigrateArgs([]string{"baseline", "-version=1"})
+	if err != nil || cmd.version != 1 {
+		t.Fatalf("unexpected baseline command: %+v (%v)", cmd, err)
+	}
+	for _, args := range [][]string{
+		nil,
+		{"sideways"},
+		{"up", "--steps", "2"},
+		{"down", "--steps", "0"},
+		{"baseline"},
+		{"status", "extra"},
+	} {
+		if _, err := parseMigrateArgs(args); err == nil {
+			t.Fatalf("expected an error for %v", args)
+		}
+	}
+}
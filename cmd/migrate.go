@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/migrations"
+	"github.com/DataInCube/hackathon-service/pkg/migrate"
+)
+
+const migrateUsage = `usage: hackathon-service migrate <command> [flags]
+
+commands:
+  up                      apply all pending migrations
+  down [--steps N]        revert the last N migrations (default 1)
+  status                  list migrations and whether they are applied
+  baseline --version N    mark migrations up to N as applied without running them
+
+flags:
+  --dry-run               print what would run without changing the database`
+
+type migrateCommand struct {
+	name    string
+	dryRun  bool
+	steps   int
+	version int64
+}
+
+func parseMigrateArgs(args []string) (migrateCommand, error) {
+	if len(args) == 0 {
+		return migrateCommand{}, errors.New(migrateUsage)
+	}
+	cmd := migrateCommand{name: args[0]}
+	fs := flag.NewFlagSet("migrate "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&cmd.dryRun, "dry-run", false, "")
+	switch cmd.name {
+	case "up", "status":
+	case "down":
+		fs.IntVar(&cmd.steps, "steps", 1, "")
+	case "baseline":
+		fs.Int64Var(&cmd.version, "version", 0, "")
+	default:
+		return migrateCommand{}, fmt.Errorf("unknown migrate command %q\n%s", cmd.name, migrateUsage)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return migrateCommand{}, fmt.Errorf("%w\n%s", err, migrateUsage)
+	}
+	if fs.NArg() > 0 {
+		return migrateCommand{}, fmt.Errorf("unexpected arguments %v\n%s", fs.Args(), migrateUsage)
+	}
+	if cmd.name == "down" && cmd.steps <= 0 {
+		return migrateCommand{}, errors.New("--steps must be positive")
+	}
+	if cmd.name == "baseline" && cmd.version <= 0 {
+		return migrateCommand{}, errors.New("baseline requires --version N")
+	}
+	return cmd, nil
+}
+
+// runMigrate implements the migrate subcommand.
+func runMigrate(ctx context.Context, db *sql.DB, args []string, out io.Writer) error {
+	cmd, err := parseMigrateArgs(args)
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	var ran []migrate.Migration
+	verb := ""
+	switch cmd.name {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(out, statuses)
+		return nil
+	case "up":
+		ran, err = migrator.Up(ctx, cmd.dryRun)
+		verb = "applied"
+	case "down":
+		ran, err = migrator.Down(ctx, cmd.steps, cmd.dryRun)
+		verb = "reverted"
+	case "baseline":
+		ran, err = migrator.Baseline(ctx, cmd.version, cmd.dryRun)
+		verb = "marked applied"
+	}
+	if err != nil {
+		return err
+	}
+
+	if cmd.dryRun {
+		verb = "would be " + verb
+	}
+	if len(ran) == 0 {
+		fmt.Fprintln(out, "nothing to do")
+		return nil
+	}
+	for _, m := range ran {
+		fmt.Fprintf(out, "%s %s\n", m, verb)
+		if cmd.dryRun && cmd.name != "baseline" {
+			script := m.Up
+			if cmd.name == "down" {
+				script = m.Down
+			}
+			fmt.Fprintf(out, "%s\n", script)
+		}
+	}
+	return nil
+}
+
+func printMigrationStatus(out io.Writer, statuses []migrate.Status) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, st := range statuses {
+		appliedAt := "-"
+		if st.AppliedAt != nil {
+			appliedAt = st.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", st.Version, st.Name, st.State, appliedAt)
+	}
+	_ = w.Flush()
+}
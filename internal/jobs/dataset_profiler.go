@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// DatasetProfilerConfig bounds profiling runs. A run still marked running
+// after StaleAfter is assumed lost and claimed again, up to MaxAttempts.
+type DatasetProfilerConfig struct {
+	StaleAfter  time.Duration
+	MaxAttempts int
+}
+
+// DatasetProfiler works through queued dataset profiles. Profiles are
+// claimed with SKIP LOCKED, so every replica can run one without leader
+// election.
+type DatasetProfiler struct {
+	datasets *services.DatasetService
+	config   DatasetProfilerConfig
+	interval time.Duration
+	logger   *logrus.Logger
+}
+
+func NewDatasetProfiler(datasets *services.DatasetService, config DatasetProfilerConfig, interval time.Duration, logger *logrus.Logger) (*DatasetProfiler, error) {
+	if datasets == nil {
+		return nil, errors.New("dataset profiler requires a dataset service")
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = time.Hour
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &DatasetProfiler{
+		datasets: datasets,
+		config:   config,
+		interval: interval,
+		logger:   logger,
+	}, nil
+}
+
+func (p *DatasetProfiler) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			p.logger.WithError(err).Warn("dataset profiler pass failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce runs queued profiles until the queue is empty.
+func (p *DatasetProfiler) RunOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		profile, err := p.datasets.ProcessNextProfile(ctx, time.Now().UTC().Add(-p.config.StaleAfter), p.config.MaxAttempts)
+		if err != nil {
+			return err
+		}
+		if profile == nil {
+			return nil
+		}
+		metrics.DatasetProfiles.WithLabelValues(profile.Status).Inc()
+		entry := p.logger.WithFields(logrus.Fields{
+			"profile_id": profile.ID,
+			"file_id":    profile.FileID,
+			"status":     profile.Status,
+		})
+		if profile.Error != "" {
+			entry.WithField("error", profile.Error).Warn("dataset profile failed")
+		} else {
+			entry.WithField("rows", profile.RowCount).Info("dataset profile completed")
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// WatchdogActorID is recorded as the actor of audit entries written by the
+// evaluation watchdog.
+const WatchdogActorID = "system:evaluation-watchdog"
+
+// EvaluationWatchdog fails or requeues submissions stuck in
+// queued_for_evaluation or evaluation_running, e.g. after an executor crash.
+// Only the replica holding the advisory lock sweeps.
+type EvaluationWatchdog struct {
+	submissions *services.SubmissionService
+	leader      *Leader
+	defaults    services.EvaluationTimeoutDefaults
+	interval    time.Duration
+	logger      *logrus.Logger
+}
+
+func NewEvaluationWatchdog(submissions *services.SubmissionService, leader *Leader, defaults services.EvaluationTimeoutDefaults, interval time.Duration, logger *logrus.Logger) (*EvaluationWatchdog, error) {
+	if submissions == nil || leader == nil {
+		return nil, errors.New("evaluation watchdog requires a submission service and a leader")
+	}
+	if defaults.Timeout <= 0 {
+		defaults.Timeout = time.Hour
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &EvaluationWatchdog{
+		submissions: submissions,
+		leader:      leader,
+		defaults:    defaults,
+		interval:    interval,
+		logger:      logger,
+	}, nil
+}
+
+func (w *EvaluationWatchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	defer w.leader.Release(context.Background())
+
+	for {
+		leader, err := w.leader.TryAcquire(ctx)
+		if err != nil {
+			w.logger.WithError(err).Warn("evaluation watchdog leader election failed")
+		}
+		if leader {
+			if err := w.RunOnce(ctx, time.Now().UTC()); err != nil {
+				w.logger.WithError(err).Warn("evaluation watchdog sweep failed")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sweeps the evaluations that are stale at now and updates the
+// watchdog metrics. Submissions that fail are skipped and reported in the
+// returned error.
+func (w *EvaluationWatchdog) RunOnce(ctx context.Context, now time.Time) error {
+	sweep, err := w.submissions.TimeOutStaleEvaluations(ctx, now, w.defaults, WatchdogActorID)
+	if sweep != nil {
+		for status, count := range sweep.Stuck {
+			metrics.EvaluationsStuck.WithLabelValues(status).Set(float64(count))
+		}
+		metrics.EvaluationTimeouts.WithLabelValues("requeued").Add(float64(sweep.Requeued))
+		metrics.EvaluationTimeouts.WithLabelValues("failed").Add(float64(sweep.Failed))
+		metrics.EvaluationTimeouts.WithLabelValues("error").Add(float64(sweep.Errors))
+		if sweep.Requeued > 0 || sweep.Failed > 0 {
+			w.logger.WithFields(logrus.Fields{
+				"requeued": sweep.Requeued,
+				"failed":   sweep.Failed,
+			}).Info("evaluation watchdog timed out submissions")
+		}
+	}
+	return err
+}
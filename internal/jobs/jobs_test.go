@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+)
+
+func TestNewLeader_RequiresDB(t *testing.T) {
+	if _, err := NewLeader(nil, LockLifecycleScheduler); err == nil {
+		t.Fatal("expected error for nil db")
+	}
+}
+
+func TestNewLifecycleScheduler_RequiresDependencies(t *testing.T) {
+	if _, err := NewLifecycleScheduler(nil, nil, nil, 0, nil); err == nil {
+		t.Fatal("expected error without hackathon service and leader")
+	}
+	if _, err := NewLifecycleScheduler(services.NewHackathonService(nil, nil), nil, nil, 0, nil); err == nil {
+		t.Fatal("expected error without leader")
+	}
+}
+
+func TestNewEvaluationWatchdog_RequiresDependencies(t *testing.T) {
+	if _, err := NewEvaluationWatchdog(nil, nil, services.EvaluationTimeoutDefaults{}, 0, nil); err == nil {
+		t.Fatal("expected error without submission service and leader")
+	}
+	if _, err := NewEvaluationWatchdog(services.NewSubmissionService(nil, nil, nil), nil, services.EvaluationTimeoutDefaults{}, 0, nil); err == nil {
+		t.Fatal("expected error without leader")
+	}
+}
+
+func TestNewDatasetProfiler_RequiresDatasetService(t *testing.T) {
+	if _, err := NewDatasetProfiler(nil, DatasetProfilerConfig{}, 0, nil); err == nil {
+		t.Fatal("expected error without dataset service")
+	}
+	if _, err := NewDatasetProfiler(services.NewDatasetService(nil, services.DatasetStorage{}, nil), DatasetProfilerConfig{}, 0, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
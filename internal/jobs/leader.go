@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// Advisory lock keys used for leader election between service replicas.
+const (
+	LockLifecycleScheduler int64 = 7_410_001
+	LockEvaluationWatchdog int64 = 7_410_002
+)
+
+// Leader holds a session-level Postgres advisory lock on a dedicated
+// connection. The replica that holds the lock is the leader until the
+// connection drops or Release is called.
+type Leader struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewLeader(db *sql.DB, key int64) (*Leader, error) {
+	if db == nil {
+		return nil, errors.New("leader election requires a database")
+	}
+	return &Leader{db: db, key: key}, nil
+}
+
+// TryAcquire reports whether this replica currently holds the lock, taking it
+// if it is free.
+func (l *Leader) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// The session is gone and the lock with it.
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+func (l *Leader) Release(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+	_, _ = l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key)
+	_ = l.conn.Close()
+	l.conn = nil
+}
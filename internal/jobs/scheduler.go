@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/api/services"
+	"github.com/DataInCube/hackathon-service/internal/metrics"
+	"github.com/DataInCube/hackathon-service/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// SchedulerActorID is recorded as the actor of audit entries written by the scheduler.
+const SchedulerActorID = "system:lifecycle-scheduler"
+
+// maxStepsPerTick bounds how many phases a single hackathon can advance in one
+// tick when several scheduled timestamps are already in the past.
+const maxStepsPerTick = 5
+
+// LifecycleScheduler advances hackathons through their phases when the
+// timestamps in hackathon_schedules pass. Only the replica holding the
+// advisory lock runs transitions.
+type LifecycleScheduler struct {
+	hackathons *services.HackathonService
+	governance *services.GovernanceService
+	leader     *Leader
+	interval   time.Duration
+	logger     *logrus.Logger
+}
+
+func NewLifecycleScheduler(hackathons *services.HackathonService, governance *services.GovernanceService, leader *Leader, interval time.Duration, logger *logrus.Logger) (*LifecycleScheduler, error) {
+	if hackathons == nil || leader == nil {
+		return nil, errors.New("lifecycle scheduler requires a hackathon service and a leader")
+	}
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &LifecycleScheduler{
+		hackathons: hackathons,
+		governance: governance,
+		leader:     leader,
+		interval:   interval,
+		logger:     logger,
+	}, nil
+}
+
+func (s *LifecycleScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.leader.Release(context.Background())
+
+	for {
+		leader, err := s.leader.TryAcquire(ctx)
+		if err != nil {
+			s.logger.WithError(err).Warn("lifecycle scheduler leader election failed")
+		}
+		if leader {
+			if err := s.RunOnce(ctx, time.Now().UTC()); err != nil {
+				s.logger.WithError(err).Warn("lifecycle scheduler tick failed")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies every transition due at now. A hackathon whose transition
+// fails is skipped until the next tick; the error is kept on its schedule.
+func (s *LifecycleScheduler) RunOnce(ctx context.Context, now time.Time) error {
+	failed := map[string]bool{}
+	for step := 0; step < maxStepsPerTick; step++ {
+		due, err := s.hackathons.DueScheduledTransitions(ctx, now)
+		if err != nil {
+			return err
+		}
+		progressed := false
+		for _, st := range due {
+			if failed[st.HackathonID] {
+				continue
+			}
+			if s.apply(ctx, st) {
+				progressed = true
+			} else {
+				failed[st.HackathonID] = true
+			}
+		}
+		if !progressed {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *LifecycleScheduler) apply(ctx context.Context, st services.ScheduledTransition) bool {
+	log := s.logger.WithFields(logrus.Fields{
+		"hackathon_id": st.HackathonID,
+		"from":         st.From,
+		"to":           st.Target,
+	})
+
+	updated, err := s.hackathons.RunScheduledTransition(ctx, st)
+	if err != nil {
+		metrics.ScheduledTransitions.WithLabelValues(st.Target, "failed").Inc()
+		log.WithError(err).Warn("scheduled transition failed")
+		return false
+	}
+	metrics.ScheduledTransitions.WithLabelValues(st.Target, "applied").Inc()
+	log.Info("scheduled transition applied")
+	s.audit(ctx, updated)
+	return true
+}
+
+func (s *LifecycleScheduler) audit(ctx context.Context, h *models.Hackathon) {
+	if s.governance == nil || h == nil {
+		return
+	}
+	raw, _ := json.Marshal(h)
+	if err := s.governance.AppendAudit(ctx, models.AuditLog{
+		HackathonID: h.ID,
+		ActorID:     SchedulerActorID,
+		Action:      "hackathon.phase.changed",
+		Payload:     raw,
+	}); err != nil {
+		s.logger.WithError(err).WithField("hackathon_id", h.ID).Warn("scheduled transition audit failed")
+	}
+}
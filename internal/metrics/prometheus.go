@@ -22,4 +22,64 @@ var (
 		},
 		[]string{"method", "path"},
 	)
+
+	OutboxPending = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "event_outbox_pending",
+			Help: "Number of outbox events not yet delivered to NATS",
+		},
+	)
+
+	OutboxLag = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "event_outbox_lag_seconds",
+			Help: "Age of the oldest undelivered outbox event",
+		},
+	)
+
+	OutboxDelivered = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "event_outbox_delivered_total",
+			Help: "Number of outbox events delivered to NATS",
+		},
+	)
+
+	OutboxPublishFailures = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "event_outbox_publish_failures_total",
+			Help: "Number of failed outbox delivery attempts",
+		},
+	)
+
+	ScheduledTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hackathon_scheduled_transitions_total",
+			Help: "Number of lifecycle transitions attempted by the scheduler",
+		},
+		[]string{"to_state", "result"},
+	)
+
+	EvaluationsStuck = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "submission_evaluations_stuck",
+			Help: "Number of submissions found past their evaluation timeout by the last watchdog pass",
+		},
+		[]string{"status"},
+	)
+
+	EvaluationTimeouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "submission_evaluation_timeouts_total",
+			Help: "Number of timed-out evaluations handled by the watchdog",
+		},
+		[]string{"result"},
+	)
+
+	DatasetProfiles = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dataset_profiles_total",
+			Help: "Number of dataset profiling runs finished by the profiler",
+		},
+		[]string{"status"},
+	)
 )
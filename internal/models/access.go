@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// HackathonMember is an allowlisted user of a private or invite-only
+// hackathon, added by an organizer or by redeeming InviteID.
+type HackathonMember struct {
+	ID          string    `json:"id"`
+	HackathonID string    `json:"hackathon_id"`
+	UserID      string    `json:"user_id"`
+	InviteID    *string   `json:"invite_id,omitempty"`
+	AddedBy     string    `json:"added_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HackathonInvite is a shareable code that adds its redeemer to the members
+// of a hackathon. MaxUses 0 means unlimited.
+type HackathonInvite struct {
+	ID          string     `json:"id"`
+	HackathonID string     `json:"hackathon_id"`
+	Code        string     `json:"code"`
+	MaxUses     int        `json:"max_uses"`
+	Uses        int        `json:"uses"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// HackathonStaff assigns a user one role on one hackathon. A user may hold
+// several roles.
+type HackathonStaff struct {
+	ID          string    `json:"id"`
+	HackathonID string    `json:"hackathon_id"`
+	UserID      string    `json:"user_id"`
+	Role        string    `json:"role"`
+	AssignedBy  string    `json:"assigned_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -3,10 +3,16 @@ package models
 import "time"
 
 type Appeal struct {
-	ID          string    `json:"id"`
-	SubmissionID string   `json:"submission_id"`
-	AppellantID string    `json:"appellant_id,omitempty"`
-	Content     string    `json:"content"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              string     `json:"id"`
+	HackathonID     string     `json:"hackathon_id"`
+	SubmissionID    string     `json:"submission_id"`
+	AppellantID     string     `json:"appellant_id,omitempty"`
+	Content         string     `json:"content"`
+	Status          string     `json:"status"`
+	AssigneeID      string     `json:"assignee_id,omitempty"`
+	ResolutionNotes string     `json:"resolution_notes,omitempty"`
+	Resolution      string     `json:"resolution,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
 }
@@ -11,11 +11,42 @@ const (
 	HackathonStateArchived         = "archived"
 )
 
+// Public hackathons are visible to everyone once published. Invite-only ones
+// are visible but need an invite or allowlist entry to register; private ones
+// are hidden from non-members altogether.
+const (
+	HackathonVisibilityPublic     = "public"
+	HackathonVisibilityPrivate    = "private"
+	HackathonVisibilityInviteOnly = "invite_only"
+)
+
+// Per-hackathon staff roles. Owners pass every staff check of their
+// hackathon.
+const (
+	StaffRoleOwner     = "owner"
+	StaffRoleOrganizer = "organizer"
+	StaffRoleJudge     = "judge"
+	StaffRoleEvaluator = "evaluator"
+	StaffRoleModerator = "moderator"
+)
+
+// Team policies summarise allows_teams/requires_teams for search facets.
+const (
+	TeamPolicySolo     = "solo"
+	TeamPolicyOptional = "optional"
+	TeamPolicyRequired = "required"
+)
+
 const (
 	RuleStatusDraft  = "draft"
 	RuleStatusLocked = "locked"
 )
 
+const (
+	ParticipantStatusRegistered = "registered"
+	ParticipantStatusWithdrawn  = "withdrawn"
+)
+
 const (
 	SubmissionStatusCreated           = "created"
 	SubmissionStatusQueuedForEval     = "queued_for_evaluation"
@@ -25,6 +56,24 @@ const (
 	SubmissionStatusInvalidated       = "invalidated"
 )
 
+const (
+	GovernanceStatusOpen        = "open"
+	GovernanceStatusUnderReview = "under_review"
+	GovernanceStatusUpheld      = "upheld"
+	GovernanceStatusRejected    = "rejected"
+	GovernanceStatusWithdrawn   = "withdrawn"
+)
+
+const (
+	AppealActionInvalidate = "invalidate"
+	AppealActionReevaluate = "reevaluate"
+)
+
+const (
+	FinalSelectionManual = "manual"
+	FinalSelectionAuto   = "auto"
+)
+
 const (
 	DatasetFileTypeTrain            = "train"
 	DatasetFileTypeTest             = "test"
@@ -33,6 +82,34 @@ const (
 	DatasetFileTypeOther            = "other"
 )
 
+const (
+	DatasetUploadStatusPending = "pending"
+	// DatasetUploadStatusUploaded means every byte was received and the
+	// parts are waiting to be assembled into the dataset file.
+	DatasetUploadStatusUploaded  = "uploaded"
+	DatasetUploadStatusCompleted = "completed"
+	DatasetUploadStatusAborted   = "aborted"
+)
+
+const (
+	DatasetProfileStatusPending   = "pending"
+	DatasetProfileStatusRunning   = "running"
+	DatasetProfileStatusCompleted = "completed"
+	DatasetProfileStatusFailed    = "failed"
+)
+
+const (
+	DatasetVersionReasonPublished         = "published"
+	DatasetVersionReasonLive              = "live"
+	DatasetVersionReasonRuleVersionLocked = "rule_version_locked"
+	DatasetVersionReasonManual            = "manual"
+)
+
+const (
+	DatasetFileFormatCSV     = "csv"
+	DatasetFileFormatParquet = "parquet"
+)
+
 const (
 	DatasetVariableRoleFeature    = "feature"
 	DatasetVariableRoleTarget     = "target"
@@ -59,3 +136,8 @@ const (
 	MetricScopeOverall   = "overall"
 	MetricScopePerTarget = "per_target"
 )
+
+const (
+	LeaderboardBoardPublic  = "public"
+	LeaderboardBoardPrivate = "private"
+)
@@ -6,14 +6,22 @@ import (
 )
 
 type Dataset struct {
-	ID             string          `json:"id"`
-	HackathonID    string          `json:"hackathon_id"`
-	Title          string          `json:"title"`
-	Description    string          `json:"description"`
-	SourceURLs     []string        `json:"source_urls,omitempty"`
-	ResponseSchema json.RawMessage `json:"response_schema,omitempty"`
-	CreatedAt      time.Time       `json:"created_at"`
-	UpdatedAt      time.Time       `json:"updated_at"`
+	ID             string                `json:"id"`
+	HackathonID    string                `json:"hackathon_id"`
+	Title          string                `json:"title"`
+	Description    string                `json:"description"`
+	SourceURLs     []string              `json:"source_urls,omitempty"`
+	ResponseSchema json.RawMessage       `json:"response_schema,omitempty"`
+	TargetColumns  []DatasetTargetColumn `json:"target_columns,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// DatasetTargetColumn names a column that profiling proposes as a target,
+// with the category given to its variable.
+type DatasetTargetColumn struct {
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
 }
 
 type DatasetFile struct {
@@ -25,10 +33,90 @@ type DatasetFile struct {
 	URL         string    `json:"url"`
 	SizeBytes   int64     `json:"size_bytes,omitempty"`
 	Checksum    string    `json:"checksum,omitempty"`
+	StorageKey  string    `json:"storage_key,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// DatasetUpload is a resumable upload session. Chunks are appended at
+// OffsetBytes until SizeBytes is reached, which creates File.
+type DatasetUpload struct {
+	ID          string       `json:"id"`
+	HackathonID string       `json:"hackathon_id"`
+	DatasetID   string       `json:"dataset_id"`
+	Name        string       `json:"name"`
+	FileType    string       `json:"file_type"`
+	Description string       `json:"description,omitempty"`
+	ContentType string       `json:"content_type,omitempty"`
+	SizeBytes   int64        `json:"size_bytes"`
+	OffsetBytes int64        `json:"offset_bytes"`
+	Parts       int          `json:"parts"`
+	Status      string       `json:"status"`
+	FileID      string       `json:"file_id,omitempty"`
+	File        *DatasetFile `json:"file,omitempty"`
+	CreatedBy   string       `json:"created_by,omitempty"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// DatasetFileDownload is a download link; ExpiresAt is nil for files that
+// only reference an external URL.
+type DatasetFileDownload struct {
+	URL       string     `json:"url"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// DatasetProfile is a profiling run over one uploaded CSV or Parquet file.
+// Columns holds the per-column statistics and the proposed variables once
+// Status is completed.
+type DatasetProfile struct {
+	ID          string                 `json:"id"`
+	DatasetID   string                 `json:"dataset_id"`
+	FileID      string                 `json:"file_id"`
+	Format      string                 `json:"format"`
+	Status      string                 `json:"status"`
+	RowCount    int64                  `json:"row_count"`
+	Columns     []DatasetColumnProfile `json:"columns"`
+	Error       string                 `json:"error,omitempty"`
+	Attempts    int                    `json:"attempts"`
+	RequestedBy string                 `json:"requested_by,omitempty"`
+	StartedAt   *time.Time             `json:"started_at,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	AcceptedAt  *time.Time             `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// DatasetColumnProfile describes one column. DataType and Role are the
+// proposal; Min and Max are numbers for numeric columns and RFC 3339 strings
+// for datetime columns. Cardinality stops counting at the profiler's cap, in
+// which case CardinalityCapped is set.
+type DatasetColumnProfile struct {
+	Name              string  `json:"name"`
+	DataType          string  `json:"data_type"`
+	Role              string  `json:"role"`
+	Category          string  `json:"category,omitempty"`
+	Count             int64   `json:"count"`
+	Nulls             int64   `json:"nulls"`
+	NullRatio         float64 `json:"null_ratio"`
+	Cardinality       int64   `json:"cardinality"`
+	CardinalityCapped bool    `json:"cardinality_capped,omitempty"`
+	Min               any     `json:"min,omitempty"`
+	Max               any     `json:"max,omitempty"`
+	// VariableID is set when the dataset already has a variable with this name.
+	VariableID string `json:"variable_id,omitempty"`
+}
+
+// DatasetProfileAcceptance reports the variables created from a profile and
+// the columns skipped because a variable with that name already existed.
+type DatasetProfileAcceptance struct {
+	Profile *DatasetProfile   `json:"profile"`
+	Created []DatasetVariable `json:"created"`
+	Skipped []string          `json:"skipped"`
+}
+
 type DatasetVariable struct {
 	ID          string    `json:"id"`
 	DatasetID   string    `json:"dataset_id"`
@@ -41,3 +129,60 @@ type DatasetVariable struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// DatasetVersion is an immutable snapshot of a hackathon's dataset. Versions
+// are numbered per hackathon and only created when the content changed since
+// the previous one; Checksum is the SHA-256 of the encoded content. Content
+// is omitted when listing versions.
+type DatasetVersion struct {
+	ID          string           `json:"id"`
+	HackathonID string           `json:"hackathon_id"`
+	DatasetID   string           `json:"dataset_id"`
+	Version     int              `json:"version"`
+	Reason      string           `json:"reason"`
+	Checksum    string           `json:"checksum"`
+	Content     *DatasetSnapshot `json:"content,omitempty"`
+	CreatedBy   string           `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// DatasetSnapshot is the frozen content of a dataset version. Files and
+// variables are keyed by name so diffs between versions read as per-file and
+// per-variable changes.
+type DatasetSnapshot struct {
+	Title          string                             `json:"title"`
+	Description    string                             `json:"description"`
+	SourceURLs     []string                           `json:"source_urls"`
+	ResponseSchema json.RawMessage                    `json:"response_schema"`
+	Files          map[string]DatasetSnapshotFile     `json:"files"`
+	Variables      map[string]DatasetSnapshotVariable `json:"variables"`
+}
+
+type DatasetSnapshotFile struct {
+	ID          string `json:"id"`
+	FileType    string `json:"file_type"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`
+	SizeBytes   int64  `json:"size_bytes,omitempty"`
+	Checksum    string `json:"checksum,omitempty"`
+	StorageKey  string `json:"storage_key,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+type DatasetSnapshotVariable struct {
+	ID          string `json:"id"`
+	Role        string `json:"role"`
+	DataType    string `json:"data_type"`
+	Description string `json:"description,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+	Category    string `json:"category,omitempty"`
+}
+
+type DatasetVersionDiff struct {
+	HackathonID   string       `json:"hackathon_id"`
+	FromVersion   int          `json:"from_version"`
+	ToVersion     int          `json:"to_version"`
+	FromVersionID string       `json:"from_version_id"`
+	ToVersionID   string       `json:"to_version_id"`
+	Changes       []JSONChange `json:"changes"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "encoding/json"
+
+const EvaluationResultVersion = "v1"
+
+// EvaluationResult is the body of the score callback. Metrics and
+// PrivateMetrics are keyed by EvaluationMetric.Name; the primary metric value
+// becomes the submission's public and private score.
+type EvaluationResult struct {
+	Version            string             `json:"version"`
+	JobID              string             `json:"job_id,omitempty"`
+	Executor           string             `json:"executor,omitempty"`
+	CommitSHA          string             `json:"commit_sha,omitempty"`
+	Metrics            map[string]float64 `json:"metrics"`
+	PrivateMetrics     map[string]float64 `json:"private_metrics,omitempty"`
+	UpdatesLeaderboard *bool              `json:"updates_leaderboard,omitempty"`
+	Details            json.RawMessage    `json:"details,omitempty"`
+}
@@ -17,6 +17,7 @@ type Hackathon struct {
 	RequiresTeams         bool            `json:"requires_teams"`
 	MinTeamSize           int             `json:"min_team_size,omitempty"`
 	MaxTeamSize           int             `json:"max_team_size,omitempty"`
+	MaxParticipants       int             `json:"max_participants,omitempty"`
 	ActiveRuleVersionID   *string         `json:"active_rule_version_id,omitempty"`
 	LeaderboardFrozen     bool            `json:"leaderboard_frozen"`
 	LeaderboardPublished  bool            `json:"leaderboard_published"`
@@ -28,3 +29,31 @@ type Hackathon struct {
 	CompletedAt           *time.Time      `json:"completed_at,omitempty"`
 	ArchivedAt            *time.Time      `json:"archived_at,omitempty"`
 }
+
+// HackathonSearchHit is a hackathon matched by a search with its relevance
+// rank and highlighted snippets. Highlights is omitted for filter-only
+// searches.
+type HackathonSearchHit struct {
+	Hackathon
+	Rank       float64              `json:"rank"`
+	Highlights *HackathonHighlights `json:"highlights,omitempty"`
+}
+
+// HackathonHighlights holds ts_headline snippets with matches wrapped in
+// <mark> tags. The surrounding text is HTML-escaped, so <mark> is the only
+// markup.
+type HackathonHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type HackathonSearchResult struct {
+	Items  []HackathonSearchHit    `json:"items"`
+	Total  int                     `json:"total"`
+	Facets map[string][]FacetCount `json:"facets"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// HackathonSchedule holds the timestamps at which the lifecycle scheduler moves
+// a hackathon into the next phase. Unset phases are left to manual transitions.
+type HackathonSchedule struct {
+	HackathonID      string     `json:"hackathon_id"`
+	WarmupAt         *time.Time `json:"warmup_at,omitempty"`
+	LiveAt           *time.Time `json:"live_at,omitempty"`
+	FreezeAt         *time.Time `json:"freeze_at,omitempty"`
+	EvaluationOnlyAt *time.Time `json:"evaluation_only_at,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastAttemptAt    *time.Time `json:"last_attempt_at,omitempty"`
+	FailedAttempts   int        `json:"failed_attempts,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "encoding/json"
+
+// JSONChange is a single JSON-patch style difference. Path is a JSON pointer
+// (RFC 6901); Op is one of add, remove or replace. OldValue is set for remove
+// and replace, Value for add and replace; a JSON null is kept as null.
+type JSONChange struct {
+	Op       string          `json:"op"`
+	Path     string          `json:"path"`
+	OldValue json.RawMessage `json:"old_value,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+}
+
+const (
+	JSONChangeAdd     = "add"
+	JSONChangeRemove  = "remove"
+	JSONChangeReplace = "replace"
+)
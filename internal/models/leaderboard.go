@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// LeaderboardEntry is the best scored submission of one participant (a team
+// when the submission has a team_id, otherwise the submitting user).
+type LeaderboardEntry struct {
+	Rank            int       `json:"rank"`
+	ParticipantKey  string    `json:"participant_key"`
+	UserID          string    `json:"user_id"`
+	TeamID          *string   `json:"team_id,omitempty"`
+	SubmissionID    string    `json:"submission_id"`
+	Score           float64   `json:"score"`
+	CompositeScore  *float64  `json:"composite_score,omitempty"`
+	SubmissionCount int       `json:"submission_count"`
+	ScoredAt        time.Time `json:"scored_at"`
+}
+
+type Leaderboard struct {
+	HackathonID   string             `json:"hackathon_id"`
+	Board         string             `json:"board"`
+	PrimaryMetric string             `json:"primary_metric,omitempty"`
+	Direction     string             `json:"direction"`
+	Frozen        bool               `json:"frozen"`
+	Published     bool               `json:"published"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"`
+	Total         int                `json:"total"`
+	Entries       []LeaderboardEntry `json:"entries"`
+}
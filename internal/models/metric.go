@@ -20,3 +20,19 @@ type EvaluationMetric struct {
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 }
+
+// ScoreVerification is a metric recomputed locally from uploaded ground-truth
+// and prediction files. Reported and Difference are set when the result is
+// compared with a submission's score.
+type ScoreVerification struct {
+	MetricID       string   `json:"metric_id"`
+	MetricName     string   `json:"metric_name"`
+	MetricType     string   `json:"metric_type"`
+	TargetVariable string   `json:"target_variable"`
+	KeyColumns     []string `json:"key_columns,omitempty"`
+	Rows           int      `json:"rows"`
+	Score          float64  `json:"score"`
+	SubmissionID   string   `json:"submission_id,omitempty"`
+	Reported       *float64 `json:"reported,omitempty"`
+	Difference     *float64 `json:"difference,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+const (
+	MetricParamNumber      = "number"
+	MetricParamInteger     = "integer"
+	MetricParamBoolean     = "boolean"
+	MetricParamString      = "string"
+	MetricParamNumberRange = "number_range"
+)
+
+// MetricParamSpec describes one accepted key of EvaluationMetric.Params.
+// Minimum and Maximum bound numbers, integers and both ends of a number range.
+type MetricParamSpec struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Default     any      `json:"default,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// MetricTypeSpec lists the params accepted by a metric_type and the direction
+// it is usually optimized in. FreeForm types accept any params object. Range
+// is the [min, max] the composite score scales values over, unset for
+// unbounded metrics.
+type MetricTypeSpec struct {
+	Type      string            `json:"type"`
+	Direction string            `json:"direction,omitempty"`
+	Range     []float64         `json:"range,omitempty"`
+	FreeForm  bool              `json:"free_form,omitempty"`
+	Params    []MetricParamSpec `json:"params"`
+}
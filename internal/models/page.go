@@ -0,0 +1,10 @@
+package models
+
+// Page is one page of a list endpoint. NextCursor is passed back as ?cursor=
+// to fetch the following page and is null on the last one; Total counts every
+// item matching the filters.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      int     `json:"total"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Participant struct {
+	ID              string     `json:"id"`
+	HackathonID     string     `json:"hackathon_id"`
+	UserID          string     `json:"user_id"`
+	Status          string     `json:"status"`
+	RuleVersionID   string     `json:"rule_version_id"`
+	RulesAcceptedAt time.Time  `json:"rules_accepted_at"`
+	RegisteredAt    time.Time  `json:"registered_at"`
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -3,11 +3,15 @@ package models
 import "time"
 
 type Report struct {
-	ID          string    `json:"id"`
-	HackathonID string    `json:"hackathon_id"`
-	ReporterID  string    `json:"reporter_id,omitempty"`
-	Type        string    `json:"type"`
-	Content     string    `json:"content"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              string     `json:"id"`
+	HackathonID     string     `json:"hackathon_id"`
+	ReporterID      string     `json:"reporter_id,omitempty"`
+	Type            string     `json:"type"`
+	Content         string     `json:"content"`
+	Status          string     `json:"status"`
+	AssigneeID      string     `json:"assignee_id,omitempty"`
+	ResolutionNotes string     `json:"resolution_notes,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
 }
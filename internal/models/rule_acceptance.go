@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// RuleAcceptance records that a user agreed to a specific locked rule version.
+// ContentHash is the SHA-256 of the version content at acceptance time.
+type RuleAcceptance struct {
+	ID            string    `json:"id"`
+	HackathonID   string    `json:"hackathon_id"`
+	RuleVersionID string    `json:"rule_version_id"`
+	UserID        string    `json:"user_id"`
+	ContentHash   string    `json:"content_hash"`
+	AcceptedAt    time.Time `json:"accepted_at"`
+}
+
+type RuleAcceptanceReport struct {
+	RuleVersionID string           `json:"rule_version_id"`
+	RuleID        string           `json:"rule_id"`
+	Version       int              `json:"version"`
+	ContentHash   string           `json:"content_hash"`
+	Active        bool             `json:"active"`
+	Accepted      []RuleAcceptance `json:"accepted"`
+	Pending       []string         `json:"pending"`
+}
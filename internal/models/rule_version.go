@@ -14,4 +14,16 @@ type RuleVersion struct {
 	CreatedBy string          `json:"created_by,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
 	LockedAt  *time.Time      `json:"locked_at,omitempty"`
+	// DatasetVersionID is the dataset snapshot taken when the version was
+	// locked.
+	DatasetVersionID *string `json:"dataset_version_id,omitempty"`
+}
+
+type RuleVersionDiff struct {
+	RuleID        string       `json:"rule_id"`
+	FromVersion   int          `json:"from_version"`
+	ToVersion     int          `json:"to_version"`
+	FromVersionID string       `json:"from_version_id"`
+	ToVersionID   string       `json:"to_version_id"`
+	Changes       []JSONChange `json:"changes"`
 }
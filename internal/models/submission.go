@@ -5,18 +5,39 @@ import (
 	"time"
 )
 
+// Submission.PrivateScore is hidden from participants until the leaderboard is
+// published; CompositeScore is the weighted score over all hackathon metrics;
+// FinalSelection is "manual" or "auto" when picked for private scoring.
+// DatasetVersionID is the dataset snapshot current when it was made; it is
+// nil when the hackathon had no dataset version yet.
 type Submission struct {
-	ID            string          `json:"id"`
-	HackathonID   string          `json:"hackathon_id"`
-	TrackID       *string         `json:"track_id,omitempty"`
-	RuleVersionID string          `json:"rule_version_id"`
-	SubmittedBy   string          `json:"submitted_by"`
-	TeamID        *string         `json:"team_id,omitempty"`
-	Status        string          `json:"status"`
-	Phase         string          `json:"phase"`
-	Metadata      json.RawMessage `json:"metadata,omitempty"`
-	CreatedAt     time.Time       `json:"created_at"`
-	UpdatedAt     time.Time       `json:"updated_at"`
-	LockedAt      *time.Time      `json:"locked_at,omitempty"`
-	InvalidatedAt *time.Time      `json:"invalidated_at,omitempty"`
+	ID               string          `json:"id"`
+	HackathonID      string          `json:"hackathon_id"`
+	TrackID          *string         `json:"track_id,omitempty"`
+	RuleVersionID    string          `json:"rule_version_id"`
+	DatasetVersionID *string         `json:"dataset_version_id,omitempty"`
+	SubmittedBy      string          `json:"submitted_by"`
+	TeamID           *string         `json:"team_id,omitempty"`
+	Status           string          `json:"status"`
+	Phase            string          `json:"phase"`
+	Metadata         json.RawMessage `json:"metadata,omitempty"`
+	PublicScore      *float64        `json:"public_score,omitempty"`
+	PrivateScore     *float64        `json:"private_score,omitempty"`
+	CompositeScore   *float64        `json:"composite_score,omitempty"`
+	FinalSelection   string          `json:"final_selection,omitempty"`
+	FinalSelectedAt  *time.Time      `json:"final_selected_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+	LockedAt         *time.Time      `json:"locked_at,omitempty"`
+	InvalidatedAt    *time.Time      `json:"invalidated_at,omitempty"`
+}
+
+// FinalSelections lists the submissions a participant picked (or had picked
+// automatically) for private scoring.
+type FinalSelections struct {
+	HackathonID        string       `json:"hackathon_id"`
+	ParticipantKey     string       `json:"participant_key"`
+	MaxFinalSelections int          `json:"max_final_selections"`
+	Remaining          int          `json:"remaining"`
+	Submissions        []Submission `json:"submissions"`
 }
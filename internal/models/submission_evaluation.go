@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SubmissionEvaluation is one evaluation attempt of a submission. Attempts
+// are superseded, not deleted, when the submission is rescored.
+type SubmissionEvaluation struct {
+	ID              string             `json:"id"`
+	SubmissionID    string             `json:"submission_id"`
+	HackathonID     string             `json:"hackathon_id"`
+	Attempt         int                `json:"attempt"`
+	JobID           string             `json:"job_id,omitempty"`
+	Executor        string             `json:"executor,omitempty"`
+	Status          string             `json:"status"`
+	Error           string             `json:"error,omitempty"`
+	PublicScore     *float64           `json:"public_score,omitempty"`
+	PrivateScore    *float64           `json:"private_score,omitempty"`
+	CompositeScore  *float64           `json:"composite_score,omitempty"`
+	SecondaryScores map[string]float64 `json:"secondary_scores,omitempty"`
+	Metadata        json.RawMessage    `json:"metadata,omitempty"`
+	StartedAt       *time.Time         `json:"started_at,omitempty"`
+	FinishedAt      *time.Time         `json:"finished_at,omitempty"`
+	SupersededAt    *time.Time         `json:"superseded_at,omitempty"`
+	RescoreReason   string             `json:"rescore_reason,omitempty"`
+	RequestedBy     string             `json:"requested_by,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// RescoreResult summarizes a bulk rescore request.
+type RescoreResult struct {
+	HackathonID   string   `json:"hackathon_id"`
+	Reason        string   `json:"reason,omitempty"`
+	Requeued      int      `json:"requeued"`
+	SubmissionIDs []string `json:"submission_ids"`
+}
@@ -2,13 +2,34 @@ package models
 
 import "time"
 
+// SubmissionLimit.EvaluationTimeoutSeconds and MaxEvaluationAttempts tune the
+// evaluation watchdog; 0 falls back to the service defaults.
 type SubmissionLimit struct {
-	ID          string    `json:"id"`
-	HackathonID string    `json:"hackathon_id"`
-	PerDay      int       `json:"per_day"`
-	Total       int       `json:"total"`
-	PerTeam     int       `json:"per_team"`
-	Notes       string    `json:"notes,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                       string    `json:"id"`
+	HackathonID              string    `json:"hackathon_id"`
+	PerDay                   int       `json:"per_day"`
+	Total                    int       `json:"total"`
+	PerTeam                  int       `json:"per_team"`
+	MaxFinalSelections       int       `json:"max_final_selections"`
+	EvaluationTimeoutSeconds int       `json:"evaluation_timeout_seconds"`
+	MaxEvaluationAttempts    int       `json:"max_evaluation_attempts"`
+	Notes                    string    `json:"notes,omitempty"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+type SubmissionQuota struct {
+	HackathonID    string    `json:"hackathon_id"`
+	UserID         string    `json:"user_id"`
+	TeamID         *string   `json:"team_id,omitempty"`
+	PerDay         int       `json:"per_day"`
+	Total          int       `json:"total"`
+	PerTeam        int       `json:"per_team"`
+	UsedToday      int       `json:"used_today"`
+	UsedTotal      int       `json:"used_total"`
+	UsedByTeam     int       `json:"used_by_team"`
+	RemainingToday *int      `json:"remaining_today"`
+	RemainingTotal *int      `json:"remaining_total"`
+	RemainingTeam  *int      `json:"remaining_team"`
+	ResetsAt       time.Time `json:"resets_at"`
 }
@@ -0,0 +1,14 @@
+DROP TABLE audit_logs;
+DROP TABLE appeals;
+DROP TABLE reports;
+DROP TABLE resources;
+DROP TABLE submissions;
+DROP TABLE rule_versions;
+DROP TABLE rules;
+DROP TABLE tracks;
+DROP TABLE submission_limits;
+DROP TABLE evaluation_metrics;
+DROP TABLE dataset_variables;
+DROP TABLE dataset_files;
+DROP TABLE hackathon_datasets;
+DROP TABLE hackathons;
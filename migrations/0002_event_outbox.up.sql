@@ -0,0 +1,14 @@
+-- Transactional outbox drained to NATS by the outbox relay.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id UUID PRIMARY KEY,
+    subject TEXT NOT NULL,
+    envelope JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS event_outbox_pending_idx ON event_outbox (next_attempt_at) WHERE delivered_at IS NULL;
@@ -0,0 +1,14 @@
+-- Planned lifecycle transitions applied by the scheduler.
+CREATE TABLE IF NOT EXISTS hackathon_schedules (
+    hackathon_id UUID PRIMARY KEY REFERENCES hackathons(id) ON DELETE CASCADE,
+    warmup_at TIMESTAMPTZ,
+    live_at TIMESTAMPTZ,
+    freeze_at TIMESTAMPTZ,
+    evaluation_only_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    last_error TEXT,
+    last_attempt_at TIMESTAMPTZ,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
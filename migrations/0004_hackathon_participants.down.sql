@@ -0,0 +1,2 @@
+DROP TABLE hackathon_participants;
+ALTER TABLE hackathons DROP COLUMN max_participants;
@@ -0,0 +1,25 @@
+-- Participant registration and the optional participant cap.
+ALTER TABLE hackathons ADD COLUMN IF NOT EXISTS max_participants INTEGER NOT NULL DEFAULT 0;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'hackathons_max_participants_check') THEN
+        ALTER TABLE hackathons ADD CONSTRAINT hackathons_max_participants_check CHECK (max_participants >= 0);
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS hackathon_participants (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    rule_version_id UUID NOT NULL REFERENCES rule_versions(id) ON DELETE RESTRICT,
+    rules_accepted_at TIMESTAMPTZ NOT NULL,
+    registered_at TIMESTAMPTZ NOT NULL,
+    withdrawn_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (hackathon_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS hackathon_participants_hackathon_status_idx ON hackathon_participants (hackathon_id, status);
@@ -0,0 +1,12 @@
+-- Which rule version each user accepted, and the content they saw.
+CREATE TABLE IF NOT EXISTS rule_acceptances (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    rule_version_id UUID NOT NULL REFERENCES rule_versions(id) ON DELETE RESTRICT,
+    user_id TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    accepted_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (hackathon_id, rule_version_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS rule_acceptances_rule_version_id_idx ON rule_acceptances (rule_version_id);
@@ -0,0 +1,18 @@
+-- Materialized leaderboard projection, rebuilt from scored submissions.
+CREATE TABLE IF NOT EXISTS leaderboard_entries (
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    board TEXT NOT NULL CHECK (board IN ('public', 'private')),
+    snapshot BOOLEAN NOT NULL DEFAULT false,
+    participant_key TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    team_id TEXT,
+    submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    submission_count INTEGER NOT NULL,
+    scored_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (hackathon_id, board, snapshot, participant_key)
+);
+
+CREATE INDEX IF NOT EXISTS leaderboard_entries_rank_idx ON leaderboard_entries (hackathon_id, board, snapshot, rank);
@@ -0,0 +1,6 @@
+ALTER TABLE submission_limits DROP COLUMN max_final_selections;
+DROP INDEX submissions_final_selection_idx;
+ALTER TABLE submissions DROP COLUMN final_selected_at;
+ALTER TABLE submissions DROP COLUMN final_selection;
+ALTER TABLE submissions DROP COLUMN private_score;
+ALTER TABLE submissions DROP COLUMN public_score;
@@ -0,0 +1,9 @@
+-- Public/private scores and final-submission selection.
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS public_score DOUBLE PRECISION;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS private_score DOUBLE PRECISION;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS final_selection TEXT CHECK (final_selection IN ('manual', 'auto'));
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS final_selected_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS submissions_final_selection_idx ON submissions (hackathon_id) WHERE final_selection IS NOT NULL;
+
+ALTER TABLE submission_limits ADD COLUMN IF NOT EXISTS max_final_selections INTEGER NOT NULL DEFAULT 0;
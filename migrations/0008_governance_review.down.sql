@@ -0,0 +1,12 @@
+DROP INDEX appeals_hackathon_status_idx;
+ALTER TABLE appeals DROP COLUMN resolved_at;
+ALTER TABLE appeals DROP COLUMN updated_at;
+ALTER TABLE appeals DROP COLUMN resolution;
+ALTER TABLE appeals DROP COLUMN resolution_notes;
+ALTER TABLE appeals DROP COLUMN assignee_id;
+ALTER TABLE appeals DROP COLUMN hackathon_id;
+DROP INDEX reports_hackathon_status_idx;
+ALTER TABLE reports DROP COLUMN resolved_at;
+ALTER TABLE reports DROP COLUMN updated_at;
+ALTER TABLE reports DROP COLUMN resolution_notes;
+ALTER TABLE reports DROP COLUMN assignee_id;
@@ -0,0 +1,23 @@
+-- Review workflow for reports and appeals. Existing rows take their
+-- creation time as last update, and appeals their submission's hackathon.
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS assignee_id TEXT;
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS resolution_notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ;
+UPDATE reports SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE reports ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS reports_hackathon_status_idx ON reports (hackathon_id, status);
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS hackathon_id UUID REFERENCES hackathons(id) ON DELETE CASCADE;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS assignee_id TEXT;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS resolution_notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS resolution TEXT NOT NULL DEFAULT '';
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ;
+UPDATE appeals a SET hackathon_id = s.hackathon_id FROM submissions s WHERE s.id = a.submission_id AND a.hackathon_id IS NULL;
+UPDATE appeals SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE appeals ALTER COLUMN hackathon_id SET NOT NULL;
+ALTER TABLE appeals ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS appeals_hackathon_status_idx ON appeals (hackathon_id, status);
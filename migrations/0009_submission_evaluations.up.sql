@@ -0,0 +1,26 @@
+-- One row per evaluation attempt. Rescoring supersedes the previous attempts
+-- instead of deleting them.
+CREATE TABLE IF NOT EXISTS submission_evaluations (
+    id UUID PRIMARY KEY,
+    submission_id UUID NOT NULL REFERENCES submissions(id) ON DELETE CASCADE,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    job_id TEXT,
+    executor TEXT,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    public_score DOUBLE PRECISION,
+    private_score DOUBLE PRECISION,
+    secondary_scores JSONB NOT NULL DEFAULT '{}'::jsonb,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    superseded_at TIMESTAMPTZ,
+    rescore_reason TEXT NOT NULL DEFAULT '',
+    requested_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (submission_id, attempt)
+);
+
+CREATE INDEX IF NOT EXISTS submission_evaluations_submission_id_idx ON submission_evaluations (submission_id, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE submission_limits DROP COLUMN max_evaluation_attempts;
+ALTER TABLE submission_limits DROP COLUMN evaluation_timeout_seconds;
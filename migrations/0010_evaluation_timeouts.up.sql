@@ -0,0 +1,3 @@
+-- Per-hackathon overrides for the evaluation watchdog.
+ALTER TABLE submission_limits ADD COLUMN IF NOT EXISTS evaluation_timeout_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE submission_limits ADD COLUMN IF NOT EXISTS max_evaluation_attempts INTEGER NOT NULL DEFAULT 0;
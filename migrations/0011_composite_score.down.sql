@@ -0,0 +1,3 @@
+ALTER TABLE leaderboard_entries DROP COLUMN composite_score;
+ALTER TABLE submission_evaluations DROP COLUMN composite_score;
+ALTER TABLE submissions DROP COLUMN composite_score;
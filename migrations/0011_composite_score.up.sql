@@ -0,0 +1,4 @@
+-- Weighted composite over all evaluation metrics.
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS composite_score DOUBLE PRECISION;
+ALTER TABLE submission_evaluations ADD COLUMN IF NOT EXISTS composite_score DOUBLE PRECISION;
+ALTER TABLE leaderboard_entries ADD COLUMN IF NOT EXISTS composite_score DOUBLE PRECISION;
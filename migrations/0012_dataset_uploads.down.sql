@@ -0,0 +1,4 @@
+DROP TABLE dataset_upload_parts;
+DROP TABLE dataset_uploads;
+ALTER TABLE dataset_files DROP COLUMN content_type;
+ALTER TABLE dataset_files DROP COLUMN storage_key;
@@ -0,0 +1,36 @@
+-- Dataset files stored in the blob store and resumable upload sessions.
+ALTER TABLE dataset_files ADD COLUMN IF NOT EXISTS storage_key TEXT;
+ALTER TABLE dataset_files ADD COLUMN IF NOT EXISTS content_type TEXT;
+
+CREATE TABLE IF NOT EXISTS dataset_uploads (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    dataset_id UUID NOT NULL REFERENCES hackathon_datasets(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    file_type TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    content_type TEXT NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL,
+    offset_bytes BIGINT NOT NULL DEFAULT 0,
+    parts INT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    file_id UUID REFERENCES dataset_files(id) ON DELETE SET NULL,
+    created_by TEXT,
+    -- A chunk write or the assembly holds the upload until reserved_until.
+    reservation_id UUID,
+    reserved_until TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS dataset_uploads_dataset_id_idx ON dataset_uploads (dataset_id);
+
+CREATE TABLE IF NOT EXISTS dataset_upload_parts (
+    upload_id UUID NOT NULL REFERENCES dataset_uploads(id) ON DELETE CASCADE,
+    part INT NOT NULL,
+    offset_bytes BIGINT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (upload_id, part)
+);
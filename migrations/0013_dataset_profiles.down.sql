@@ -0,0 +1,2 @@
+DROP TABLE dataset_profiles;
+ALTER TABLE hackathon_datasets DROP COLUMN target_columns;
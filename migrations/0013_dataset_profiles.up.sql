@@ -0,0 +1,32 @@
+-- Profiling jobs for uploaded CSV and Parquet files.
+CREATE TABLE IF NOT EXISTS dataset_profiles (
+    id UUID PRIMARY KEY,
+    dataset_id UUID NOT NULL REFERENCES hackathon_datasets(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL REFERENCES dataset_files(id) ON DELETE CASCADE,
+    format TEXT NOT NULL,
+    status TEXT NOT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    columns JSONB NOT NULL DEFAULT '[]'::jsonb,
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    requested_by TEXT,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS dataset_profiles_file_id_idx ON dataset_profiles (file_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS dataset_profiles_queue_idx ON dataset_profiles (created_at) WHERE status IN ('pending', 'running');
+CREATE UNIQUE INDEX IF NOT EXISTS dataset_profiles_active_file_idx ON dataset_profiles (file_id) WHERE status IN ('pending', 'running');
+
+-- Target columns the profiler marks as targets, moved out of response_schema
+-- (a JSON Schema for submission metadata). Bare names become {"name": ...}.
+ALTER TABLE hackathon_datasets ADD COLUMN IF NOT EXISTS target_columns JSONB NOT NULL DEFAULT '[]'::jsonb;
+UPDATE hackathon_datasets d
+SET target_columns = (
+    SELECT jsonb_agg(CASE WHEN jsonb_typeof(t) = 'string' THEN jsonb_build_object('name', t #>> '{}') ELSE t END)
+    FROM jsonb_array_elements(d.response_schema->'targets') t
+)
+WHERE jsonb_typeof(d.response_schema->'targets') = 'array' AND jsonb_array_length(d.response_schema->'targets') > 0;
@@ -0,0 +1,3 @@
+ALTER TABLE submissions DROP COLUMN dataset_version_id;
+ALTER TABLE rule_versions DROP COLUMN dataset_version_id;
+DROP TABLE dataset_versions;
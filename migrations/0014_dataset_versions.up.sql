@@ -0,0 +1,16 @@
+-- Immutable dataset snapshots referenced by rule versions and submissions.
+CREATE TABLE IF NOT EXISTS dataset_versions (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    dataset_id UUID NOT NULL,
+    version INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    content JSONB NOT NULL,
+    created_by TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (hackathon_id, version)
+);
+
+ALTER TABLE rule_versions ADD COLUMN IF NOT EXISTS dataset_version_id UUID REFERENCES dataset_versions(id);
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS dataset_version_id UUID REFERENCES dataset_versions(id);
@@ -0,0 +1,3 @@
+DROP INDEX evaluation_metrics_type_idx;
+DROP INDEX hackathons_search_idx;
+ALTER TABLE hackathons DROP COLUMN search_vector;
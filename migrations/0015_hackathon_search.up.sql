@@ -0,0 +1,10 @@
+-- Weighted full-text index over hackathon titles (A) and descriptions (B).
+-- HackathonService.Create and Update keep it current.
+ALTER TABLE hackathons ADD COLUMN IF NOT EXISTS search_vector TSVECTOR NOT NULL DEFAULT ''::tsvector;
+
+UPDATE hackathons
+SET search_vector = setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+                    setweight(to_tsvector('english', coalesce(description, '')), 'B');
+
+CREATE INDEX IF NOT EXISTS hackathons_search_idx ON hackathons USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS evaluation_metrics_type_idx ON evaluation_metrics (metric_type, hackathon_id);
@@ -0,0 +1,3 @@
+DROP INDEX hackathons_visibility_idx;
+DROP TABLE hackathon_members;
+DROP TABLE hackathon_invites;
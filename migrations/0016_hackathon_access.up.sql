@@ -0,0 +1,29 @@
+-- Invite codes and the member allowlist of private and invite-only hackathons.
+CREATE TABLE IF NOT EXISTS hackathon_invites (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    code TEXT NOT NULL UNIQUE,
+    max_uses INTEGER NOT NULL DEFAULT 0,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CHECK (max_uses >= 0),
+    CHECK (uses >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS hackathon_invites_hackathon_idx ON hackathon_invites (hackathon_id, created_at);
+
+CREATE TABLE IF NOT EXISTS hackathon_members (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    invite_id UUID REFERENCES hackathon_invites(id) ON DELETE SET NULL,
+    added_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (hackathon_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS hackathon_members_user_idx ON hackathon_members (user_id);
+CREATE INDEX IF NOT EXISTS hackathons_visibility_idx ON hackathons (visibility);
@@ -0,0 +1 @@
+DROP TABLE hackathon_staff;
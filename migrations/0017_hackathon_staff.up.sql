@@ -0,0 +1,19 @@
+-- Per-hackathon role assignments. Existing hackathons get their creator as
+-- owner.
+CREATE TABLE IF NOT EXISTS hackathon_staff (
+    id UUID PRIMARY KEY,
+    hackathon_id UUID NOT NULL REFERENCES hackathons(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'organizer', 'judge', 'evaluator', 'moderator')),
+    assigned_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (hackathon_id, user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS hackathon_staff_user_idx ON hackathon_staff (user_id);
+
+INSERT INTO hackathon_staff (id, hackathon_id, user_id, role, assigned_by, created_at)
+SELECT md5(id::text || ':owner')::uuid, id, created_by, 'owner', created_by, created_at
+FROM hackathons
+WHERE coalesce(created_by, '') <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+// Package migrations embeds the service's SQL migrations; see pkg/migrate for
+// the naming scheme.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,80 @@
+// Package blobstore stores uploaded files behind a small driver interface.
+// The local driver writes to a directory and serves HMAC-signed download
+// links itself; the S3 driver talks to any S3-compatible service (AWS S3,
+// MinIO) and returns presigned URLs.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store is implemented by the blob storage drivers. Keys are slash separated
+// relative paths.
+type Store interface {
+	// Put writes size bytes from r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a download URL for key that stops working after ttl.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures a driver.
+type Config struct {
+	Driver string
+
+	LocalDir      string
+	PublicBaseURL string
+	SigningKey    string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+// New builds the store selected by cfg.Driver ("local" or "s3").
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Driver)) {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicBaseURL, []byte(cfg.SigningKey))
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported blob store driver %q", cfg.Driver)
+	}
+}
+
+// cleanKey rejects empty, absolute and parent-relative keys.
+func cleanKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("%q: %w", key, ErrInvalidKey)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("%q: %w", key, ErrInvalidKey)
+		}
+	}
+	return key, nil
+}
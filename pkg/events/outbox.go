@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TxPublisher is implemented by publishers that can record an event inside the
+// caller's transaction, so the event commits or rolls back with the domain change.
+type TxPublisher interface {
+	Publisher
+	PublishTx(ctx context.Context, tx *sql.Tx, subject string, payload any) error
+}
+
+// OutboxPublisher stores events in the event_outbox table. Delivery to NATS is
+// done asynchronously by an OutboxRelay.
+type OutboxPublisher struct {
+	db *sql.DB
+}
+
+func NewOutboxPublisher(db *sql.DB) (*OutboxPublisher, error) {
+	if db == nil {
+		return nil, errors.New("outbox database is required")
+	}
+	return &OutboxPublisher{db: db}, nil
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, subject string, payload any) error {
+	if p == nil || p.db == nil {
+		return nil
+	}
+	return insertOutboxEvent(ctx, p.db, subject, payload)
+}
+
+func (p *OutboxPublisher) PublishTx(ctx context.Context, tx *sql.Tx, subject string, payload any) error {
+	if p == nil {
+		return nil
+	}
+	if tx == nil {
+		return p.Publish(ctx, subject, payload)
+	}
+	return insertOutboxEvent(ctx, tx, subject, payload)
+}
+
+func (p *OutboxPublisher) Close() {}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertOutboxEvent(ctx context.Context, db execer, subject string, payload any) error {
+	env := NewEnvelope(subject, payload)
+	b, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO event_outbox (id, subject, envelope, attempts, created_at, next_attempt_at)
+		VALUES ($1,$2,$3,0,$4,$4)`,
+		env.ID, subject, b, env.OccurredAt,
+	)
+	return err
+}
+
+func outboxBackoff(attempts int, max time.Duration) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 16 {
+		return max
+	}
+	delay := time.Second << (attempts - 1)
+	if delay > max {
+		return max
+	}
+	return delay
+}
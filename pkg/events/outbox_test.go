@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestNewOutboxPublisher_RequiresDB(t *testing.T) {
+	pub, err := NewOutboxPublisher(nil)
+	if err == nil {
+		t.Fatal("expected error for nil database")
+	}
+	if pub != nil {
+		t.Fatal("publisher must be nil on error")
+	}
+}
+
+func TestOutboxPublisher_NilSafeMethods(t *testing.T) {
+	var pub *OutboxPublisher
+	if err := pub.Publish(context.Background(), "subject", map[string]any{"ok": true}); err != nil {
+		t.Fatalf("nil publisher publish should be no-op, got %v", err)
+	}
+	if err := pub.PublishTx(context.Background(), nil, "subject", nil); err != nil {
+		t.Fatalf("nil publisher publish tx should be no-op, got %v", err)
+	}
+	pub.Close()
+}
+
+func TestNewOutboxRelay_RequiresDependencies(t *testing.T) {
+	if _, err := NewOutboxRelay(nil, &NatsPublisher{}, RelayConfig{}, nil); err == nil {
+		t.Fatal("expected error for nil database")
+	}
+}
+
+func TestNewOutboxRelay_DefaultLease(t *testing.T) {
+	relay, err := NewOutboxRelay(&sql.DB{}, &NatsPublisher{}, RelayConfig{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if relay.cfg.Lease != 30*time.Second {
+		t.Fatalf("want default lease 30s, got %v", relay.cfg.Lease)
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	max := time.Minute
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{7, max},
+		{40, max},
+	}
+	for _, tc := range cases {
+		if got := outboxBackoff(tc.attempts, max); got != tc.want {
+			t.Fatalf("attempts=%d: want %v got %v", tc.attempts, tc.want, got)
+		}
+	}
+}
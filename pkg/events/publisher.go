@@ -10,6 +10,8 @@ import (
 	"github.com/nats-io/nats.go"
 )
 
+const publishTimeout = 5 * time.Second
+
 type Publisher interface {
 	Publish(ctx context.Context, subject string, payload any) error
 	Close()
@@ -64,31 +66,38 @@ func NewNatsPublisher(natsURL string, stream string, subjects []string) (*NatsPu
 	return &NatsPublisher{nc: nc, js: js}, nil
 }
 
-func (p *NatsPublisher) Publish(ctx context.Context, subject string, payload any) error {
-	if p == nil || p.js == nil {
-		return nil
-	}
-	env := Envelope{
+func NewEnvelope(subject string, payload any) Envelope {
+	return Envelope{
 		ID:         uuid.NewString(),
 		Type:       subject,
 		Source:     "hackathon-service",
 		OccurredAt: time.Now().UTC(),
 		Payload:    payload,
 	}
-	b, err := json.Marshal(env)
-	if err != nil {
-		return err
+}
+
+func (p *NatsPublisher) Publish(ctx context.Context, subject string, payload any) error {
+	if p == nil || p.js == nil {
+		return nil
 	}
-	ack, err := p.js.PublishAsync(subject, b)
+	env := NewEnvelope(subject, payload)
+	b, err := json.Marshal(env)
 	if err != nil {
 		return err
 	}
-	if ack != nil {
-		go func() {
-			_ = ack.Ok()
-		}()
+	return p.PublishRaw(ctx, subject, env.ID, b)
+}
+
+// PublishRaw publishes an already encoded envelope and waits for the JetStream
+// ack. The envelope id is used as the message id so redeliveries are deduplicated.
+func (p *NatsPublisher) PublishRaw(ctx context.Context, subject, id string, data []byte) error {
+	if p == nil || p.js == nil {
+		return nil
 	}
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	_, err := p.js.Publish(subject, data, nats.Context(ctx), nats.MsgId(id))
+	return err
 }
 
 func (p *NatsPublisher) Close() {
@@ -0,0 +1,210 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/internal/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// RawPublisher delivers an encoded envelope and only returns nil once the
+// broker has acknowledged it.
+type RawPublisher interface {
+	PublishRaw(ctx context.Context, subject, id string, data []byte) error
+}
+
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxBackoff   time.Duration
+	Retention    time.Duration
+	// Lease is how long a claimed row stays invisible to other relays. A row
+	// whose relay died mid-batch becomes due again once its lease expires.
+	Lease time.Duration
+}
+
+// OutboxRelay moves pending event_outbox rows to NATS. Rows are claimed with a
+// short lease so several replicas can run a relay at the same time, and no
+// database transaction is held open while the broker is being called.
+type OutboxRelay struct {
+	db     *sql.DB
+	target RawPublisher
+	cfg    RelayConfig
+	logger *logrus.Logger
+}
+
+type outboxRow struct {
+	id       string
+	subject  string
+	envelope []byte
+	attempts int
+}
+
+func NewOutboxRelay(db *sql.DB, target RawPublisher, cfg RelayConfig, logger *logrus.Logger) (*OutboxRelay, error) {
+	if db == nil || target == nil {
+		return nil, errors.New("outbox relay requires a database and a target publisher")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 30 * time.Second
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &OutboxRelay{db: db, target: target, cfg: cfg, logger: logger}, nil
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for {
+		for {
+			n, err := r.RelayBatch(ctx)
+			if err != nil {
+				r.logger.WithError(err).Warn("outbox relay batch failed")
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+		if err := r.reportLag(ctx); err != nil {
+			r.logger.WithError(err).Warn("outbox lag query failed")
+		}
+		if time.Since(lastPrune) > time.Hour {
+			if err := r.prune(ctx); err != nil {
+				r.logger.WithError(err).Warn("outbox prune failed")
+			}
+			lastPrune = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publishes up to BatchSize due events and returns how many rows it claimed.
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	batch, err := r.claim(ctx)
+	if err != nil || len(batch) == 0 {
+		return 0, err
+	}
+
+	results := make([]error, len(batch))
+	for i, row := range batch {
+		results[i] = r.target.PublishRaw(ctx, row.subject, row.id, row.envelope)
+	}
+	if err := r.record(ctx, batch, results); err != nil {
+		return 0, err
+	}
+	return len(batch), nil
+}
+
+// claim leases up to BatchSize due rows in a single statement, so the row
+// locks taken by FOR UPDATE SKIP LOCKED are released before publishing.
+func (r *OutboxRelay) claim(ctx context.Context) ([]outboxRow, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH claimed AS (
+			UPDATE event_outbox
+			SET locked_until = NOW() + $2::bigint * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT id FROM event_outbox
+				WHERE delivered_at IS NULL AND next_attempt_at <= NOW()
+					AND (locked_until IS NULL OR locked_until <= NOW())
+				ORDER BY created_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, subject, envelope, attempts, created_at
+		)
+		SELECT id, subject, envelope, attempts FROM claimed ORDER BY created_at`,
+		r.cfg.BatchSize, r.cfg.Lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batch []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		if err := rows.Scan(&row.id, &row.subject, &row.envelope, &row.attempts); err != nil {
+			return nil, err
+		}
+		batch = append(batch, row)
+	}
+	return batch, rows.Err()
+}
+
+// record stores the publish outcome of a claimed batch and releases its lease.
+func (r *OutboxRelay) record(ctx context.Context, batch []outboxRow, results []error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, row := range batch {
+		attempts := row.attempts + 1
+		if err := results[i]; err != nil {
+			metrics.OutboxPublishFailures.Inc()
+			r.logger.WithError(err).WithField("event_id", row.id).Warn("outbox publish failed")
+			next := time.Now().UTC().Add(outboxBackoff(attempts, r.cfg.MaxBackoff))
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE event_outbox
+				SET attempts = $1, last_error = $2, next_attempt_at = $3, locked_until = NULL
+				WHERE id = $4`, attempts, err.Error(), next, row.id); err != nil {
+				return err
+			}
+			continue
+		}
+		metrics.OutboxDelivered.Inc()
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE event_outbox
+			SET attempts = $1, last_error = NULL, delivered_at = NOW(), locked_until = NULL
+			WHERE id = $2`, attempts, row.id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *OutboxRelay) reportLag(ctx context.Context) error {
+	var pending int
+	var lag float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(EXTRACT(EPOCH FROM NOW() - MIN(created_at)), 0)
+		FROM event_outbox
+		WHERE delivered_at IS NULL`).Scan(&pending, &lag)
+	if err != nil {
+		return err
+	}
+	metrics.OutboxPending.Set(float64(pending))
+	metrics.OutboxLag.Set(lag)
+	return nil
+}
+
+func (r *OutboxRelay) prune(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM event_outbox
+		WHERE delivered_at IS NOT NULL AND delivered_at < $1`, time.Now().UTC().Add(-r.cfg.Retention))
+	return err
+}
@@ -0,0 +1,130 @@
+// Package jsonschema validates JSON documents against a JSON Schema.
+//
+// Validation is delegated to github.com/santhosh-tekuri/jsonschema/v5.
+// Documents without a $schema are treated as draft-07. Only local $ref
+// pointers are allowed; the package adapts the library errors to path-level
+// Errors so callers can report them field by field.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	lib "github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const schemaURL = "schema.json"
+
+// Error is a single violation. Path is a JSON pointer into the validated
+// document (or into the schema when compiling); "/" denotes the root.
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type Errors []Error
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, item := range e {
+		parts = append(parts, item.Path+": "+item.Message)
+	}
+	return strings.Join(parts, "; ")
+}
+
+type Schema struct {
+	compiled *lib.Schema
+}
+
+// Compile parses a schema document and checks it against the draft
+// meta-schema. An empty document compiles to a schema that accepts everything.
+func Compile(raw []byte) (*Schema, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return &Schema{}, nil
+	}
+	if !json.Valid(raw) {
+		return nil, Errors{{Path: "/", Message: "schema is not valid JSON"}}
+	}
+
+	c := lib.NewCompiler()
+	c.Draft = lib.Draft7
+	c.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("remote $ref %q is not supported", s)
+	}
+	if err := c.AddResource(schemaURL, bytes.NewReader(raw)); err != nil {
+		return nil, Errors{{Path: "/", Message: "schema is not valid JSON"}}
+	}
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		var verr *lib.ValidationError
+		if errors.As(err, &verr) {
+			return nil, fromValidationError(verr)
+		}
+		var serr *lib.SchemaError
+		if errors.As(err, &serr) && serr.Err != nil {
+			err = serr.Err
+		}
+		return nil, Errors{{Path: "/", Message: strings.TrimPrefix(err.Error(), "jsonschema: ")}}
+	}
+	return &Schema{compiled: compiled}, nil
+}
+
+// Validate checks a raw JSON document. An empty document is treated as null.
+func (s *Schema) Validate(raw []byte) Errors {
+	var doc any
+	if len(bytes.TrimSpace(raw)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return Errors{{Path: "/", Message: "document is not valid JSON"}}
+		}
+	}
+	if s.compiled == nil {
+		return nil
+	}
+	err := s.compiled.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	var verr *lib.ValidationError
+	if errors.As(err, &verr) {
+		return fromValidationError(verr)
+	}
+	return Errors{{Path: "/", Message: err.Error()}}
+}
+
+// fromValidationError flattens the library's error tree to its leaves, which
+// are the violations a caller can act on.
+func fromValidationError(verr *lib.ValidationError) Errors {
+	var errs Errors
+	seen := map[Error]bool{}
+	var walk func(*lib.ValidationError)
+	walk = func(e *lib.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+		item := Error{Path: displayPath(e.InstanceLocation), Message: e.Message}
+		if !seen[item] {
+			seen[item] = true
+			errs = append(errs, item)
+		}
+	}
+	walk(verr)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
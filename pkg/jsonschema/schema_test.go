@@ -0,0 +1,149 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+)
+
+const predictionSchema = `{
+	"type": "object",
+	"required": ["predictions", "model"],
+	"additionalProperties": false,
+	"properties": {
+		"model": {"type": "string", "minLength": 2, "pattern": "^[a-z0-9-]+$"},
+		"threshold": {"type": "number", "minimum": 0, "maximum": 1},
+		"predictions": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"$ref": "#/definitions/prediction"}
+		},
+		"mode": {"enum": ["fast", "full"]}
+	},
+	"definitions": {
+		"prediction": {
+			"type": "object",
+			"required": ["id", "label"],
+			"properties": {
+				"id": {"type": "integer"},
+				"label": {"type": ["string", "null"]}
+			}
+		}
+	}
+}`
+
+func TestCompile_InvalidSchema(t *testing.T) {
+	_, err := Compile([]byte(`{"type": "strng", "properties": {"a": {"minLength": -1}}, "required": "a"}`))
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	paths := map[string]bool{}
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+	for _, want := range []string{"/type", "/properties/a/minLength", "/required"} {
+		if !paths[want] {
+			t.Fatalf("expected error at %s, got %v", want, errs)
+		}
+	}
+}
+
+func TestCompile_UnresolvableRef(t *testing.T) {
+	if _, err := Compile([]byte(`{"$ref": "#/definitions/missing"}`)); err == nil {
+		t.Fatal("expected error for unresolvable $ref")
+	}
+	if _, err := Compile([]byte(`{"$ref": "http://example.com/schema.json"}`)); err == nil {
+		t.Fatal("expected error for remote $ref")
+	}
+}
+
+func TestValidate_Format(t *testing.T) {
+	schema, err := Compile([]byte(`{"type": "string", "format": "date-time"}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if errs := schema.Validate([]byte(`"2026-10-17T10:00:00Z"`)); len(errs) > 0 {
+		t.Fatalf("expected valid date-time, got %v", errs)
+	}
+	if errs := schema.Validate([]byte(`"yesterday"`)); len(errs) == 0 {
+		t.Fatal("expected format violation")
+	}
+}
+
+func TestValidate_Valid(t *testing.T) {
+	schema, err := Compile([]byte(predictionSchema))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	doc := `{"model": "xgb-1", "threshold": 0.5, "mode": "fast", "predictions": [{"id": 1, "label": "cat"}, {"id": 2, "label": null}]}`
+	if errs := schema.Validate([]byte(doc)); len(errs) > 0 {
+		t.Fatalf("expected valid document, got %v", errs)
+	}
+}
+
+func TestValidate_PathLevelErrors(t *testing.T) {
+	schema, err := Compile([]byte(predictionSchema))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	doc := `{"model": "X", "threshold": 2, "mode": "slow", "extra": 1, "predictions": [{"id": 1.5, "label": "a"}, {"label": 3}]}`
+	errs := schema.Validate([]byte(doc))
+
+	// Missing and unexpected properties are reported on the enclosing object.
+	want := []string{
+		"/",
+		"/model",
+		"/threshold",
+		"/mode",
+		"/predictions/0/id",
+		"/predictions/1",
+		"/predictions/1/label",
+	}
+	got := map[string]bool{}
+	for _, e := range errs {
+		got[e.Path] = true
+	}
+	for _, path := range want {
+		if !got[path] {
+			t.Fatalf("expected error at %s, got %v", path, errs)
+		}
+	}
+}
+
+func TestValidate_Combinators(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"oneOf": [{"type": "integer"}, {"type": "number", "multipleOf": 0.5}],
+		"not": {"const": 3}
+	}`))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	cases := map[string]bool{
+		`1.5`: true,  // only the second branch
+		`2`:   false, // both branches match
+		`3`:   false, // excluded by not
+		`0.3`: false, // no branch matches
+	}
+	for doc, valid := range cases {
+		errs := schema.Validate([]byte(doc))
+		if valid && len(errs) > 0 {
+			t.Fatalf("%s: expected valid, got %v", doc, errs)
+		}
+		if !valid && len(errs) == 0 {
+			t.Fatalf("%s: expected errors", doc)
+		}
+	}
+}
+
+func TestValidate_EmptySchemaAcceptsAnything(t *testing.T) {
+	schema, err := Compile(nil)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if errs := schema.Validate([]byte(`{"anything": [1, "two"]}`)); len(errs) > 0 {
+		t.Fatalf("expected valid, got %v", errs)
+	}
+	if errs := schema.Validate([]byte(`{broken`)); len(errs) != 1 || errs[0].Path != "/" {
+		t.Fatalf("expected a single root error for malformed JSON, got %v", errs)
+	}
+}
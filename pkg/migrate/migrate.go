@@ -0,0 +1,421 @@
+// Package migrate applies ordered SQL migrations from an fs.FS, usually one
+// embedded in the binary, and records them in a schema_migrations table.
+//
+// Migrations are files named NNNN_name.up.sql with an optional matching
+// NNNN_name.down.sql. Each one runs in its own transaction while the runner
+// holds a Postgres advisory lock, so replicas starting together apply every
+// migration exactly once.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockKey is the advisory lock held while migrating. It sits next to the
+// leader election keys of the background jobs.
+const LockKey int64 = 7_410_000
+
+var (
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrIrreversible     = errors.New("migration has no down script")
+	ErrUnknownVersion   = errors.New("applied migration is not embedded")
+)
+
+// Migration states reported by Status.
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified"
+	StateUnknown  = "unknown"
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the hex SHA-256 of Up.
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Status describes one migration known to the files, the database or both.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Load reads the migrations in the root of fsys, ordered by version. Files
+// that are not .sql are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		raw, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("version %d used by %q and %q: %w", version, m.Name, name, ErrInvalidMigration)
+		}
+		script := string(raw)
+		if direction == "up" {
+			m.Up = script
+		} else {
+			m.Down = script
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("%s has no up script: %w", m, ErrInvalidMigration)
+		}
+		sum := sha256.Sum256([]byte(m.Up))
+		m.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func parseFileName(file string) (int64, string, string, error) {
+	base := strings.TrimSuffix(file, ".sql")
+	direction := path.Ext(base)
+	if direction != ".up" && direction != ".down" {
+		return 0, "", "", fmt.Errorf("%s: expected .up.sql or .down.sql: %w", file, ErrInvalidMigration)
+	}
+	base = strings.TrimSuffix(base, direction)
+	prefix, name, ok := strings.Cut(base, "_")
+	if !ok || name == "" {
+		return 0, "", "", fmt.Errorf("%s: expected NNNN_name: %w", file, ErrInvalidMigration)
+	}
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("%s: version must be a positive number: %w", file, ErrInvalidMigration)
+	}
+	return version, name, direction[1:], nil
+}
+
+// Migrator runs a fixed set of migrations against one database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	lockKey    int64
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	if db == nil {
+		return nil, errors.New("migrations require a database")
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, lockKey: LockKey}, nil
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Status lists every migration with its state, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := loadApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return statuses(m.migrations, applied), nil
+}
+
+// Up applies every pending migration in version order and returns them. With
+// dryRun it only returns what would be applied.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	if dryRun {
+		applied, err := loadApplied(ctx, m.db)
+		if err != nil {
+			return nil, err
+		}
+		return pendingMigrations(m.migrations, applied)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingMigrations(m.migrations, applied)
+		if err != nil {
+			return err
+		}
+		for _, mig := range pending {
+			if err := runInTx(ctx, conn, mig.Up, `
+				INSERT INTO schema_migrations (version, name, checksum, applied_at)
+				VALUES ($1, $2, $3, $4)`, mig.Version, mig.Name, mig.Checksum, time.Now().UTC()); err != nil {
+				return fmt.Errorf("apply %s: %w", mig, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// them. With dryRun it only returns what would be reverted.
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive: %w", ErrInvalidMigration)
+	}
+	if dryRun {
+		applied, err := loadApplied(ctx, m.db)
+		if err != nil {
+			return nil, err
+		}
+		return revertibleMigrations(m.migrations, applied, steps)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		revert, err := revertibleMigrations(m.migrations, applied, steps)
+		if err != nil {
+			return err
+		}
+		for _, mig := range revert {
+			if err := runInTx(ctx, conn, mig.Down, `
+				DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+				return fmt.Errorf("revert %s: %w", mig, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases whose schema was created before migrations existed.
+func (m *Migrator) Baseline(ctx context.Context, version int64, dryRun bool) ([]Migration, error) {
+	var done []Migration
+	run := func(q querier, record func(Migration) error) error {
+		applied, err := loadApplied(ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if mig.Version > version {
+				break
+			}
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := record(mig); err != nil {
+				return err
+			}
+			done = append(done, mig)
+		}
+		return nil
+	}
+	if dryRun {
+		err := run(m.db, func(Migration) error { return nil })
+		return done, err
+	}
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		return run(conn, func(mig Migration) error {
+			_, err := conn.ExecContext(ctx, `
+				INSERT INTO schema_migrations (version, name, checksum, applied_at)
+				VALUES ($1, $2, $3, $4)`, mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+			return err
+		})
+	})
+	return done, err
+}
+
+// withLock runs fn on a dedicated connection holding the migration lock,
+// waiting for any other replica that is migrating.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, m.lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	// Unlock with a fresh context so a cancelled run still releases the lock.
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, m.lockKey)
+
+	if _, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func runInTx(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// loadApplied returns the recorded migrations by version; none when the
+// table does not exist yet.
+func loadApplied(ctx context.Context, q querier) (map[int64]appliedMigration, error) {
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, err
+	}
+	applied := map[int64]appliedMigration{}
+	if !exists {
+		return applied, nil
+	}
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// pendingMigrations returns the migrations not applied yet. Applied ones
+// whose script changed since are an error: the database no longer matches
+// what the files describe.
+func pendingMigrations(migrations []Migration, applied map[int64]appliedMigration) ([]Migration, error) {
+	var pending []Migration
+	for _, mig := range migrations {
+		a, ok := applied[mig.Version]
+		if !ok {
+			pending = append(pending, mig)
+			continue
+		}
+		if a.checksum != mig.Checksum {
+			return nil, fmt.Errorf("%s: %w", mig, ErrChecksumMismatch)
+		}
+	}
+	return pending, nil
+}
+
+// revertibleMigrations returns the newest steps applied migrations, newest
+// first.
+func revertibleMigrations(migrations []Migration, applied map[int64]appliedMigration, steps int) ([]Migration, error) {
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
+	}
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if len(versions) > steps {
+		versions = versions[:steps]
+	}
+
+	revert := make([]Migration, 0, len(versions))
+	for _, v := range versions {
+		mig, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("version %d (%s): %w", v, applied[v].name, ErrUnknownVersion)
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return nil, fmt.Errorf("%s: %w", mig, ErrIrreversible)
+		}
+		revert = append(revert, mig)
+	}
+	return revert, nil
+}
+
+func statuses(migrations []Migration, applied map[int64]appliedMigration) []Status {
+	out := make([]Status, 0, len(migrations)+len(applied))
+	seen := make(map[int64]bool, len(migrations))
+	for _, mig := range migrations {
+		seen[mig.Version] = true
+		st := Status{Version: mig.Version, Name: mig.Name, State: StatePending}
+		if a, ok := applied[mig.Version]; ok {
+			appliedAt := a.appliedAt
+			st.AppliedAt = &appliedAt
+			st.State = StateApplied
+			if a.checksum != mig.Checksum {
+				st.State = StateModified
+			}
+		}
+		out = append(out, st)
+	}
+	for version, a := range applied {
+		if seen[version] {
+			continue
+		}
+		appliedAt := a.appliedAt
+		out = append(out, Status{Version: version, Name: a.name, State: StateUnknown, AppliedAt: &appliedAt})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
@@ -0,0 +1,123 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DataInCube/hackathon-service/migrations"
+)
+
+func TestLoadOrdersAndPairsScripts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON t (a);")},
+		"0002_create_t.up.sql":    {Data: []byte("CREATE TABLE t (a INT);")},
+		"0002_create_t.down.sql":  {Data: []byte("DROP TABLE t;")},
+		"README.md":               {Data: []byte("ignored")},
+		"embed.go":                {Data: []byte("package x")},
+		"0010_add_index.down.sql": {Data: []byte("DROP INDEX a;")},
+	}
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 10 {
+		t.Fatalf("unexpected migrations: %+v", got)
+	}
+	if got[0].Name != "create_t" || got[0].Down != "DROP TABLE t;" || got[0].String() != "0002_create_t" {
+		t.Fatalf("unexpected first migration: %+v", got[0])
+	}
+	if len(got[0].Checksum) != 64 || got[0].Checksum == got[1].Checksum {
+		t.Fatalf("unexpected checksums: %q %q", got[0].Checksum, got[1].Checksum)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"no direction":  {"0001_a.sql": {Data: []byte("x")}},
+		"no name":       {"0001.up.sql": {Data: []byte("x")}},
+		"bad version":   {"v1_a.up.sql": {Data: []byte("x")}},
+		"zero version":  {"0000_a.up.sql": {Data: []byte("x")}},
+		"down only":     {"0001_a.down.sql": {Data: []byte("x")}},
+		"empty up":      {"0001_a.up.sql": {Data: []byte("  \n")}},
+		"name conflict": {"0001_a.up.sql": {Data: []byte("x")}, "0001_b.down.sql": {Data: []byte("y")}},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); !errors.Is(err, ErrInvalidMigration) {
+			t.Fatalf("%s: expected ErrInvalidMigration, got %v", name, err)
+		}
+	}
+}
+
+func TestPendingAndRevertible(t *testing.T) {
+	migs := []Migration{
+		{Version: 1, Name: "a", Up: "a", Down: "da", Checksum: "c1"},
+		{Version: 2, Name: "b", Up: "b", Checksum: "c2"},
+		{Version: 3, Name: "c", Up: "c", Down: "dc", Checksum: "c3"},
+	}
+	applied := map[int64]appliedMigration{1: {name: "a", checksum: "c1"}}
+
+	pending, err := pendingMigrations(migs, applied)
+	if err != nil || len(pending) != 2 || pending[0].Version != 2 || pending[1].Version != 3 {
+		t.Fatalf("pending = %+v (%v)", pending, err)
+	}
+	if _, err := pendingMigrations(migs, map[int64]appliedMigration{1: {checksum: "edited"}}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("expected ErrChecksumMismatch, got %v", err)
+	}
+
+	applied[2] = appliedMigration{name: "b", checksum: "c2"}
+	applied[3] = appliedMigration{name: "c", checksum: "c3"}
+	revert, err := revertibleMigrations(migs, applied, 1)
+	if err != nil || len(revert) != 1 || revert[0].Version != 3 {
+		t.Fatalf("revert = %+v (%v)", revert, err)
+	}
+	if _, err := revertibleMigrations(migs, applied, 2); !errors.Is(err, ErrIrreversible) {
+		t.Fatalf("expected ErrIrreversible, got %v", err)
+	}
+	applied[7] = appliedMigration{name: "newer"}
+	if _, err := revertibleMigrations(migs, applied, 1); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	migs := []Migration{
+		{Version: 1, Name: "a", Checksum: "c1"},
+		{Version: 2, Name: "b", Checksum: "c2"},
+		{Version: 4, Name: "d", Checksum: "c4"},
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	got := statuses(migs, map[int64]appliedMigration{
+		1: {name: "a", checksum: "c1", appliedAt: at},
+		2: {name: "b", checksum: "old", appliedAt: at},
+		3: {name: "gone", checksum: "c3", appliedAt: at},
+	})
+	want := []Status{
+		{Version: 1, Name: "a", State: StateApplied},
+		{Version: 2, Name: "b", State: StateModified},
+		{Version: 3, Name: "gone", State: StateUnknown},
+		{Version: 4, Name: "d", State: StatePending},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("statuses = %+v", got)
+	}
+	for i := range want {
+		if got[i].Version != want[i].Version || got[i].Name != want[i].Name || got[i].State != want[i].State {
+			t.Fatalf("status %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got[0].AppliedAt == nil || !got[0].AppliedAt.Equal(at) || got[3].AppliedAt != nil {
+		t.Fatalf("unexpected applied_at: %+v", got)
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migs, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(migs) == 0 || migs[0].Version != 1 || migs[0].Down == "" {
+		t.Fatalf("expected a reversible 0001 migration, got %+v", migs)
+	}
+}
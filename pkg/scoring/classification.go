@@ -0,0 +1,417 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func accuracy(s Sample, _ Params) (float64, error) {
+	truth, pred := trimAll(s.Truth), trimAll(s.Pred)
+	var correct int
+	for i := range truth {
+		if truth[i] == pred[i] {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(truth)), nil
+}
+
+// balancedAccuracy averages the recall of every class present in the ground
+// truth.
+func balancedAccuracy(s Sample, _ Params) (float64, error) {
+	truth, pred := trimAll(s.Truth), trimAll(s.Pred)
+	support := map[string]int{}
+	hits := map[string]int{}
+	for i, y := range truth {
+		support[y]++
+		if pred[i] == y {
+			hits[y]++
+		}
+	}
+	var sum float64
+	for label, n := range support {
+		sum += float64(hits[label]) / float64(n)
+	}
+	return sum / float64(len(support)), nil
+}
+
+func precision(s Sample, p Params) (float64, error) {
+	return averagedScore(s, p, func(c counts) float64 { return c.precision() })
+}
+
+func recall(s Sample, p Params) (float64, error) {
+	return averagedScore(s, p, func(c counts) float64 { return c.recall() })
+}
+
+func f1(s Sample, p Params) (float64, error) {
+	return averagedScore(s, p, func(c counts) float64 { return c.fBeta(1) })
+}
+
+func fBeta(s Sample, p Params) (float64, error) {
+	beta := p.Float("beta", 0)
+	if beta <= 0 {
+		return 0, fmt.Errorf("f_beta needs a positive beta: %w", ErrInvalidInput)
+	}
+	return averagedScore(s, p, func(c counts) float64 { return c.fBeta(beta) })
+}
+
+type counts struct{ tp, fp, fn int }
+
+func (c counts) precision() float64 { return ratio(c.tp, c.tp+c.fp) }
+func (c counts) recall() float64    { return ratio(c.tp, c.tp+c.fn) }
+
+func (c counts) fBeta(beta float64) float64 {
+	p, r := c.precision(), c.recall()
+	b2 := beta * beta
+	if b2*p+r == 0 {
+		return 0
+	}
+	return (1 + b2) * p * r / (b2*p + r)
+}
+
+// averagedScore applies score per label and combines the results following
+// the "average" param: binary (positive label only), micro, macro or
+// weighted by support.
+func averagedScore(s Sample, p Params, score func(counts) float64) (float64, error) {
+	truth, pred := trimAll(s.Truth), trimAll(s.Pred)
+	labels := sortLabels(truth, pred)
+	per := make(map[string]*counts, len(labels))
+	support := map[string]int{}
+	for _, l := range labels {
+		per[l] = &counts{}
+	}
+	for i, y := range truth {
+		support[y]++
+		if pred[i] == y {
+			per[y].tp++
+			continue
+		}
+		per[pred[i]].fp++
+		per[y].fn++
+	}
+
+	switch average := p.String("average", "binary"); average {
+	case "binary":
+		pos, err := positiveLabel(labels, p)
+		if err != nil {
+			return 0, err
+		}
+		if c, ok := per[pos]; ok {
+			return score(*c), nil
+		}
+		return 0, nil
+	case "micro":
+		var total counts
+		for _, c := range per {
+			total.tp += c.tp
+			total.fp += c.fp
+			total.fn += c.fn
+		}
+		return score(total), nil
+	case "macro":
+		var sum float64
+		for _, c := range per {
+			sum += score(*c)
+		}
+		return sum / float64(len(per)), nil
+	case "weighted":
+		var sum float64
+		for label, c := range per {
+			sum += score(*c) * float64(support[label])
+		}
+		return sum / float64(len(truth)), nil
+	default:
+		return 0, fmt.Errorf("unsupported average %q: %w", average, ErrInvalidInput)
+	}
+}
+
+// rocAUC computes the binary area under the ROC curve from the rank sum of
+// the positive rows, averaging the ranks of tied scores.
+func rocAUC(s Sample, p Params) (float64, error) {
+	positive, scores, err := binaryScores(s, p)
+	if err != nil {
+		return 0, err
+	}
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] < scores[order[b]] })
+
+	var nPos, nNeg int
+	var rankSum float64
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, idx := range order[start:end] {
+			if positive[idx] {
+				rankSum += rank
+			}
+		}
+		start = end
+	}
+	for _, pos := range positive {
+		if pos {
+			nPos++
+		} else {
+			nNeg++
+		}
+	}
+	if nPos == 0 || nNeg == 0 {
+		return 0, fmt.Errorf("roc_auc needs both classes in the ground truth: %w", ErrInvalidInput)
+	}
+	return (rankSum - float64(nPos*(nPos+1))/2) / float64(nPos*nNeg), nil
+}
+
+// prAUC computes the average precision: the precision at each distinct score
+// threshold weighted by the recall gained there.
+func prAUC(s Sample, p Params) (float64, error) {
+	positive, scores, err := binaryScores(s, p)
+	if err != nil {
+		return 0, err
+	}
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	var nPos int
+	for _, pos := range positive {
+		if pos {
+			nPos++
+		}
+	}
+	if nPos == 0 {
+		return 0, fmt.Errorf("pr_auc needs positive rows in the ground truth: %w", ErrInvalidInput)
+	}
+	var tp, fp int
+	var ap, prevRecall float64
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && scores[order[end]] == scores[order[start]] {
+			if positive[order[end]] {
+				tp++
+			} else {
+				fp++
+			}
+			end++
+		}
+		rec := float64(tp) / float64(nPos)
+		ap += (rec - prevRecall) * float64(tp) / float64(tp+fp)
+		prevRecall = rec
+		start = end
+	}
+	return ap, nil
+}
+
+// logLoss is the binary cross-entropy of the predicted positive-class
+// probabilities, clipped to [eps, 1-eps].
+func logLoss(s Sample, p Params) (float64, error) {
+	positive, probs, err := binaryScores(s, p)
+	if err != nil {
+		return 0, err
+	}
+	eps := p.Float("eps", 1e-15)
+	var sum float64
+	for i, prob := range probs {
+		if prob < 0 || prob > 1 {
+			return 0, fmt.Errorf("row %d: probability %v is outside [0, 1]: %w", i+1, prob, ErrInvalidInput)
+		}
+		prob = math.Min(math.Max(prob, eps), 1-eps)
+		if positive[i] {
+			sum -= math.Log(prob)
+		} else {
+			sum -= math.Log(1 - prob)
+		}
+	}
+	return sum / float64(len(probs)), nil
+}
+
+// matthewsCorrelation uses the multiclass generalization of the MCC, which
+// reduces to the usual formula for two classes.
+func matthewsCorrelation(s Sample, _ Params) (float64, error) {
+	truth, pred := trimAll(s.Truth), trimAll(s.Pred)
+	trueCount := map[string]float64{}
+	predCount := map[string]float64{}
+	var correct float64
+	for i, y := range truth {
+		trueCount[y]++
+		predCount[pred[i]]++
+		if pred[i] == y {
+			correct++
+		}
+	}
+	n := float64(len(truth))
+	var cross, sumP2, sumT2 float64
+	for _, l := range sortLabels(truth, pred) {
+		cross += predCount[l] * trueCount[l]
+		sumP2 += predCount[l] * predCount[l]
+		sumT2 += trueCount[l] * trueCount[l]
+	}
+	den := math.Sqrt(n*n-sumP2) * math.Sqrt(n*n-sumT2)
+	if den == 0 {
+		return 0, nil
+	}
+	return (correct*n - cross) / den, nil
+}
+
+// cohenKappa measures agreement beyond chance. Linear and quadratic weights
+// penalize disagreements by their distance in label order.
+func cohenKappa(s Sample, p Params) (float64, error) {
+	truth, pred := trimAll(s.Truth), trimAll(s.Pred)
+	labels := sortLabels(truth, pred)
+	index := make(map[string]int, len(labels))
+	for i, l := range labels {
+		index[l] = i
+	}
+	k := len(labels)
+	observed := make([][]float64, k)
+	for i := range observed {
+		observed[i] = make([]float64, k)
+	}
+	rows := make([]float64, k)
+	cols := make([]float64, k)
+	for i, y := range truth {
+		observed[index[y]][index[pred[i]]]++
+		rows[index[y]]++
+		cols[index[pred[i]]]++
+	}
+
+	weights := p.String("weights", "none")
+	weight := func(i, j int) float64 {
+		d := math.Abs(float64(i - j))
+		switch weights {
+		case "linear":
+			return d
+		case "quadratic":
+			return d * d
+		default:
+			if i == j {
+				return 0
+			}
+			return 1
+		}
+	}
+	if weights != "none" && weights != "linear" && weights != "quadratic" {
+		return 0, fmt.Errorf("unsupported weights %q: %w", weights, ErrInvalidInput)
+	}
+
+	n := float64(len(truth))
+	var obs, exp float64
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			w := weight(i, j)
+			obs += w * observed[i][j]
+			exp += w * rows[i] * cols[j] / n
+		}
+	}
+	if exp == 0 {
+		if obs == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 1 - obs/exp, nil
+}
+
+// binaryScores marks the positive ground-truth rows and parses the
+// predictions as positive-class scores.
+func binaryScores(s Sample, p Params) ([]bool, []float64, error) {
+	truth := trimAll(s.Truth)
+	labels := sortLabels(truth)
+	if len(labels) > 2 {
+		return nil, nil, fmt.Errorf("only binary ground truth is supported, got %d classes: %w", len(labels), ErrInvalidInput)
+	}
+	pos, err := positiveLabel(labels, p)
+	if err != nil {
+		return nil, nil, err
+	}
+	scores, err := parseNumbers(s.Pred, "prediction")
+	if err != nil {
+		return nil, nil, err
+	}
+	positive := make([]bool, len(truth))
+	for i, y := range truth {
+		positive[i] = y == pos
+	}
+	return positive, scores, nil
+}
+
+// positiveLabel returns the pos_label param, or infers it for 0/1 and
+// false/true labels. Other labels need pos_label: guessing would silently
+// score the wrong class.
+func positiveLabel(labels []string, p Params) (string, error) {
+	if pos := p.String("pos_label", ""); pos != "" {
+		return pos, nil
+	}
+	zeroOne, boolean := true, true
+	for _, l := range labels {
+		zeroOne = zeroOne && (l == "0" || l == "1")
+		boolean = boolean && (strings.EqualFold(l, "false") || strings.EqualFold(l, "true"))
+	}
+	switch {
+	case zeroOne:
+		return "1", nil
+	case boolean:
+		for _, l := range labels {
+			if strings.EqualFold(l, "true") {
+				return l, nil
+			}
+		}
+		return "true", nil
+	}
+	return "", fmt.Errorf("pos_label is required for labels %v: %w", labels, ErrInvalidInput)
+}
+
+// sortLabels returns the distinct labels, ordered numerically when they are
+// all numbers and lexically otherwise.
+func sortLabels(columns ...[]string) []string {
+	seen := map[string]bool{}
+	var labels []string
+	for _, col := range columns {
+		for _, v := range col {
+			if !seen[v] {
+				seen[v] = true
+				labels = append(labels, v)
+			}
+		}
+	}
+	numeric := make(map[string]float64, len(labels))
+	for _, l := range labels {
+		f, err := strconv.ParseFloat(l, 64)
+		if err != nil {
+			numeric = nil
+			break
+		}
+		numeric[l] = f
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if numeric != nil && numeric[labels[i]] != numeric[labels[j]] {
+			return numeric[labels[i]] < numeric[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+	return labels
+}
+
+func trimAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+func ratio(num, den int) float64 {
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
@@ -0,0 +1,47 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Params are the metric params stored on an evaluation metric.
+type Params map[string]any
+
+func (p Params) Float(name string, def float64) float64 {
+	switch v := p[name].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+func (p Params) Int(name string, def int) int {
+	return int(p.Float(name, float64(def)))
+}
+
+func (p Params) String(name, def string) string {
+	switch v := p[name].(type) {
+	case string:
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	case float64, int, json.Number:
+		return fmt.Sprint(v)
+	}
+	return def
+}
+
+func (p Params) Bool(name string, def bool) bool {
+	if v, ok := p[name].(bool); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,121 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+)
+
+// ranking holds the relevance of one group's rows ordered by descending
+// prediction; ties keep their input order.
+type ranking []float64
+
+func rankings(s Sample) ([]ranking, error) {
+	relevance, pred, err := parsePairs(s)
+	if err != nil {
+		return nil, err
+	}
+	var order []string
+	rows := map[string][]int{}
+	for i := range relevance {
+		g := ""
+		if len(s.Group) > 0 {
+			g = s.Group[i]
+		}
+		if _, ok := rows[g]; !ok {
+			order = append(order, g)
+		}
+		rows[g] = append(rows[g], i)
+	}
+	out := make([]ranking, 0, len(order))
+	for _, g := range order {
+		idx := rows[g]
+		sort.SliceStable(idx, func(a, b int) bool { return pred[idx[a]] > pred[idx[b]] })
+		r := make(ranking, len(idx))
+		for i, row := range idx {
+			r[i] = relevance[row]
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func cutoff(n, k int) int {
+	if k > 0 && k < n {
+		return k
+	}
+	return n
+}
+
+// meanAveragePrecision averages, over groups, the precision at each relevant
+// row within the cutoff k. Rows with relevance > 0 are relevant.
+func meanAveragePrecision(s Sample, p Params) (float64, error) {
+	return meanOverRankings(s, func(r ranking) float64 {
+		k := cutoff(len(r), p.Int("k", 0))
+		var relevant int
+		for _, rel := range r {
+			if rel > 0 {
+				relevant++
+			}
+		}
+		if relevant == 0 {
+			return 0
+		}
+		var hits int
+		var sum float64
+		for i, rel := range r[:k] {
+			if rel > 0 {
+				hits++
+				sum += float64(hits) / float64(i+1)
+			}
+		}
+		return sum / float64(min(relevant, k))
+	})
+}
+
+// ndcg uses linear gains and a log2 position discount; groups without any
+// relevant row score 0.
+func ndcg(s Sample, p Params) (float64, error) {
+	return meanOverRankings(s, func(r ranking) float64 {
+		k := cutoff(len(r), p.Int("k", 0))
+		ideal := append(ranking(nil), r...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(ideal)))
+		idcg := dcg(ideal[:k])
+		if idcg == 0 {
+			return 0
+		}
+		return dcg(r[:k]) / idcg
+	})
+}
+
+func dcg(r ranking) float64 {
+	var sum float64
+	for i, rel := range r {
+		sum += rel / math.Log2(float64(i+2))
+	}
+	return sum
+}
+
+// meanReciprocalRank averages the reciprocal rank of the first relevant row
+// of each group within the cutoff k.
+func meanReciprocalRank(s Sample, p Params) (float64, error) {
+	return meanOverRankings(s, func(r ranking) float64 {
+		for i, rel := range r[:cutoff(len(r), p.Int("k", 0))] {
+			if rel > 0 {
+				return 1 / float64(i+1)
+			}
+		}
+		return 0
+	})
+}
+
+func meanOverRankings(s Sample, score func(ranking) float64) (float64, error) {
+	groups, err := rankings(s)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, r := range groups {
+		sum += score(r)
+	}
+	return sum / float64(len(groups)), nil
+}
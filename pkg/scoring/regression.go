@@ -0,0 +1,115 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func meanAbsoluteError(s Sample, _ Params) (float64, error) {
+	return meanOver(s, func(y, p float64) (float64, error) { return math.Abs(y - p), nil })
+}
+
+func meanSquaredError(s Sample, _ Params) (float64, error) {
+	return meanOver(s, func(y, p float64) (float64, error) { return (y - p) * (y - p), nil })
+}
+
+func rootMeanSquaredError(s Sample, p Params) (float64, error) {
+	mse, err := meanSquaredError(s, p)
+	return math.Sqrt(mse), err
+}
+
+func rootMeanSquaredLogError(s Sample, _ Params) (float64, error) {
+	msle, err := meanOver(s, func(y, p float64) (float64, error) {
+		if y < 0 || p < 0 {
+			return 0, fmt.Errorf("rmsle needs non-negative values: %w", ErrInvalidInput)
+		}
+		d := math.Log1p(y) - math.Log1p(p)
+		return d * d, nil
+	})
+	return math.Sqrt(msle), err
+}
+
+func meanAbsolutePercentageError(s Sample, params Params) (float64, error) {
+	eps := params.Float("epsilon", 1e-8)
+	return meanOver(s, func(y, p float64) (float64, error) {
+		return math.Abs(y-p) / math.Max(math.Abs(y), eps), nil
+	})
+}
+
+func symmetricMeanAbsolutePercentageError(s Sample, _ Params) (float64, error) {
+	return meanOver(s, func(y, p float64) (float64, error) {
+		denom := math.Abs(y) + math.Abs(p)
+		if denom == 0 {
+			return 0, nil
+		}
+		return 2 * math.Abs(y-p) / denom, nil
+	})
+}
+
+// r2Score follows the usual convention for constant ground truth: 1 for a
+// perfect prediction and 0 otherwise.
+func r2Score(s Sample, _ Params) (float64, error) {
+	truth, pred, err := parsePairs(s)
+	if err != nil {
+		return 0, err
+	}
+	var mean float64
+	for _, y := range truth {
+		mean += y
+	}
+	mean /= float64(len(truth))
+	var ssRes, ssTot float64
+	for i, y := range truth {
+		ssRes += (y - pred[i]) * (y - pred[i])
+		ssTot += (y - mean) * (y - mean)
+	}
+	if ssTot == 0 {
+		if ssRes == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	return 1 - ssRes/ssTot, nil
+}
+
+func meanOver(s Sample, term func(y, p float64) (float64, error)) (float64, error) {
+	truth, pred, err := parsePairs(s)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i, y := range truth {
+		v, err := term(y, pred[i])
+		if err != nil {
+			return 0, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		sum += v
+	}
+	return sum / float64(len(truth)), nil
+}
+
+func parsePairs(s Sample) ([]float64, []float64, error) {
+	truth, err := parseNumbers(s.Truth, "ground truth")
+	if err != nil {
+		return nil, nil, err
+	}
+	pred, err := parseNumbers(s.Pred, "prediction")
+	if err != nil {
+		return nil, nil, err
+	}
+	return truth, pred, nil
+}
+
+func parseNumbers(values []string, what string) ([]float64, error) {
+	out := make([]float64, len(values))
+	for i, raw := range values {
+		v, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("row %d: %s %q is not a finite number: %w", i+1, what, raw, ErrInvalidInput)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,92 @@
+// Package scoring provides reference implementations of the evaluation
+// metrics so that scores reported by the evaluation service can be checked
+// locally.
+//
+// Values are passed as the strings read from prediction and ground-truth
+// files: regression and ranking metrics parse them as numbers, classification
+// metrics compare them as labels, and roc_auc, pr_auc and log_loss read the
+// prediction as the score of the positive class. Ranking metrics (map, ndcg,
+// mrr) rank the rows of each Group by prediction; without groups all rows
+// form one ranking.
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	ErrUnsupported  = errors.New("unsupported metric type")
+	ErrInvalidInput = errors.New("invalid scoring input")
+)
+
+// Sample holds aligned ground-truth and prediction values. Group is optional
+// and only read by ranking metrics.
+type Sample struct {
+	Truth []string
+	Pred  []string
+	Group []string
+}
+
+type metricFunc func(s Sample, p Params) (float64, error)
+
+var metrics = map[string]metricFunc{
+	"mae":               meanAbsoluteError,
+	"mse":               meanSquaredError,
+	"rmse":              rootMeanSquaredError,
+	"rmsle":             rootMeanSquaredLogError,
+	"mape":              meanAbsolutePercentageError,
+	"smape":             symmetricMeanAbsolutePercentageError,
+	"r2":                r2Score,
+	"accuracy":          accuracy,
+	"balanced_accuracy": balancedAccuracy,
+	"precision":         precision,
+	"recall":            recall,
+	"f1":                f1,
+	"f_beta":            fBeta,
+	"roc_auc":           rocAUC,
+	"pr_auc":            prAUC,
+	"log_loss":          logLoss,
+	"mcc":               matthewsCorrelation,
+	"kappa":             cohenKappa,
+	"map":               meanAveragePrecision,
+	"ndcg":              ndcg,
+	"mrr":               meanReciprocalRank,
+	"wer":               wordErrorRate,
+	"cer":               characterErrorRate,
+}
+
+// Supported reports whether metricType has a reference implementation.
+func Supported(metricType string) bool {
+	_, ok := metrics[metricType]
+	return ok
+}
+
+// Types lists the metric types with a reference implementation, sorted.
+func Types() []string {
+	out := make([]string, 0, len(metrics))
+	for name := range metrics {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Score computes metricType over s using the metric params.
+func Score(metricType string, s Sample, params Params) (float64, error) {
+	fn, ok := metrics[metricType]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", metricType, ErrUnsupported)
+	}
+	if len(s.Truth) == 0 {
+		return 0, fmt.Errorf("no rows to score: %w", ErrInvalidInput)
+	}
+	if len(s.Pred) != len(s.Truth) {
+		return 0, fmt.Errorf("%d predictions for %d ground-truth rows: %w", len(s.Pred), len(s.Truth), ErrInvalidInput)
+	}
+	if len(s.Group) != 0 && len(s.Group) != len(s.Truth) {
+		return 0, fmt.Errorf("%d groups for %d ground-truth rows: %w", len(s.Group), len(s.Truth), ErrInvalidInput)
+	}
+	return fn(s, params)
+}
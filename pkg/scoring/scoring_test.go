@@ -0,0 +1,105 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func sample(truth, pred []string) Sample { return Sample{Truth: truth, Pred: pred} }
+
+func TestScoreReferenceValues(t *testing.T) {
+	regression := sample([]string{"3", "-0.5", "2", "7"}, []string{"2.5", "0.0", "2", "8"})
+	multiclass := sample([]string{"0", "1", "2", "0", "1", "2"}, []string{"0", "2", "1", "0", "0", "1"})
+	binary := sample([]string{"1", "0", "1", "1", "0"}, []string{"1", "1", "1", "0", "0"})
+	probs := sample([]string{"0", "0", "1", "1"}, []string{"0.1", "0.4", "0.35", "0.8"})
+
+	cases := []struct {
+		name       string
+		metricType string
+		sample     Sample
+		params     Params
+		want       float64
+	}{
+		{"mae", "mae", regression, nil, 0.5},
+		{"mse", "mse", regression, nil, 0.375},
+		{"rmse", "rmse", regression, nil, math.Sqrt(0.375)},
+		{"r2", "r2", regression, nil, 0.9486081370449679},
+		{"accuracy", "accuracy", multiclass, nil, 1.0 / 3},
+		{"balanced accuracy", "balanced_accuracy", multiclass, nil, 1.0 / 3},
+		{"binary f1", "f1", binary, nil, 2.0 / 3},
+		{"macro f1", "f1", multiclass, Params{"average": "macro"}, 0.26666666666666666},
+		{"micro f1", "f1", multiclass, Params{"average": "micro"}, 1.0 / 3},
+		{"weighted precision", "precision", multiclass, Params{"average": "weighted"}, 0.2222222222222222},
+		{"f beta", "f_beta", binary, Params{"beta": 2.0}, 2.0 / 3},
+		{"roc auc", "roc_auc", probs, nil, 0.75},
+		{"pr auc", "pr_auc", probs, nil, 0.8333333333333333},
+		{"roc auc pos_label", "roc_auc", sample([]string{"stay", "stay", "churn", "churn"}, []string{"0.1", "0.4", "0.35", "0.8"}), Params{"pos_label": "churn"}, 0.75},
+		{"log loss", "log_loss", sample([]string{"1", "0"}, []string{"0.9", "0.1"}), nil, -math.Log(0.9)},
+		{"mcc", "mcc", sample([]string{"1", "1", "1", "-1"}, []string{"1", "-1", "1", "1"}), nil, -1.0 / 3},
+		{"kappa", "kappa", sample(
+			[]string{"negative", "positive", "negative", "neutral", "positive"},
+			[]string{"negative", "positive", "negative", "neutral", "negative"}), nil, 0.6875},
+		{"ndcg", "ndcg", sample([]string{"10", "0", "0", "1", "5"}, []string{".1", ".2", ".3", "4", "70"}), nil, 0.6956940443813076},
+		{"wer", "wer", sample([]string{"the cat sat"}, []string{"the cat sit on"}), nil, 2.0 / 3},
+		{"cer lowercase", "cer", sample([]string{"Abc"}, []string{"abd"}), Params{"lowercase": true}, 1.0 / 3},
+	}
+	for _, tc := range cases {
+		got, err := Score(tc.metricType, tc.sample, tc.params)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("%s: got=%v want=%v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRankingMetricsUseGroups(t *testing.T) {
+	s := Sample{
+		Truth: []string{"1", "0", "0", "1", "0"},
+		Pred:  []string{"0.9", "0.2", "0.9", "0.8", "0.1"},
+		Group: []string{"q1", "q1", "q2", "q2", "q2"},
+	}
+	got, err := Score("mrr", s, nil)
+	if err != nil || got != 0.75 {
+		t.Fatalf("mrr: got=%v err=%v", got, err)
+	}
+	got, err = Score("map", s, nil)
+	if err != nil || got != 0.75 {
+		t.Fatalf("map: got=%v err=%v", got, err)
+	}
+	got, err = Score("mrr", s, Params{"k": 1.0})
+	if err != nil || got != 0.5 {
+		t.Fatalf("mrr@1: got=%v err=%v", got, err)
+	}
+}
+
+func TestScoreRejectsInvalidInput(t *testing.T) {
+	cases := []struct {
+		metricType string
+		sample     Sample
+	}{
+		{"mae", sample([]string{"1", "2"}, []string{"1"})},
+		{"mae", sample(nil, nil)},
+		{"mae", sample([]string{"1"}, []string{"abc"})},
+		{"rmsle", sample([]string{"-1"}, []string{"1"})},
+		{"roc_auc", sample([]string{"1", "1"}, []string{"0.2", "0.3"})},
+		{"roc_auc", sample([]string{"a", "b", "c"}, []string{"0.2", "0.3", "0.1"})},
+		{"log_loss", sample([]string{"1"}, []string{"1.5"})},
+		{"f1", sample([]string{"a", "b", "c"}, []string{"a", "b", "c"})},
+		{"roc_auc", sample([]string{"stay", "churn"}, []string{"0.2", "0.7"})},
+		{"f1", sample([]string{"cat", "dog"}, []string{"cat", "dog"})},
+	}
+	for i, tc := range cases {
+		if _, err := Score(tc.metricType, tc.sample, nil); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("case %d: expected ErrInvalidInput, got=%v", i, err)
+		}
+	}
+	if _, err := Score("fid", sample([]string{"1"}, []string{"1"}), nil); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got=%v", err)
+	}
+	if !Supported("rmse") || Supported("fid") {
+		t.Fatalf("unexpected Supported result")
+	}
+}
@@ -0,0 +1,57 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// wordErrorRate is the word-level edit distance summed over all rows divided
+// by the number of reference words.
+func wordErrorRate(s Sample, p Params) (float64, error) {
+	return errorRate(s, p, strings.Fields)
+}
+
+// characterErrorRate is the character-level counterpart of wordErrorRate.
+func characterErrorRate(s Sample, p Params) (float64, error) {
+	return errorRate(s, p, func(v string) []string {
+		return strings.Split(strings.TrimSpace(v), "")
+	})
+}
+
+func errorRate(s Sample, p Params, tokenize func(string) []string) (float64, error) {
+	lower := p.Bool("lowercase", false)
+	var edits, total int
+	for i := range s.Truth {
+		ref, hyp := s.Truth[i], s.Pred[i]
+		if lower {
+			ref, hyp = strings.ToLower(ref), strings.ToLower(hyp)
+		}
+		refTokens := tokenize(ref)
+		edits += editDistance(refTokens, tokenize(hyp))
+		total += len(refTokens)
+	}
+	if total == 0 {
+		return 0, fmt.Errorf("ground truth has no reference tokens: %w", ErrInvalidInput)
+	}
+	return float64(edits) / float64(total), nil
+}
+
+func editDistance(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}